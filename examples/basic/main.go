@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
 
 	"agentic-ai-framework/internal/provider"
 	"agentic-ai-framework/internal/runtime"
 )
 
 func main() {
-	p := provider.NewOpenAIChatCompletionsProvider("config.yaml")
+	p, err := provider.NewOpenAIChatCompletionsProvider("config.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	fmt.Println("Provider:", p.Name())
 	fmt.Println("Available Models:")
@@ -20,10 +25,13 @@ func main() {
 
 	fmt.Println("Generating text with", models[0].Name(), "...")
 
-	result := runtime.GenerateText(p, "Hello! How are you?", models[0].Name(), map[string]any{
+	result, err := runtime.GenerateText(context.Background(), p, "Hello! How are you?", models[0].Name(), map[string]any{
 		"temperature": 0.7,
 		"top_p":       0.9,
 	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	fmt.Println("\nGenerated text:")
 	fmt.Println(result.TextContent())
@@ -0,0 +1,174 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"agentic-ai-framework/internal/cost"
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/runtime"
+	"agentic-ai-framework/internal/types"
+)
+
+const DefaultMaxSteps = runtime.DefaultMaxToolIterations
+
+type StepType string
+
+const (
+	StepModelCall   StepType = "model_call"
+	StepToolCall    StepType = "tool_call"
+	StepObservation StepType = "observation"
+	StepFinalAnswer StepType = "final_answer"
+)
+
+type Step struct {
+	Type     StepType
+	Content  string
+	ToolCall types.ToolCall
+	Usage    types.TokenUsage
+	Cost     float64
+	Err      error
+	Duration time.Duration
+}
+
+type Result struct {
+	Output   string
+	Steps    []Step
+	Usage    types.TokenUsage
+	Cost     float64
+	Response types.GenerateTextResult
+}
+
+type Config struct {
+	Name         string
+	Provider     provider.Provider
+	Model        string
+	Instructions string
+	Parameters   map[string]any
+	Tools        []runtime.Tool
+	Memory       Memory
+	MaxSteps     int
+	OnStep       func(Step)
+}
+
+type Agent struct {
+	name         string
+	provider     provider.Provider
+	model        string
+	instructions string
+	parameters   map[string]any
+	tools        *runtime.ToolRegistry
+	memory       Memory
+	maxSteps     int
+	onStep       func(Step)
+}
+
+func NewAgent(cfg Config) (*Agent, error) {
+	if cfg.Provider == nil {
+		return nil, fmt.Errorf("%w: agent provider is required", types.ErrInvalidConfig)
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("%w: agent model is required", types.ErrInvalidConfig)
+	}
+
+	tools := runtime.NewToolRegistry()
+	for _, tool := range cfg.Tools {
+		if err := tools.Register(tool); err != nil {
+			return nil, fmt.Errorf("%w: %v", types.ErrInvalidConfig, err)
+		}
+	}
+
+	maxSteps := cfg.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxSteps
+	}
+
+	return &Agent{
+		name:         cfg.Name,
+		provider:     cfg.Provider,
+		model:        cfg.Model,
+		instructions: cfg.Instructions,
+		parameters:   maps.Clone(cfg.Parameters),
+		tools:        tools,
+		memory:       cfg.Memory,
+		maxSteps:     maxSteps,
+		onStep:       cfg.OnStep,
+	}, nil
+}
+
+func (a *Agent) Name() string {
+	return a.name
+}
+
+func (a *Agent) Model() string {
+	return a.model
+}
+
+func (a *Agent) Memory() Memory {
+	return a.memory
+}
+
+func (a *Agent) Run(ctx context.Context, input string) (Result, error) {
+	return a.RunMessage(ctx, types.NewUserMessage(input))
+}
+
+func (a *Agent) RunMessage(ctx context.Context, input types.Message) (Result, error) {
+	if a.name != "" {
+		ctx = cost.WithScope(ctx, cost.Scope{Agent: a.name})
+	}
+
+	var history []types.Message
+	if a.memory != nil {
+		var err error
+		history, err = a.memory.Messages(ctx)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to load memory: %w", err)
+		}
+	}
+
+	var messages []types.Message
+	if a.instructions != "" {
+		messages = append(messages, types.NewSystemMessage(a.instructions))
+	}
+	messages = append(messages, history...)
+	turnStart := len(messages)
+	messages = append(messages, input)
+
+	var result Result
+	transcript, response, err := runtime.RunToolLoop(ctx, a.provider, a.tools, messages, a.model, a.parameters, a.maxSteps, func(step runtime.ToolLoopStep) {
+		switch step.Kind {
+		case runtime.ToolLoopModelCall:
+			if step.Err == nil {
+				result.Usage = result.Usage.Add(step.Response.Usage())
+				result.Cost += step.Response.Cost()
+			}
+			a.record(&result, Step{Type: StepModelCall, Content: step.Response.TextContent(), Usage: step.Response.Usage(), Cost: step.Response.Cost(), Err: step.Err, Duration: step.Duration})
+		case runtime.ToolLoopToolCall:
+			a.record(&result, Step{Type: StepToolCall, Content: step.ToolCall.Arguments(), ToolCall: step.ToolCall})
+		case runtime.ToolLoopObservation:
+			a.record(&result, Step{Type: StepObservation, Content: step.Output, ToolCall: step.ToolCall, Err: step.Err, Duration: step.Duration})
+		}
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Output = response.TextContent()
+	result.Response = response
+	a.record(&result, Step{Type: StepFinalAnswer, Content: result.Output})
+	if a.memory != nil {
+		if err := a.memory.Append(ctx, transcript[turnStart:]...); err != nil {
+			return result, fmt.Errorf("failed to save memory: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func (a *Agent) record(result *Result, step Step) {
+	result.Steps = append(result.Steps, step)
+	if a.onStep != nil {
+		a.onStep(step)
+	}
+}
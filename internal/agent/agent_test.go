@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"agentic-ai-framework/internal/cost"
+	"agentic-ai-framework/internal/providertest"
+	"agentic-ai-framework/internal/runtime"
+	"agentic-ai-framework/internal/types"
+)
+
+func weatherTool() runtime.Tool {
+	return runtime.NewTypedTool("get_weather", "Get the weather", nil, func(ctx context.Context, args struct {
+		City string `json:"city"`
+	}) (string, error) {
+		if args.City == "" {
+			return "", errors.New("city is required")
+		}
+		return "sunny in " + args.City, nil
+	})
+}
+
+func TestNewAgent(t *testing.T) {
+	if _, err := NewAgent(Config{Model: "gpt-4.1"}); !errors.Is(err, types.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig without a provider, got %v", err)
+	}
+	if _, err := NewAgent(Config{Provider: providertest.New()}); !errors.Is(err, types.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig without a model, got %v", err)
+	}
+	_, err := NewAgent(Config{Provider: providertest.New(), Model: "gpt-4.1", Tools: []runtime.Tool{weatherTool(), weatherTool()}})
+	if !errors.Is(err, types.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig for duplicate tools, got %v", err)
+	}
+}
+
+func TestAgentRun(t *testing.T) {
+	t.Run("reason-act loop with transcript", func(t *testing.T) {
+		p := providertest.New(
+			providertest.ToolCallResponse(
+				types.NewToolCall("call_1", "get_weather", `{"city":"Paris"}`),
+				types.NewToolCall("call_2", "get_weather", `{}`),
+			).WithText("Let me check.").WithUsage(10, 5),
+			providertest.TextResponse("It is sunny in Paris.").WithUsage(20, 6),
+		)
+		var streamed []StepType
+		a, err := NewAgent(Config{
+			Provider:     p,
+			Model:        "gpt-4.1",
+			Instructions: "You are a weather assistant.",
+			Parameters:   map[string]any{"temperature": 0.2},
+			Tools:        []runtime.Tool{weatherTool()},
+			OnStep:       func(step Step) { streamed = append(streamed, step.Type) },
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := a.Run(context.Background(), "Weather in Paris?")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.Output != "It is sunny in Paris." {
+			t.Errorf("unexpected output: %s", result.Output)
+		}
+		if result.Usage.TotalTokens() != 41 || result.Response.Usage().TotalTokens() != 41 {
+			t.Errorf("expected aggregated usage of 41 tokens, got %d", result.Usage.TotalTokens())
+		}
+		expected := []StepType{StepModelCall, StepToolCall, StepObservation, StepToolCall, StepObservation, StepModelCall, StepFinalAnswer}
+		if len(result.Steps) != len(expected) || len(streamed) != len(expected) {
+			t.Fatalf("expected %d steps, got %d (streamed %d)", len(expected), len(result.Steps), len(streamed))
+		}
+		for i, stepType := range expected {
+			if result.Steps[i].Type != stepType || streamed[i] != stepType {
+				t.Errorf("step %d: expected %s, got %s", i, stepType, result.Steps[i].Type)
+			}
+		}
+		if result.Steps[2].Content != "sunny in Paris" || result.Steps[4].Err == nil {
+			t.Errorf("unexpected observations: %+v, %+v", result.Steps[2], result.Steps[4])
+		}
+
+		requests := p.Requests()
+		first := requests[0]
+		if first.Messages[0].Role() != types.RoleSystem || first.Messages[0].Content() != "You are a weather assistant." {
+			t.Errorf("expected instructions as the first message, got %+v", first.Messages[0])
+		}
+		if len(first.Tools) != 1 || first.Tools[0].Name() != "get_weather" || first.Parameters["temperature"] != 0.2 {
+			t.Errorf("expected tools and default parameters to be sent, got %v %v", first.Tools, first.Parameters)
+		}
+		second := requests[1].Messages
+		if len(second) != 5 || second[3].ToolCallID() != "call_1" || second[4].Content() != "error: city is required" {
+			t.Errorf("unexpected second request: %+v", second)
+		}
+	})
+
+	t.Run("memory carries history across runs", func(t *testing.T) {
+		p := providertest.New(
+			providertest.TextResponse("Hi Ada!").WithUsage(1, 1),
+			providertest.TextResponse("Your name is Ada.").WithUsage(1, 1),
+		)
+		memory := NewBufferMemory()
+		a, _ := NewAgent(Config{Provider: p, Model: "gpt-4.1", Instructions: "Be friendly.", Memory: memory})
+
+		if _, err := a.Run(context.Background(), "I'm Ada."); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := a.Run(context.Background(), "What's my name?"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		second := p.Requests()[1].Messages
+		if len(second) != 4 || second[1].Content() != "I'm Ada." || second[2].Content() != "Hi Ada!" {
+			t.Errorf("expected previous turn to be replayed, got %+v", second)
+		}
+		history, _ := memory.Messages(context.Background())
+		if len(history) != 4 || history[0].Role() == types.RoleSystem {
+			t.Errorf("expected 4 stored messages without instructions, got %+v", history)
+		}
+	})
+
+	t.Run("cost is attributed to the agent", func(t *testing.T) {
+		p := providertest.New(
+			providertest.ToolCallResponse(types.NewToolCall("call_1", "get_weather", `{"city":"Oslo"}`)).WithUsage(1000, 0),
+			providertest.TextResponse("Snowy.").WithUsage(1000, 0),
+		)
+		tracker := cost.NewTracker(cost.PriceTable{"gpt-4.1": {Input: 2}})
+		a, _ := NewAgent(Config{Name: "weather", Provider: cost.NewMeteredProvider(p, tracker), Model: "gpt-4.1", Tools: []runtime.Tool{weatherTool()}})
+
+		ctx := cost.WithScope(context.Background(), cost.Scope{Tenant: "acme"})
+		result, err := a.Run(ctx, "Weather in Oslo?")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Cost != 0.004 || result.Response.Cost() != 0.004 || result.Steps[0].Cost != 0.002 {
+			t.Errorf("expected run cost of 0.004, got %f", result.Cost)
+		}
+		if spend := tracker.Spend(cost.DimensionAgent, "weather"); spend.Calls != 2 {
+			t.Errorf("expected calls attributed to the agent, got %+v", spend)
+		}
+		if spend := tracker.Spend(cost.DimensionTenant, "acme"); spend.Calls != 2 {
+			t.Errorf("expected caller scope to be kept, got %+v", spend)
+		}
+	})
+
+	t.Run("step limit", func(t *testing.T) {
+		call := providertest.ToolCallResponse(types.NewToolCall("call_1", "get_weather", `{"city":"Rome"}`)).WithUsage(1, 1)
+		p := providertest.New(call, call)
+		a, _ := NewAgent(Config{Provider: p, Model: "gpt-4.1", Tools: []runtime.Tool{weatherTool()}, MaxSteps: 2})
+
+		result, err := a.Run(context.Background(), "Weather?")
+		if !errors.Is(err, runtime.ErrMaxToolIterations) {
+			t.Errorf("expected ErrMaxToolIterations, got %v", err)
+		}
+		if result.Usage.TotalTokens() != 4 || len(result.Steps) != 6 {
+			t.Errorf("expected partial transcript with usage, got %d tokens and %d steps", result.Usage.TotalTokens(), len(result.Steps))
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		memory := NewBufferMemory()
+		a, _ := NewAgent(Config{Provider: providertest.New(providertest.ErrorResponse(errors.New("boom"))), Model: "gpt-4.1", Memory: memory})
+
+		result, err := a.Run(context.Background(), "Hello")
+		if err == nil || len(result.Steps) != 1 || result.Steps[0].Err == nil {
+			t.Errorf("expected error recorded in the transcript, got %v %+v", err, result.Steps)
+		}
+		if history, _ := memory.Messages(context.Background()); len(history) != 0 {
+			t.Errorf("expected failed turn not to be stored, got %+v", history)
+		}
+	})
+}
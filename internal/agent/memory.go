@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type Memory interface {
+	Messages(ctx context.Context) ([]types.Message, error)
+	Append(ctx context.Context, messages ...types.Message) error
+}
+
+type BufferMemory struct {
+	mu       sync.RWMutex
+	messages []types.Message
+}
+
+func NewBufferMemory() *BufferMemory {
+	return &BufferMemory{}
+}
+
+func (m *BufferMemory) Messages(ctx context.Context) ([]types.Message, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]types.Message(nil), m.messages...), nil
+}
+
+func (m *BufferMemory) Append(ctx context.Context, messages ...types.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, messages...)
+	return nil
+}
+
+func (m *BufferMemory) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
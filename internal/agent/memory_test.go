@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"agentic-ai-framework/internal/memory"
+	"agentic-ai-framework/internal/types"
+)
+
+func TestMemoryImplementations(t *testing.T) {
+	var _ Memory = &BufferMemory{}
+	var _ Memory = &memory.Conversation{}
+}
+
+func TestBufferMemory(t *testing.T) {
+	memory := NewBufferMemory()
+	memory.Append(context.Background(), types.NewUserMessage("one"), types.NewAssistantMessage("two"))
+
+	messages, _ := memory.Messages(context.Background())
+	messages[0] = types.NewUserMessage("changed")
+	stored, _ := memory.Messages(context.Background())
+	if len(stored) != 2 || stored[0].Content() != "one" {
+		t.Errorf("expected stored messages to be isolated from callers, got %+v", stored)
+	}
+
+	memory.Clear()
+	if stored, _ := memory.Messages(context.Background()); len(stored) != 0 {
+		t.Errorf("expected empty memory after Clear, got %+v", stored)
+	}
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"agentic-ai-framework/internal/middleware"
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/strategy"
+	"agentic-ai-framework/internal/types"
+)
+
+type Entry struct {
+	Result    types.GenerateTextResult
+	ExpiresAt time.Time
+}
+
+func (e Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+type Store interface {
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	Set(ctx context.Context, key string, entry Entry) error
+	Delete(ctx context.Context, key string) error
+}
+
+type Options struct {
+	TTL                   time.Duration
+	AllowNonDeterministic bool
+}
+
+type Stats struct {
+	Hits   int64
+	Misses int64
+	Skips  int64
+	Errors int64
+}
+
+type Cache struct {
+	store   Store
+	options Options
+	now     func() time.Time
+	hits    atomic.Int64
+	misses  atomic.Int64
+	skips   atomic.Int64
+	errors  atomic.Int64
+}
+
+func New(store Store, options Options) *Cache {
+	return &Cache{store: store, options: options, now: time.Now}
+}
+
+func (c *Cache) Stats() Stats {
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Skips: c.skips.Load(), Errors: c.errors.Load()}
+}
+
+func (c *Cache) Middleware() middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+			if bypassed(ctx) || (!c.options.AllowNonDeterministic && !Deterministic(request.Parameters)) {
+				c.skips.Add(1)
+				return next(ctx, request)
+			}
+
+			key, err := Key(request)
+			if err != nil {
+				c.skips.Add(1)
+				return next(ctx, request)
+			}
+			entry, found, err := c.store.Get(ctx, key)
+			switch {
+			case err != nil:
+				c.errors.Add(1)
+			case found && entry.Expired(c.now()):
+				c.store.Delete(ctx, key)
+			case found:
+				c.hits.Add(1)
+				return cacheable(entry.Result).
+					WithUsage(types.NewTokenUsage(0, 0, 0)).
+					WithCacheHit(true), nil
+			}
+
+			c.misses.Add(1)
+			result, err := next(ctx, request)
+			if err != nil {
+				return result, err
+			}
+			entry = Entry{Result: cacheable(result)}
+			if c.options.TTL > 0 {
+				entry.ExpiresAt = c.now().Add(c.options.TTL)
+			}
+			if err := c.store.Set(ctx, key, entry); err != nil {
+				c.errors.Add(1)
+			}
+			return result, nil
+		}
+	}
+}
+
+func Key(request middleware.Request) (string, error) {
+	parameters := provider.NormalizeRequestParameters(request.AvailableParameters, request.Parameters)
+	body := strategy.BuildChatCompletionsRequestBody(provider.NewChatCompletionsRequest(request.Messages, request.Tools, request.Model, parameters))
+	data, err := json.Marshal(map[string]any{
+		"provider": request.Provider,
+		"base_url": request.BaseURL,
+		"request":  body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func Deterministic(requestParameters map[string]any) bool {
+	if n, set := requestParameters["n"]; set && !isNumber(n, 1) {
+		return false
+	}
+	temperature, set := requestParameters["temperature"]
+	return set && isNumber(temperature, 0)
+}
+
+func cacheable(result types.GenerateTextResult) types.GenerateTextResult {
+	return result.
+		WithAttempts(0).
+		WithEstimatedPromptTokens(0).
+		WithCost(0).
+		WithRateLimit(types.RateLimit{}).
+		WithCacheHit(false)
+}
+
+func isNumber(value any, expected float64) bool {
+	number, ok := types.ToFloat(value)
+	return ok && number == expected
+}
+
+type bypassKey struct{}
+
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+func bypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
@@ -0,0 +1,246 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/middleware"
+	"agentic-ai-framework/internal/types"
+)
+
+type countingHandler struct {
+	calls int
+	err   error
+}
+
+func (h *countingHandler) handle(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+	h.calls++
+	if h.err != nil {
+		return types.GenerateTextResult{}, h.err
+	}
+	return types.NewGenerateTextResult("Paris", types.NewTokenUsage(10, 2, 12)).WithAttempts(1).WithResponseID("resp_1"), nil
+}
+
+func testRequest(params map[string]any) middleware.Request {
+	return middleware.Request{
+		Provider:   "OpenAI Chat Completions",
+		Model:      "gpt-4.1",
+		Messages:   []types.Message{types.NewUserMessage("Capital of France?")},
+		Parameters: params,
+	}
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("serves repeated requests from the cache", func(t *testing.T) {
+		handler := &countingHandler{}
+		c := New(NewLRUStore(10), Options{})
+		next := c.Middleware()(handler.handle)
+
+		first, err := next(ctx, testRequest(map[string]any{"temperature": 0.0, "top_p": 1.0}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := next(ctx, testRequest(map[string]any{"top_p": 1.0, "temperature": 0.0}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if handler.calls != 1 {
+			t.Errorf("expected one provider call, got %d", handler.calls)
+		}
+		if first.CacheHit() || first.Usage().TotalTokens() != 12 {
+			t.Errorf("expected first result to be a billed miss, got %+v", first)
+		}
+		if !second.CacheHit() || second.Usage().TotalTokens() != 0 || second.Attempts() != 0 || second.TextContent() != "Paris" || second.ResponseID() != "resp_1" {
+			t.Errorf("expected second result to be an unbilled hit, got %+v", second)
+		}
+		if stats := c.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("hits carry no per-call metadata", func(t *testing.T) {
+		rateLimit := types.NewRateLimit(types.NewRateLimitWindow(500, 499, time.Second), types.RateLimitWindow{})
+		handle := func(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+			return types.NewGenerateTextResult("Paris", types.NewTokenUsage(10, 2, 12)).
+				WithAttempts(2).
+				WithCost(0.25).
+				WithEstimatedPromptTokens(9).
+				WithRateLimit(rateLimit), nil
+		}
+		disk, err := NewDiskStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var hits []types.GenerateTextResult
+		for _, store := range []Store{NewLRUStore(10), disk} {
+			next := New(store, Options{}).Middleware()(handle)
+			first, _ := next(ctx, testRequest(map[string]any{"temperature": 0}))
+			if first.Cost() != 0.25 || first.Attempts() != 2 {
+				t.Errorf("expected the miss to keep its metadata, got %+v", first)
+			}
+			hit, _ := next(ctx, testRequest(map[string]any{"temperature": 0}))
+			if hit.Cost() != 0 || hit.EstimatedPromptTokens() != 0 || hit.Attempts() != 0 || hit.RateLimit().Known() || hit.Usage().TotalTokens() != 0 {
+				t.Errorf("expected per-call metadata to be cleared on a hit, got %+v", hit)
+			}
+			hits = append(hits, hit)
+		}
+		if hits[0].TextContent() != hits[1].TextContent() || hits[0].CacheHit() != hits[1].CacheHit() {
+			t.Errorf("expected both stores to return the same hit, got %+v and %+v", hits[0], hits[1])
+		}
+	})
+
+	t.Run("expires entries after the ttl", func(t *testing.T) {
+		handler := &countingHandler{}
+		store := NewLRUStore(10)
+		c := New(store, Options{TTL: time.Minute})
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		c.now = func() time.Time { return now }
+		next := c.Middleware()(handler.handle)
+
+		next(ctx, testRequest(map[string]any{"temperature": 0}))
+		now = now.Add(59 * time.Second)
+		next(ctx, testRequest(map[string]any{"temperature": 0}))
+		now = now.Add(time.Second)
+		next(ctx, testRequest(map[string]any{"temperature": 0}))
+
+		if handler.calls != 2 {
+			t.Errorf("expected a second provider call after expiry, got %d", handler.calls)
+		}
+	})
+
+	t.Run("skips non-deterministic requests by default", func(t *testing.T) {
+		handler := &countingHandler{}
+		c := New(NewLRUStore(10), Options{})
+		next := c.Middleware()(handler.handle)
+
+		next(ctx, testRequest(map[string]any{"temperature": 0.7}))
+		next(ctx, testRequest(map[string]any{"temperature": 0.7}))
+		next(ctx, testRequest(nil))
+		if handler.calls != 3 || c.Stats().Skips != 3 {
+			t.Errorf("expected non-deterministic requests to bypass the cache, got %d calls", handler.calls)
+		}
+
+		next(ctx, testRequest(map[string]any{"temperature": 0}))
+		next(ctx, testRequest(map[string]any{"temperature": 0}))
+		if handler.calls != 4 {
+			t.Errorf("expected deterministic requests to be cached, got %d calls", handler.calls)
+		}
+	})
+
+	t.Run("caches sampled requests when allowed", func(t *testing.T) {
+		handler := &countingHandler{}
+		c := New(NewLRUStore(10), Options{AllowNonDeterministic: true})
+		next := c.Middleware()(handler.handle)
+
+		next(ctx, testRequest(map[string]any{"temperature": 0.7}))
+		next(ctx, testRequest(map[string]any{"temperature": 0.7}))
+		if handler.calls != 1 || c.Stats().Skips != 0 {
+			t.Errorf("expected the opt-out to cache sampled requests, got %d calls", handler.calls)
+		}
+	})
+
+	t.Run("bypass and errors", func(t *testing.T) {
+		handler := &countingHandler{err: errors.New("boom")}
+		store := NewLRUStore(10)
+		next := New(store, Options{}).Middleware()(handler.handle)
+
+		if _, err := next(ctx, testRequest(map[string]any{"temperature": 0})); err == nil {
+			t.Fatal("expected error")
+		}
+		if store.Len() != 0 {
+			t.Error("expected errors not to be cached")
+		}
+
+		handler.err = nil
+		next(WithBypass(ctx), testRequest(map[string]any{"temperature": 0}))
+		next(WithBypass(ctx), testRequest(map[string]any{"temperature": 0}))
+		if handler.calls != 3 || store.Len() != 0 {
+			t.Errorf("expected bypassed requests to skip the cache, got %d calls", handler.calls)
+		}
+	})
+}
+
+func TestKey(t *testing.T) {
+	base, err := Key(testRequest(map[string]any{"temperature": 0.0, "seed": 7}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(base) != 64 {
+		t.Errorf("expected hex sha256 key, got %q", base)
+	}
+
+	same, _ := Key(testRequest(map[string]any{"seed": 7, "temperature": 0.0}))
+	if same != base {
+		t.Error("expected parameter order not to change the key")
+	}
+
+	maxTokens := types.NewParameter("max_completion_tokens", types.ParameterInteger).WithAliases("max_tokens")
+	aliased := testRequest(map[string]any{"temperature": 0.0, "max_tokens": 100})
+	aliased.AvailableParameters = []types.Parameter{maxTokens}
+	canonical := testRequest(map[string]any{"temperature": 0.0, "max_completion_tokens": 100})
+	canonical.AvailableParameters = []types.Parameter{maxTokens}
+	aliasedKey, _ := Key(aliased)
+	canonicalKey, _ := Key(canonical)
+	if aliasedKey != canonicalKey {
+		t.Error("expected parameter aliases to share a key")
+	}
+
+	changed := []middleware.Request{
+		testRequest(map[string]any{"temperature": 0.1, "seed": 7}),
+		func() middleware.Request {
+			r := testRequest(map[string]any{"temperature": 0.0, "seed": 7})
+			r.Model = "gpt-5"
+			return r
+		}(),
+		func() middleware.Request {
+			r := testRequest(map[string]any{"temperature": 0.0, "seed": 7})
+			r.Provider = "groq"
+			return r
+		}(),
+		func() middleware.Request {
+			r := testRequest(map[string]any{"temperature": 0.0, "seed": 7})
+			r.BaseURL = "https://api.groq.com/openai/v1"
+			return r
+		}(),
+		func() middleware.Request {
+			r := testRequest(map[string]any{"temperature": 0.0, "seed": 7})
+			r.Tools = []types.ToolDefinition{types.NewToolDefinition("lookup", "", nil)}
+			return r
+		}(),
+	}
+	for i, request := range changed {
+		if key, _ := Key(request); key == base {
+			t.Errorf("request %d: expected a different key", i)
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	cases := []struct {
+		params   map[string]any
+		expected bool
+	}{
+		{nil, false},
+		{map[string]any{"temperature": 0}, true},
+		{map[string]any{"temperature": 0.0, "n": 1}, true},
+		{map[string]any{"temperature": 0.0, "n": 3}, false},
+		{map[string]any{"temperature": 0.5}, false},
+		{map[string]any{"temperature": 0.5, "seed": 42}, false},
+		{map[string]any{"temperature": 0, "seed": 42}, true},
+		{map[string]any{"temperature": float32(0)}, true},
+		{map[string]any{"temperature": 0, "n": int32(1)}, true},
+		{map[string]any{"temperature": uint8(0), "n": int32(2)}, false},
+		{map[string]any{"temperature": "0"}, false},
+	}
+	for _, c := range cases {
+		if got := Deterministic(c.params); got != c.expected {
+			t.Errorf("%v: expected %t, got %t", c.params, c.expected, got)
+		}
+	}
+}
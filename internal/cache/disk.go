@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type DiskStore struct {
+	dir string
+}
+
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+func (s *DiskStore) Get(ctx context.Context, key string) (Entry, bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("failed to read cache entry %s: %w", key, err)
+	}
+	var stored storedEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to decode cache entry %s: %w", key, err)
+	}
+	return Entry{Result: stored.Result.decode(), ExpiresAt: stored.ExpiresAt}, true, nil
+}
+
+func (s *DiskStore) Set(ctx context.Context, key string, entry Entry) error {
+	data, err := json.Marshal(storedEntry{Result: encodeResult(entry.Result), ExpiresAt: entry.ExpiresAt})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, key+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *DiskStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete cache entry %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *DiskStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+type storedEntry struct {
+	Result    storedResult `json:"result"`
+	ExpiresAt time.Time    `json:"expires_at,omitzero"`
+}
+
+type storedResult struct {
+	Text              string           `json:"text"`
+	PromptTokens      int              `json:"prompt_tokens"`
+	CompletionTokens  int              `json:"completion_tokens"`
+	TotalTokens       int              `json:"total_tokens"`
+	CachedTokens      int              `json:"cached_tokens,omitempty"`
+	ReasoningTokens   int              `json:"reasoning_tokens,omitempty"`
+	ToolCalls         []storedToolCall `json:"tool_calls,omitempty"`
+	FinishReason      string           `json:"finish_reason,omitempty"`
+	ResponseID        string           `json:"response_id,omitempty"`
+	Model             string           `json:"model,omitempty"`
+	SystemFingerprint string           `json:"system_fingerprint,omitempty"`
+	Created           time.Time        `json:"created,omitzero"`
+	Refusal           string           `json:"refusal,omitempty"`
+	Choices           []storedChoice   `json:"choices,omitempty"`
+	Raw               json.RawMessage  `json:"raw,omitempty"`
+}
+
+type storedToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type storedChoice struct {
+	Index        int              `json:"index"`
+	Text         string           `json:"text"`
+	FinishReason string           `json:"finish_reason,omitempty"`
+	ToolCalls    []storedToolCall `json:"tool_calls,omitempty"`
+	Refusal      string           `json:"refusal,omitempty"`
+}
+
+func encodeResult(result types.GenerateTextResult) storedResult {
+	usage := result.Usage()
+	stored := storedResult{
+		Text:              result.TextContent(),
+		PromptTokens:      usage.PromptTokens(),
+		CompletionTokens:  usage.CompletionTokens(),
+		TotalTokens:       usage.TotalTokens(),
+		CachedTokens:      usage.CachedTokens(),
+		ReasoningTokens:   usage.ReasoningTokens(),
+		ToolCalls:         encodeToolCalls(result.ToolCalls()),
+		FinishReason:      result.FinishReason(),
+		ResponseID:        result.ResponseID(),
+		Model:             result.Model(),
+		SystemFingerprint: result.SystemFingerprint(),
+		Created:           result.Created(),
+		Refusal:           result.Refusal(),
+		Raw:               result.RawResponse(),
+	}
+	for _, choice := range result.Choices() {
+		stored.Choices = append(stored.Choices, storedChoice{
+			Index:        choice.Index(),
+			Text:         choice.TextContent(),
+			FinishReason: choice.FinishReason(),
+			ToolCalls:    encodeToolCalls(choice.ToolCalls()),
+			Refusal:      choice.Refusal(),
+		})
+	}
+	return stored
+}
+
+func (s storedResult) decode() types.GenerateTextResult {
+	usage := types.NewTokenUsage(s.PromptTokens, s.CompletionTokens, s.TotalTokens).
+		WithCachedTokens(s.CachedTokens).
+		WithReasoningTokens(s.ReasoningTokens)
+	var choices []types.Choice
+	for _, choice := range s.Choices {
+		choices = append(choices, types.NewChoice(choice.Index, choice.Text, choice.FinishReason, decodeToolCalls(choice.ToolCalls), choice.Refusal))
+	}
+	return types.NewGenerateTextResult(s.Text, usage).
+		WithToolCalls(decodeToolCalls(s.ToolCalls)).
+		WithFinishReason(s.FinishReason).
+		WithResponseID(s.ResponseID).
+		WithModel(s.Model).
+		WithSystemFingerprint(s.SystemFingerprint).
+		WithCreated(s.Created).
+		WithRefusal(s.Refusal).
+		WithChoices(choices).
+		WithRawResponse(s.Raw)
+}
+
+func encodeToolCalls(calls []types.ToolCall) []storedToolCall {
+	var stored []storedToolCall
+	for _, call := range calls {
+		stored = append(stored, storedToolCall{ID: call.ID(), Name: call.Name(), Arguments: call.Arguments()})
+	}
+	return stored
+}
+
+func decodeToolCalls(stored []storedToolCall) []types.ToolCall {
+	var calls []types.ToolCall
+	for _, call := range stored {
+		calls = append(calls, types.NewToolCall(call.ID, call.Name, call.Arguments))
+	}
+	return calls
+}
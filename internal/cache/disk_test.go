@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestDiskStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "cache")
+	store, err := NewDiskStore(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	calls := []types.ToolCall{types.NewToolCall("call_1", "lookup", `{"q":"paris"}`)}
+	result := types.NewGenerateTextResult("Paris", types.NewTokenUsage(10, 5, 15).WithCachedTokens(4).WithReasoningTokens(2)).
+		WithToolCalls(calls).
+		WithFinishReason(types.FinishReasonToolCalls).
+		WithResponseID("resp_1").
+		WithModel("gpt-4.1-2025-04-14").
+		WithSystemFingerprint("fp_1").
+		WithCreated(created).
+		WithChoices([]types.Choice{types.NewChoice(0, "Paris", types.FinishReasonToolCalls, calls, "")}).
+		WithRawResponse([]byte(`{"id":"resp_1"}`))
+	expires := created.Add(time.Hour)
+
+	if err := store.Set(ctx, "key", Entry{Result: result, ExpiresAt: expires}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reopened, _ := NewDiskStore(dir)
+	entry, found, err := reopened.Get(ctx, "key")
+	if err != nil || !found {
+		t.Fatalf("expected entry, got %v %v", found, err)
+	}
+	got := entry.Result
+	if !entry.ExpiresAt.Equal(expires) || got.TextContent() != "Paris" || got.ResponseID() != "resp_1" || got.Model() != "gpt-4.1-2025-04-14" || got.SystemFingerprint() != "fp_1" || !got.Created().Equal(created) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if usage := got.Usage(); usage.TotalTokens() != 15 || usage.CachedTokens() != 4 || usage.ReasoningTokens() != 2 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+	if len(got.ToolCalls()) != 1 || got.ToolCalls()[0].Arguments() != `{"q":"paris"}` || len(got.Choices()) != 1 || got.Choices()[0].FinishReason() != types.FinishReasonToolCalls {
+		t.Errorf("unexpected tool calls or choices: %+v", got)
+	}
+	if string(got.RawResponse()) != `{"id":"resp_1"}` {
+		t.Errorf("unexpected raw response: %s", got.RawResponse())
+	}
+
+	if _, found, err := store.Get(ctx, "missing"); found || err != nil {
+		t.Errorf("expected missing entry, got %v %v", found, err)
+	}
+
+	if err := store.Delete(ctx, "key"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found, _ := store.Get(ctx, "key"); found {
+		t.Error("expected entry to be deleted")
+	}
+
+	os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0o644)
+	if _, _, err := store.Get(ctx, "corrupt"); err == nil {
+		t.Error("expected error for corrupt entry")
+	}
+}
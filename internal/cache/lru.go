@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+type lruItem struct {
+	key   string
+	entry Entry
+}
+
+type LRUStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+func NewLRUStore(capacity int) *LRUStore {
+	return &LRUStore{capacity: capacity, items: make(map[string]*list.Element), order: list.New()}
+}
+
+func (s *LRUStore) Get(ctx context.Context, key string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, exists := s.items[key]
+	if !exists {
+		return Entry{}, false, nil
+	}
+	s.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true, nil
+}
+
+func (s *LRUStore) Set(ctx context.Context, key string, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, exists := s.items[key]; exists {
+		element.Value.(*lruItem).entry = entry
+		s.order.MoveToFront(element)
+		return nil
+	}
+	s.items[key] = s.order.PushFront(&lruItem{key: key, entry: entry})
+	for s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+func (s *LRUStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, exists := s.items[key]; exists {
+		s.order.Remove(element)
+		delete(s.items, key)
+	}
+	return nil
+}
+
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
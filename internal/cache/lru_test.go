@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestLRUStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(2)
+	entry := func(text string) Entry {
+		return Entry{Result: types.NewGenerateTextResult(text, types.NewTokenUsage(1, 1, 2))}
+	}
+
+	store.Set(ctx, "a", entry("A"))
+	store.Set(ctx, "b", entry("B"))
+	store.Get(ctx, "a")
+	store.Set(ctx, "c", entry("C"))
+
+	if _, found, _ := store.Get(ctx, "b"); found {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if got, found, _ := store.Get(ctx, "a"); !found || got.Result.TextContent() != "A" {
+		t.Errorf("expected recently used entry to be kept, got %+v", got)
+	}
+
+	store.Set(ctx, "a", entry("A2"))
+	if got, _, _ := store.Get(ctx, "a"); got.Result.TextContent() != "A2" || store.Len() != 2 {
+		t.Errorf("expected entry to be replaced in place, got %s with %d entries", got.Result.TextContent(), store.Len())
+	}
+
+	store.Delete(ctx, "a")
+	if _, found, _ := store.Get(ctx, "a"); found || store.Len() != 1 {
+		t.Error("expected entry to be deleted")
+	}
+}
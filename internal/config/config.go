@@ -3,29 +3,137 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	ProviderTypeOpenAI    = "openai"
+	ProviderTypeAnthropic = "anthropic"
+	ProviderTypeOllama    = "ollama"
+)
+
 type Config struct {
-	OpenAI struct {
-		APIKey  string `yaml:"api_key"`
-		BaseURL string `yaml:"base_url"`
-	} `yaml:"openai"`
+	OpenAI    OpenAIConfig           `yaml:"openai"`
+	Anthropic AnthropicConfig        `yaml:"anthropic"`
+	Ollama    OllamaConfig           `yaml:"ollama"`
+	Providers []ProviderConfig       `yaml:"providers"`
+	Pricing   map[string]PriceConfig `yaml:"pricing"`
+}
+
+type OpenAIConfig struct {
+	APIKey          string              `yaml:"api_key"`
+	BaseURL         string              `yaml:"base_url"`
+	Organization    string              `yaml:"organization"`
+	Project         string              `yaml:"project"`
+	Endpoints       []EndpointConfig    `yaml:"endpoints"`
+	LoadBalancing   LoadBalancingConfig `yaml:"load_balancing"`
+	Models          []ModelConfig       `yaml:"models"`
+	EmbeddingModels []ModelConfig       `yaml:"embedding_models"`
+	Retry           RetryConfig         `yaml:"retry"`
+}
+
+type EndpointConfig struct {
+	APIKey       string `yaml:"api_key"`
+	BaseURL      string `yaml:"base_url"`
+	Organization string `yaml:"organization"`
+	Project      string `yaml:"project"`
+	Weight       int    `yaml:"weight"`
+}
+
+type LoadBalancingConfig struct {
+	Strategy string        `yaml:"strategy"`
+	Ejection time.Duration `yaml:"ejection"`
+}
+
+type AnthropicConfig struct {
+	APIKey  string      `yaml:"api_key"`
+	BaseURL string      `yaml:"base_url"`
+	Version string      `yaml:"version"`
+	Retry   RetryConfig `yaml:"retry"`
+}
+
+type OllamaConfig struct {
+	BaseURL       string      `yaml:"base_url"`
+	ContextLength int         `yaml:"context_length"`
+	Retry         RetryConfig `yaml:"retry"`
+}
+
+type ProviderConfig struct {
+	ID              string              `yaml:"id"`
+	Type            string              `yaml:"type"`
+	APIKey          string              `yaml:"api_key"`
+	BaseURL         string              `yaml:"base_url"`
+	Version         string              `yaml:"version"`
+	Organization    string              `yaml:"organization"`
+	Project         string              `yaml:"project"`
+	Endpoints       []EndpointConfig    `yaml:"endpoints"`
+	LoadBalancing   LoadBalancingConfig `yaml:"load_balancing"`
+	Models          []ModelConfig       `yaml:"models"`
+	EmbeddingModels []ModelConfig       `yaml:"embedding_models"`
+	ContextLength   int                 `yaml:"context_length"`
+	Retry           RetryConfig         `yaml:"retry"`
+}
+
+func (p ProviderConfig) OpenAI() OpenAIConfig {
+	return OpenAIConfig{
+		APIKey:          p.APIKey,
+		BaseURL:         p.BaseURL,
+		Organization:    p.Organization,
+		Project:         p.Project,
+		Endpoints:       p.Endpoints,
+		LoadBalancing:   p.LoadBalancing,
+		Models:          p.Models,
+		EmbeddingModels: p.EmbeddingModels,
+		Retry:           p.Retry,
+	}
+}
+
+func (p ProviderConfig) Anthropic() AnthropicConfig {
+	return AnthropicConfig{APIKey: p.APIKey, BaseURL: p.BaseURL, Version: p.Version, Retry: p.Retry}
+}
+
+func (p ProviderConfig) Ollama() OllamaConfig {
+	return OllamaConfig{BaseURL: p.BaseURL, ContextLength: p.ContextLength, Retry: p.Retry}
+}
+
+type ModelConfig struct {
+	Name            string   `yaml:"name"`
+	Parameters      []string `yaml:"parameters"`
+	Vision          bool     `yaml:"vision"`
+	ContextWindow   int      `yaml:"context_window"`
+	MaxOutputTokens int      `yaml:"max_output_tokens"`
+}
+
+type PriceConfig struct {
+	Input       float64 `yaml:"input"`
+	CachedInput float64 `yaml:"cached_input"`
+	Output      float64 `yaml:"output"`
+	Reasoning   float64 `yaml:"reasoning"`
+}
+
+type RetryConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	MaxElapsed     time.Duration `yaml:"max_elapsed"`
+	Multiplier     float64       `yaml:"multiplier"`
+	Jitter         float64       `yaml:"jitter"`
 }
 
-func LoadConfig(filename string) Config {
+func LoadConfig(filename string) (Config, error) {
 	var config Config
 
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to read config file %s: %v", filename, err))
+		return Config{}, fmt.Errorf("failed to read config file %s: %w", filename, err)
 	}
 
 	err = yaml.Unmarshal(data, &config)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to parse config file %s: %v", filename, err))
+		return Config{}, fmt.Errorf("failed to parse config file %s: %w", filename, err)
 	}
 
-	return config
+	return config, nil
 }
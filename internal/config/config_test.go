@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -16,7 +20,10 @@ func TestLoadConfig(t *testing.T) {
 	}
 	defer os.Remove("test_config.yaml")
 
-	cfg := LoadConfig("test_config.yaml")
+	cfg, err := LoadConfig("test_config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if cfg.OpenAI.APIKey == "" {
 		t.Error("expected openai.api_key to be loaded from config.yaml")
@@ -28,3 +35,215 @@ func TestLoadConfig(t *testing.T) {
 		t.Errorf("expected base_url 'https://api.openai.com/v1', got '%s'", cfg.OpenAI.BaseURL)
 	}
 }
+
+func TestLoadConfigAnthropic(t *testing.T) {
+	testConfig := `anthropic:
+  api_key: "test-anthropic-key"
+  base_url: "https://api.anthropic.com/v1"
+  version: "2023-06-01"
+`
+	err := os.WriteFile("test_config_anthropic.yaml", []byte(testConfig), 0644)
+	if err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	defer os.Remove("test_config_anthropic.yaml")
+
+	cfg, err := LoadConfig("test_config_anthropic.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Anthropic.APIKey != "test-anthropic-key" {
+		t.Errorf("expected api_key 'test-anthropic-key', got '%s'", cfg.Anthropic.APIKey)
+	}
+	if cfg.Anthropic.BaseURL != "https://api.anthropic.com/v1" {
+		t.Errorf("expected base_url 'https://api.anthropic.com/v1', got '%s'", cfg.Anthropic.BaseURL)
+	}
+	if cfg.Anthropic.Version != "2023-06-01" {
+		t.Errorf("expected version '2023-06-01', got '%s'", cfg.Anthropic.Version)
+	}
+	if cfg.OpenAI.APIKey != "" {
+		t.Errorf("expected empty openai.api_key, got '%s'", cfg.OpenAI.APIKey)
+	}
+}
+
+func TestLoadConfigRetry(t *testing.T) {
+	testConfig := `openai:
+  api_key: "test-key"
+  retry:
+    max_attempts: 5
+    initial_backoff: 250ms
+    max_backoff: 10s
+    max_elapsed: 1m
+    multiplier: 1.5
+    jitter: 0.1
+`
+	err := os.WriteFile("test_config_retry.yaml", []byte(testConfig), 0644)
+	if err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	defer os.Remove("test_config_retry.yaml")
+
+	cfg, err := LoadConfig("test_config_retry.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	retry := cfg.OpenAI.Retry
+	if retry.MaxAttempts != 5 {
+		t.Errorf("expected max_attempts 5, got %d", retry.MaxAttempts)
+	}
+	if retry.InitialBackoff != 250*time.Millisecond || retry.MaxBackoff != 10*time.Second || retry.MaxElapsed != time.Minute {
+		t.Errorf("unexpected durations: %+v", retry)
+	}
+	if retry.Multiplier != 1.5 || retry.Jitter != 0.1 {
+		t.Errorf("unexpected multiplier/jitter: %+v", retry)
+	}
+	if cfg.Anthropic.Retry != (RetryConfig{}) {
+		t.Errorf("expected empty anthropic retry config, got %+v", cfg.Anthropic.Retry)
+	}
+}
+
+func TestLoadConfigProviders(t *testing.T) {
+	testConfig := `providers:
+  - id: openai
+    type: openai
+    api_key: "test-key"
+  - id: groq
+    type: openai
+    api_key: "groq-key"
+    base_url: "https://api.groq.com/openai/v1"
+    models:
+      - name: llama-3.3-70b-versatile
+        parameters: [temperature, top_p]
+`
+	err := os.WriteFile("test_config_providers.yaml", []byte(testConfig), 0644)
+	if err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	defer os.Remove("test_config_providers.yaml")
+
+	cfg, err := LoadConfig("test_config_providers.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cfg.Providers) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(cfg.Providers))
+	}
+	groq := cfg.Providers[1]
+	if groq.ID != "groq" || groq.Type != ProviderTypeOpenAI || groq.BaseURL != "https://api.groq.com/openai/v1" {
+		t.Errorf("unexpected provider: %+v", groq)
+	}
+	if len(groq.Models) != 1 || groq.Models[0].Name != "llama-3.3-70b-versatile" || len(groq.Models[0].Parameters) != 2 {
+		t.Errorf("unexpected models: %+v", groq.Models)
+	}
+
+	openai := groq.OpenAI()
+	if openai.APIKey != "groq-key" || openai.BaseURL != groq.BaseURL || len(openai.Models) != 1 {
+		t.Errorf("unexpected openai config: %+v", openai)
+	}
+}
+
+func TestLoadConfigPricing(t *testing.T) {
+	testConfig := `pricing:
+  gpt-4.1:
+    input: 2.00
+    cached_input: 0.50
+    output: 8.00
+  llama-3.3-70b-versatile:
+    input: 0.59
+    output: 0.79
+`
+	err := os.WriteFile("test_config_pricing.yaml", []byte(testConfig), 0644)
+	if err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	defer os.Remove("test_config_pricing.yaml")
+
+	cfg, err := LoadConfig("test_config_pricing.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cfg.Pricing) != 2 {
+		t.Fatalf("expected 2 prices, got %d", len(cfg.Pricing))
+	}
+	if price := cfg.Pricing["gpt-4.1"]; price.Input != 2 || price.CachedInput != 0.5 || price.Output != 8 || price.Reasoning != 0 {
+		t.Errorf("unexpected price: %+v", price)
+	}
+}
+
+func TestLoadConfigEndpoints(t *testing.T) {
+	testConfig := `openai:
+  organization: "org-shared"
+  endpoints:
+    - api_key: "team-a-key"
+      project: "proj_a"
+      weight: 3
+    - api_key: "team-b-key"
+      base_url: "https://eu.api.openai.com/v1"
+      organization: "org-b"
+  load_balancing:
+    strategy: weighted
+    ejection: 45s
+providers:
+  - id: pooled
+    type: openai
+    endpoints:
+      - api_key: "key-1"
+      - api_key: "key-2"
+    load_balancing:
+      strategy: least_loaded
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	openai := cfg.OpenAI
+	if openai.Organization != "org-shared" || len(openai.Endpoints) != 2 {
+		t.Fatalf("unexpected openai config: %+v", openai)
+	}
+	if endpoint := openai.Endpoints[0]; endpoint.APIKey != "team-a-key" || endpoint.Project != "proj_a" || endpoint.Weight != 3 {
+		t.Errorf("unexpected first endpoint: %+v", endpoint)
+	}
+	if endpoint := openai.Endpoints[1]; endpoint.BaseURL != "https://eu.api.openai.com/v1" || endpoint.Organization != "org-b" {
+		t.Errorf("unexpected second endpoint: %+v", endpoint)
+	}
+	if openai.LoadBalancing.Strategy != "weighted" || openai.LoadBalancing.Ejection != 45*time.Second {
+		t.Errorf("unexpected load balancing config: %+v", openai.LoadBalancing)
+	}
+
+	pooled := cfg.Providers[0].OpenAI()
+	if len(pooled.Endpoints) != 2 || pooled.LoadBalancing.Strategy != "least_loaded" {
+		t.Errorf("expected endpoints to carry over to the provider config, got %+v", pooled)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadConfig("does_not_exist.yaml")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected fs.ErrNotExist, got %v", err)
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		err := os.WriteFile("test_config_invalid.yaml", []byte("openai: [unterminated"), 0644)
+		if err != nil {
+			t.Fatalf("failed to create test config: %v", err)
+		}
+		defer os.Remove("test_config_invalid.yaml")
+
+		_, err = LoadConfig("test_config_invalid.yaml")
+		if err == nil {
+			t.Fatal("expected error for invalid yaml")
+		}
+	})
+}
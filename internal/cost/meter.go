@@ -0,0 +1,128 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/runtime"
+	"agentic-ai-framework/internal/tokenizer"
+	"agentic-ai-framework/internal/types"
+)
+
+type MeteredProvider struct {
+	provider.Provider
+	tracker *Tracker
+}
+
+func NewMeteredProvider(p provider.Provider, tracker *Tracker) *MeteredProvider {
+	return &MeteredProvider{Provider: p, tracker: tracker}
+}
+
+func (p *MeteredProvider) Tracker() *Tracker {
+	return p.tracker
+}
+
+func (p *MeteredProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *MeteredProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.meter(ctx, modelName, messages, requestParameters, func() (types.GenerateTextResult, error) {
+		return p.Provider.GenerateChat(ctx, messages, modelName, requestParameters)
+	})
+}
+
+func (p *MeteredProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.meter(ctx, modelName, messages, requestParameters, func() (types.GenerateTextResult, error) {
+		return p.Provider.GenerateWithTools(ctx, messages, tools, modelName, requestParameters)
+	})
+}
+
+func (p *MeteredProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	reservation, err := p.reserve(ctx, modelName, []types.Message{types.NewUserMessage(prompt)}, requestParameters)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := p.Provider.StreamText(ctx, prompt, modelName, requestParameters)
+	if err != nil {
+		reservation.Release()
+		return nil, err
+	}
+
+	metered := make(chan types.StreamChunk)
+	go func() {
+		defer close(metered)
+		defer reservation.Release()
+		for chunk := range chunks {
+			if chunk.Done() {
+				result := chunk.Result()
+				cost := reservation.Record(p.pricedModel(modelName, result.Model()), result.Usage())
+				chunk = types.NewStreamResult(result.WithCost(cost))
+			}
+			select {
+			case metered <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return metered, nil
+}
+
+func (p *MeteredProvider) AvailableEmbeddingModels() []provider.Model {
+	if embedder, ok := p.Provider.(provider.Embedder); ok {
+		return embedder.AvailableEmbeddingModels()
+	}
+	return nil
+}
+
+func (p *MeteredProvider) Embed(ctx context.Context, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	embedder, ok := p.Provider.(provider.Embedder)
+	if !ok {
+		return types.EmbeddingResult{}, fmt.Errorf("%w: provider %s does not support embeddings", types.ErrNotSupported, p.Name())
+	}
+	counter := tokenizer.ForModel(modelName)
+	estimate := 0
+	for _, input := range inputs {
+		estimate += counter.Count(input)
+	}
+	reservation, err := p.tracker.Check(ScopeFromContext(ctx), modelName, types.NewTokenUsage(estimate, 0, estimate))
+	if err != nil {
+		return types.EmbeddingResult{}, err
+	}
+	result, err := embedder.Embed(ctx, inputs, modelName, requestParameters)
+	if err != nil {
+		reservation.Release()
+		return result, err
+	}
+	reservation.Record(modelName, result.Usage())
+	return result, nil
+}
+
+func (p *MeteredProvider) meter(ctx context.Context, modelName string, messages []types.Message, requestParameters map[string]any, call func() (types.GenerateTextResult, error)) (types.GenerateTextResult, error) {
+	reservation, err := p.reserve(ctx, modelName, messages, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	result, err := call()
+	if err != nil {
+		reservation.Release()
+		return result, err
+	}
+	cost := reservation.Record(p.pricedModel(modelName, result.Model()), result.Usage())
+	return result.WithCost(cost), nil
+}
+
+func (p *MeteredProvider) reserve(ctx context.Context, modelName string, messages []types.Message, requestParameters map[string]any) (*Reservation, error) {
+	prompt := runtime.EstimatePromptTokens(modelName, messages)
+	output := runtime.RequestedOutputTokens(requestParameters)
+	return p.tracker.Check(ScopeFromContext(ctx), modelName, types.NewTokenUsage(prompt, output, prompt+output))
+}
+
+func (p *MeteredProvider) pricedModel(requested string, served string) string {
+	if _, found := p.tracker.Prices().Lookup(requested); !found && served != "" {
+		return served
+	}
+	return requested
+}
@@ -0,0 +1,143 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+type usageProvider struct {
+	calls int
+}
+
+func (p *usageProvider) Name() string {
+	return "UsageProvider"
+}
+
+func (p *usageProvider) AvailableModels() []provider.Model {
+	return nil
+}
+
+func (p *usageProvider) GetModel(modelName string) (provider.Model, error) {
+	return nil, nil
+}
+
+func (p *usageProvider) AvailableRequestParameters(modelName string) []types.Parameter {
+	return nil
+}
+
+func (p *usageProvider) Config() map[string]any {
+	return nil
+}
+
+func (p *usageProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *usageProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p.calls++
+	if modelName == "broken" {
+		return types.GenerateTextResult{}, errors.New("boom")
+	}
+	return types.NewGenerateTextResult("ok", types.NewTokenUsage(1000, 500, 1500)).WithModel("gpt-4.1-2025-04-14"), nil
+}
+
+func (p *usageProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, messages, modelName, requestParameters)
+}
+
+func (p *usageProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	p.calls++
+	chunks := make(chan types.StreamChunk, 2)
+	chunks <- types.NewStreamDelta("ok")
+	chunks <- types.NewStreamResult(types.NewGenerateTextResult("ok", types.NewTokenUsage(1000, 500, 1500)))
+	close(chunks)
+	return chunks, nil
+}
+
+func (p *usageProvider) AvailableEmbeddingModels() []provider.Model {
+	return nil
+}
+
+func (p *usageProvider) Embed(ctx context.Context, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	p.calls++
+	return types.NewEmbeddingResult(nil, types.NewTokenUsage(1_000_000, 0, 1_000_000)), nil
+}
+
+func TestMeteredProvider(t *testing.T) {
+	prices := PriceTable{"gpt-4.1": {Input: 2, Output: 8}, "text-embedding-3-small": {Input: 0.02}}
+
+	t.Run("records cost of each call", func(t *testing.T) {
+		tracker := NewTracker(prices)
+		p := NewMeteredProvider(&usageProvider{}, tracker)
+		ctx := WithScope(context.Background(), Scope{Tenant: "acme"})
+
+		result, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !approx(result.Cost(), 0.006) {
+			t.Errorf("expected cost on result, got %f", result.Cost())
+		}
+		if _, err := p.GenerateWithTools(ctx, nil, nil, "openai-proxy", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := p.Embed(ctx, []string{"a"}, "text-embedding-3-small", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := p.GenerateChat(ctx, nil, "broken", nil); err == nil {
+			t.Fatal("expected error")
+		}
+
+		spend := tracker.Spend(DimensionTenant, "acme")
+		if spend.Calls != 3 || !approx(spend.Cost, 0.006+0.006+0.02) || spend.Unpriced != 0 {
+			t.Errorf("expected served model to be priced when the requested one is unknown, got %+v", spend)
+		}
+	})
+
+	t.Run("records streamed usage", func(t *testing.T) {
+		tracker := NewTracker(prices)
+		p := NewMeteredProvider(&usageProvider{}, tracker)
+
+		chunks, err := p.StreamText(context.Background(), "Hello", "gpt-4.1", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var final types.GenerateTextResult
+		for chunk := range chunks {
+			if chunk.Done() {
+				final = chunk.Result()
+			}
+		}
+		if !approx(final.Cost(), 0.006) || !approx(tracker.Total().Cost, 0.006) {
+			t.Errorf("expected streamed cost to be recorded, got %f and %f", final.Cost(), tracker.Total().Cost)
+		}
+	})
+
+	t.Run("refuses calls over budget", func(t *testing.T) {
+		tracker := NewTracker(prices)
+		tracker.SetBudget(DimensionSession, "s1", 0.005)
+		inner := &usageProvider{}
+		p := NewMeteredProvider(inner, tracker)
+		ctx := WithScope(context.Background(), Scope{Session: "s1"})
+
+		if _, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil); !errors.Is(err, types.ErrBudgetExceeded) {
+			t.Errorf("expected ErrBudgetExceeded, got %v", err)
+		}
+		if _, err := p.StreamText(ctx, "Hello", "gpt-4.1", nil); !errors.Is(err, types.ErrBudgetExceeded) {
+			t.Errorf("expected ErrBudgetExceeded for streams, got %v", err)
+		}
+		if _, err := p.Embed(ctx, []string{"a"}, "text-embedding-3-small", nil); !errors.Is(err, types.ErrBudgetExceeded) {
+			t.Errorf("expected ErrBudgetExceeded for embeddings, got %v", err)
+		}
+		if inner.calls != 1 {
+			t.Errorf("expected refused calls not to reach the provider, got %d calls", inner.calls)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package cost
+
+import (
+	"regexp"
+	"strings"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/types"
+)
+
+type Price struct {
+	Input       float64
+	CachedInput float64
+	Output      float64
+	Reasoning   float64
+}
+
+func (p Price) Cost(usage types.TokenUsage) float64 {
+	cached := min(usage.CachedTokens(), usage.PromptTokens())
+	reasoning := min(usage.ReasoningTokens(), usage.CompletionTokens())
+
+	cachedRate := p.CachedInput
+	if cachedRate == 0 {
+		cachedRate = p.Input
+	}
+	reasoningRate := p.Reasoning
+	if reasoningRate == 0 {
+		reasoningRate = p.Output
+	}
+
+	total := float64(usage.PromptTokens()-cached)*p.Input +
+		float64(cached)*cachedRate +
+		float64(usage.CompletionTokens()-reasoning)*p.Output +
+		float64(reasoning)*reasoningRate
+	return total / 1_000_000
+}
+
+var snapshotSuffix = regexp.MustCompile(`-(\d{4}-\d{2}-\d{2}|\d{8})$`)
+
+type PriceTable map[string]Price
+
+func DefaultPrices() PriceTable {
+	return PriceTable{
+		"gpt-4.1":                {Input: 2.00, CachedInput: 0.50, Output: 8.00},
+		"gpt-4.1-mini":           {Input: 0.40, CachedInput: 0.10, Output: 1.60},
+		"gpt-4.1-nano":           {Input: 0.10, CachedInput: 0.025, Output: 0.40},
+		"gpt-5":                  {Input: 1.25, CachedInput: 0.125, Output: 10.00},
+		"gpt-5-mini":             {Input: 0.25, CachedInput: 0.025, Output: 2.00},
+		"gpt-5-nano":             {Input: 0.05, CachedInput: 0.005, Output: 0.40},
+		"claude-sonnet-4-5":      {Input: 3.00, CachedInput: 0.30, Output: 15.00},
+		"claude-haiku-4-5":       {Input: 1.00, CachedInput: 0.10, Output: 5.00},
+		"claude-opus-4-1":        {Input: 15.00, CachedInput: 1.50, Output: 75.00},
+		"text-embedding-3-small": {Input: 0.02},
+		"text-embedding-3-large": {Input: 0.13},
+		"text-embedding-ada-002": {Input: 0.10},
+	}
+}
+
+func LoadPrices(configFile string) (PriceTable, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return PricesFromConfig(cfg.Pricing), nil
+}
+
+func PricesFromConfig(pricing map[string]config.PriceConfig) PriceTable {
+	prices := DefaultPrices()
+	for model, price := range pricing {
+		prices[model] = Price{Input: price.Input, CachedInput: price.CachedInput, Output: price.Output, Reasoning: price.Reasoning}
+	}
+	return prices
+}
+
+func (t PriceTable) Lookup(modelName string) (Price, bool) {
+	if price, exists := t[modelName]; exists {
+		return price, true
+	}
+	if _, name, found := strings.Cut(modelName, "/"); found {
+		if price, exists := t[name]; exists {
+			return price, true
+		}
+		modelName = name
+	}
+
+	if base := snapshotSuffix.ReplaceAllString(modelName, ""); base != modelName {
+		if price, exists := t[base]; exists {
+			return price, true
+		}
+	}
+	return Price{}, false
+}
+
+func (t PriceTable) Cost(modelName string, usage types.TokenUsage) (float64, bool) {
+	price, found := t.Lookup(modelName)
+	if !found {
+		return 0, false
+	}
+	return price.Cost(usage), true
+}
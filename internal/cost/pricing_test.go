@@ -0,0 +1,100 @@
+package cost
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/types"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPriceCost(t *testing.T) {
+	price := Price{Input: 2, CachedInput: 0.5, Output: 8, Reasoning: 10}
+
+	usage := types.NewTokenUsage(1_000_000, 500_000, 1_500_000).WithCachedTokens(400_000).WithReasoningTokens(100_000)
+	if cost := price.Cost(usage); !approx(cost, 0.6*2+0.4*0.5+0.4*8+0.1*10) {
+		t.Errorf("unexpected cost: %f", cost)
+	}
+
+	if cost := (Price{Input: 1, Output: 4}).Cost(usage); !approx(cost, 1+0.5*4) {
+		t.Errorf("expected cached and reasoning tokens to fall back to input and output rates, got %f", cost)
+	}
+	if cost := price.Cost(types.NewTokenUsage(0, 0, 0)); cost != 0 {
+		t.Errorf("expected zero cost for zero usage, got %f", cost)
+	}
+}
+
+func TestPriceTableLookup(t *testing.T) {
+	prices := DefaultPrices()
+
+	cases := map[string]float64{
+		"gpt-4.1":                   2.00,
+		"gpt-4.1-mini":              0.40,
+		"gpt-4.1-2025-04-14":        2.00,
+		"gpt-4.1-mini-2025-04-14":   0.40,
+		"claude-haiku-4-5-20251001": 1.00,
+		"openai/gpt-5":              1.25,
+		"claude-sonnet-4-5":         3.00,
+	}
+	for model, input := range cases {
+		price, found := prices.Lookup(model)
+		if !found || price.Input != input {
+			t.Errorf("%s: expected input price %.2f, got %+v (found %t)", model, input, price, found)
+		}
+	}
+
+	if _, found := prices.Lookup("llama3.2"); found {
+		t.Error("expected unknown model to have no price")
+	}
+	if _, found := prices.Lookup("gpt-4.10"); found {
+		t.Error("expected prefix match to require a dash")
+	}
+	for _, model := range []string{"gpt-5-pro", "gpt-4.1-mini-preview", "claude-sonnet-4-5-latest"} {
+		if _, found := prices.Lookup(model); found {
+			t.Errorf("expected unknown variant %s to have no price", model)
+		}
+	}
+	if cost, found := prices.Cost("llama3.2", types.NewTokenUsage(10, 10, 20)); found || cost != 0 {
+		t.Errorf("expected no cost for unknown model, got %f", cost)
+	}
+}
+
+func TestPricesFromConfig(t *testing.T) {
+	prices := PricesFromConfig(map[string]config.PriceConfig{
+		"gpt-4.1":                 {Input: 1, Output: 4},
+		"llama-3.3-70b-versatile": {Input: 0.59, Output: 0.79},
+	})
+
+	if price, _ := prices.Lookup("gpt-4.1"); price.Input != 1 || price.CachedInput != 0 {
+		t.Errorf("expected config to override defaults, got %+v", price)
+	}
+	if price, found := prices.Lookup("groq/llama-3.3-70b-versatile"); !found || price.Output != 0.79 {
+		t.Errorf("expected price from config, got %+v", price)
+	}
+	if _, found := prices.Lookup("claude-haiku-4-5"); !found {
+		t.Error("expected defaults to be kept")
+	}
+
+	testConfig := `pricing:
+  my-model:
+    input: 1.5
+    output: 3
+`
+	if err := os.WriteFile("test_config_pricing.yaml", []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	defer os.Remove("test_config_pricing.yaml")
+
+	prices, err := LoadPrices("test_config_pricing.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price, _ := prices.Lookup("my-model"); price.Input != 1.5 {
+		t.Errorf("unexpected price: %+v", price)
+	}
+}
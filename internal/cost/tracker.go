@@ -0,0 +1,235 @@
+package cost
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type Dimension string
+
+const (
+	DimensionRun     Dimension = "run"
+	DimensionAgent   Dimension = "agent"
+	DimensionSession Dimension = "session"
+	DimensionTenant  Dimension = "tenant"
+)
+
+type Scope struct {
+	Run     string
+	Agent   string
+	Session string
+	Tenant  string
+}
+
+func (s Scope) values() map[Dimension]string {
+	values := make(map[Dimension]string)
+	for dimension, value := range map[Dimension]string{
+		DimensionRun:     s.Run,
+		DimensionAgent:   s.Agent,
+		DimensionSession: s.Session,
+		DimensionTenant:  s.Tenant,
+	} {
+		if value != "" {
+			values[dimension] = value
+		}
+	}
+	return values
+}
+
+type scopeKey struct{}
+
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	current := ScopeFromContext(ctx)
+	if scope.Run != "" {
+		current.Run = scope.Run
+	}
+	if scope.Agent != "" {
+		current.Agent = scope.Agent
+	}
+	if scope.Session != "" {
+		current.Session = scope.Session
+	}
+	if scope.Tenant != "" {
+		current.Tenant = scope.Tenant
+	}
+	return context.WithValue(ctx, scopeKey{}, current)
+}
+
+func ScopeFromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+type Entry struct {
+	Dimension Dimension
+	Value     string
+	Cost      float64
+	Usage     types.TokenUsage
+	Calls     int
+	Unpriced  int
+}
+
+type entryKey struct {
+	dimension Dimension
+	value     string
+}
+
+type Tracker struct {
+	mu       sync.Mutex
+	prices   PriceTable
+	entries  map[entryKey]*Entry
+	budgets  map[entryKey]float64
+	reserved map[entryKey]float64
+	total    Entry
+}
+
+type Reservation struct {
+	tracker *Tracker
+	scope   Scope
+	keys    []entryKey
+	cost    float64
+	once    sync.Once
+}
+
+func NewTracker(prices PriceTable) *Tracker {
+	if prices == nil {
+		prices = DefaultPrices()
+	}
+	return &Tracker{
+		prices:   prices,
+		entries:  make(map[entryKey]*Entry),
+		budgets:  make(map[entryKey]float64),
+		reserved: make(map[entryKey]float64),
+	}
+}
+
+func (t *Tracker) Prices() PriceTable {
+	return t.prices
+}
+
+func (t *Tracker) SetBudget(dimension Dimension, value string, limit float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budgets[entryKey{dimension, value}] = limit
+}
+
+func (t *Tracker) Check(scope Scope, modelName string, estimate types.TokenUsage) (*Reservation, error) {
+	cost, _ := t.prices.Cost(modelName, estimate)
+	values := scope.values()
+	reservation := &Reservation{tracker: t, scope: scope, cost: cost}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, dimension := range []Dimension{DimensionRun, DimensionAgent, DimensionSession, DimensionTenant} {
+		value, scoped := values[dimension]
+		if !scoped {
+			continue
+		}
+		key := entryKey{dimension, value}
+		limit, limited := t.budgets[key]
+		if !limited {
+			continue
+		}
+		spent := t.reserved[key]
+		if entry, exists := t.entries[key]; exists {
+			spent += entry.Cost
+		}
+		if spent >= limit {
+			return nil, &types.BudgetExceededError{Dimension: string(dimension), Value: value, Limit: limit, Spent: spent}
+		}
+		reservation.keys = append(reservation.keys, key)
+	}
+	for _, key := range reservation.keys {
+		t.reserved[key] += cost
+	}
+	return reservation, nil
+}
+
+func (t *Tracker) Record(scope Scope, modelName string, usage types.TokenUsage) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.record(scope, modelName, usage)
+}
+
+func (t *Tracker) record(scope Scope, modelName string, usage types.TokenUsage) float64 {
+	cost, priced := t.prices.Cost(modelName, usage)
+	add := func(entry *Entry) {
+		entry.Cost += cost
+		entry.Usage = entry.Usage.Add(usage)
+		entry.Calls++
+		if !priced {
+			entry.Unpriced++
+		}
+	}
+	add(&t.total)
+	for dimension, value := range scope.values() {
+		key := entryKey{dimension, value}
+		entry, exists := t.entries[key]
+		if !exists {
+			entry = &Entry{Dimension: dimension, Value: value}
+			t.entries[key] = entry
+		}
+		add(entry)
+	}
+	return cost
+}
+
+func (t *Tracker) release(reservation *Reservation) {
+	for _, key := range reservation.keys {
+		t.reserved[key] -= reservation.cost
+		if t.reserved[key] <= 0 {
+			delete(t.reserved, key)
+		}
+	}
+}
+
+func (r *Reservation) Record(modelName string, usage types.TokenUsage) float64 {
+	cost := 0.0
+	r.once.Do(func() {
+		r.tracker.mu.Lock()
+		defer r.tracker.mu.Unlock()
+		r.tracker.release(r)
+		cost = r.tracker.record(r.scope, modelName, usage)
+	})
+	return cost
+}
+
+func (r *Reservation) Release() {
+	r.once.Do(func() {
+		r.tracker.mu.Lock()
+		defer r.tracker.mu.Unlock()
+		r.tracker.release(r)
+	})
+}
+
+func (t *Tracker) Spend(dimension Dimension, value string) Entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, exists := t.entries[entryKey{dimension, value}]; exists {
+		return *entry
+	}
+	return Entry{Dimension: dimension, Value: value}
+}
+
+func (t *Tracker) Total() Entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+func (t *Tracker) Report() []Entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries := make([]Entry, 0, len(t.entries))
+	for _, entry := range t.entries {
+		entries = append(entries, *entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Or(cmp.Compare(a.Dimension, b.Dimension), cmp.Compare(a.Value, b.Value))
+	})
+	return entries
+}
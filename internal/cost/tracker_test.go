@@ -0,0 +1,143 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestWithScope(t *testing.T) {
+	ctx := WithScope(context.Background(), Scope{Tenant: "acme", Session: "s1"})
+	ctx = WithScope(ctx, Scope{Agent: "support", Session: "s2"})
+
+	scope := ScopeFromContext(ctx)
+	if scope != (Scope{Agent: "support", Session: "s2", Tenant: "acme"}) {
+		t.Errorf("unexpected scope: %+v", scope)
+	}
+	if ScopeFromContext(context.Background()) != (Scope{}) {
+		t.Error("expected empty scope without a value")
+	}
+}
+
+func TestTracker(t *testing.T) {
+	t.Run("aggregates per dimension", func(t *testing.T) {
+		tracker := NewTracker(PriceTable{"gpt-4.1": {Input: 2, Output: 8}})
+		usage := types.NewTokenUsage(1000, 500, 1500)
+
+		cost := tracker.Record(Scope{Tenant: "acme", Agent: "support"}, "gpt-4.1", usage)
+		if !approx(cost, 0.006) {
+			t.Errorf("unexpected cost: %f", cost)
+		}
+		tracker.Record(Scope{Tenant: "acme", Agent: "billing"}, "gpt-4.1", usage)
+		tracker.Record(Scope{Tenant: "globex"}, "llama3.2", usage)
+
+		acme := tracker.Spend(DimensionTenant, "acme")
+		if !approx(acme.Cost, 0.012) || acme.Calls != 2 || acme.Usage.TotalTokens() != 3000 {
+			t.Errorf("unexpected tenant spend: %+v", acme)
+		}
+		globex := tracker.Spend(DimensionTenant, "globex")
+		if globex.Cost != 0 || globex.Unpriced != 1 {
+			t.Errorf("expected unpriced call to be counted, got %+v", globex)
+		}
+		if total := tracker.Total(); !approx(total.Cost, 0.012) || total.Calls != 3 {
+			t.Errorf("unexpected total: %+v", total)
+		}
+		if unknown := tracker.Spend(DimensionRun, "missing"); unknown.Calls != 0 {
+			t.Errorf("expected empty entry, got %+v", unknown)
+		}
+
+		report := tracker.Report()
+		if len(report) != 4 || report[0].Value != "billing" || report[1].Value != "support" || report[2].Value != "acme" || report[3].Value != "globex" {
+			t.Errorf("unexpected report order: %+v", report)
+		}
+	})
+
+	t.Run("enforces budgets", func(t *testing.T) {
+		tracker := NewTracker(PriceTable{"gpt-4.1": {Input: 2, Output: 8}})
+		tracker.SetBudget(DimensionTenant, "acme", 0.01)
+		scope := Scope{Tenant: "acme", Session: "s1"}
+
+		usage := types.NewTokenUsage(1000, 500, 1500)
+		reservation, err := tracker.Check(scope, "gpt-4.1", usage)
+		if err != nil {
+			t.Fatalf("expected budget to allow the first call, got %v", err)
+		}
+		reservation.Record("gpt-4.1", usage)
+		reservation, err = tracker.Check(scope, "gpt-4.1", usage)
+		if err != nil {
+			t.Errorf("expected spend below the cap to be allowed, got %v", err)
+		}
+		reservation.Record("gpt-4.1", usage)
+
+		_, err = tracker.Check(scope, "gpt-4.1", usage)
+		var budgetErr *types.BudgetExceededError
+		if !errors.As(err, &budgetErr) || !errors.Is(err, types.ErrBudgetExceeded) {
+			t.Fatalf("expected BudgetExceededError, got %v", err)
+		}
+		if budgetErr.Dimension != "tenant" || budgetErr.Value != "acme" || !approx(budgetErr.Spent, 0.012) {
+			t.Errorf("unexpected budget error: %+v", budgetErr)
+		}
+		if _, err := tracker.Check(Scope{Tenant: "globex"}, "gpt-4.1", usage); err != nil {
+			t.Errorf("expected other tenants to be unaffected, got %v", err)
+		}
+	})
+
+	t.Run("reserves in-flight calls against the budget", func(t *testing.T) {
+		tracker := NewTracker(PriceTable{"gpt-4.1": {Input: 2, Output: 8}})
+		tracker.SetBudget(DimensionTenant, "acme", 0.01)
+		scope := Scope{Tenant: "acme"}
+		estimate := types.NewTokenUsage(1000, 500, 1500)
+
+		first, err := tracker.Check(scope, "gpt-4.1", estimate)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := tracker.Check(scope, "gpt-4.1", estimate)
+		if err != nil {
+			t.Fatalf("expected a call below the cap including reservations to be allowed, got %v", err)
+		}
+		if _, err := tracker.Check(scope, "gpt-4.1", estimate); !errors.Is(err, types.ErrBudgetExceeded) {
+			t.Errorf("expected in-flight reservations to exhaust the budget, got %v", err)
+		}
+
+		second.Release()
+		first.Record("gpt-4.1", types.NewTokenUsage(1000, 0, 1000))
+		first.Record("gpt-4.1", types.NewTokenUsage(1000, 0, 1000))
+		if spend := tracker.Spend(DimensionTenant, "acme"); spend.Calls != 1 || !approx(spend.Cost, 0.002) {
+			t.Errorf("expected the reservation to be settled once at actual usage, got %+v", spend)
+		}
+		if _, err := tracker.Check(scope, "gpt-4.1", estimate); err != nil {
+			t.Errorf("expected released reservations to free the budget, got %v", err)
+		}
+	})
+
+	t.Run("concurrent checks cannot overshoot", func(t *testing.T) {
+		tracker := NewTracker(PriceTable{"gpt-4.1": {Input: 2, Output: 8}})
+		tracker.SetBudget(DimensionRun, "r1", 0.01)
+		scope := Scope{Run: "r1"}
+		estimate := types.NewTokenUsage(1000, 500, 1500)
+
+		var wg sync.WaitGroup
+		var allowed atomic.Int32
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reservation, err := tracker.Check(scope, "gpt-4.1", estimate)
+				if err != nil {
+					return
+				}
+				allowed.Add(1)
+				reservation.Record("gpt-4.1", estimate)
+			}()
+		}
+		wg.Wait()
+		if allowed.Load() != 2 {
+			t.Errorf("expected two calls to fit the budget, got %d", allowed.Load())
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+type Conversation struct {
+	mu        sync.Mutex
+	store     Store
+	sessionID string
+	policies  []Policy
+}
+
+func NewConversation(store Store, sessionID string, policies ...Policy) (*Conversation, error) {
+	if store == nil {
+		return nil, fmt.Errorf("%w: memory store is required", types.ErrInvalidConfig)
+	}
+	if sessionID == "" {
+		return nil, fmt.Errorf("%w: session id is required", types.ErrInvalidConfig)
+	}
+	return &Conversation{store: store, sessionID: sessionID, policies: policies}, nil
+}
+
+func (c *Conversation) SessionID() string {
+	return c.sessionID
+}
+
+func (c *Conversation) Messages(ctx context.Context) ([]types.Message, error) {
+	return c.store.Load(ctx, c.sessionID)
+}
+
+func (c *Conversation) Append(ctx context.Context, messages ...types.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.policies) == 0 {
+		return c.store.Append(ctx, c.sessionID, messages...)
+	}
+
+	history, err := c.store.Load(ctx, c.sessionID)
+	if err != nil {
+		return err
+	}
+	history = append(history, messages...)
+	for _, policy := range c.policies {
+		history, err = policy.Apply(ctx, history)
+		if err != nil {
+			return err
+		}
+	}
+	return c.store.Replace(ctx, c.sessionID, history)
+}
+
+func (c *Conversation) Clear(ctx context.Context) error {
+	return c.store.Clear(ctx, c.sessionID)
+}
+
+func (c *Conversation) GenerateChat(ctx context.Context, p provider.Provider, instructions string, input types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	history, err := c.Messages(ctx)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+
+	var messages []types.Message
+	if instructions != "" {
+		messages = append(messages, types.NewSystemMessage(instructions))
+	}
+	messages = append(append(messages, history...), input)
+
+	result, err := p.GenerateChat(ctx, messages, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	if err := c.Append(ctx, input, types.NewAssistantMessage(result.TextContent())); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestNewConversation(t *testing.T) {
+	if _, err := NewConversation(nil, "session"); !errors.Is(err, types.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig without a store, got %v", err)
+	}
+	if _, err := NewConversation(NewInMemoryStore(), ""); !errors.Is(err, types.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig without a session id, got %v", err)
+	}
+}
+
+func TestConversation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("applies policies on append", func(t *testing.T) {
+		store := NewInMemoryStore()
+		conversation, _ := NewConversation(store, "session", SlidingWindow{MaxMessages: 3})
+		for _, message := range toolConversation()[1:] {
+			if err := conversation.Append(ctx, message); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		messages, _ := store.Load(ctx, "session")
+		expected := "assistant:Sunny in Paris, rainy in Rome.|user:Thanks!"
+		if contents(messages) != expected {
+			t.Errorf("expected %s, got %s", expected, contents(messages))
+		}
+	})
+
+	t.Run("generate chat replays history", func(t *testing.T) {
+		p := &summaryProvider{}
+		conversation, _ := NewConversation(NewInMemoryStore(), "session")
+		conversation.Append(ctx, types.NewUserMessage("I'm Ada."), types.NewAssistantMessage("Hi Ada!"))
+
+		result, err := conversation.GenerateChat(ctx, p, "Be friendly.", types.NewUserMessage("Who am I?"), "gpt-4.1", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.TextContent() != " The user is Ada. " {
+			t.Errorf("unexpected result: %q", result.TextContent())
+		}
+
+		expected := "system:Be friendly.|user:I'm Ada.|assistant:Hi Ada!|user:Who am I?"
+		if contents(p.messages) != expected {
+			t.Errorf("expected request %s, got %s", expected, contents(p.messages))
+		}
+		messages, _ := conversation.Messages(ctx)
+		if len(messages) != 4 || messages[0].Role() == types.RoleSystem {
+			t.Errorf("expected 4 stored messages without instructions, got %s", contents(messages))
+		}
+	})
+
+	t.Run("failed call is not stored", func(t *testing.T) {
+		conversation, _ := NewConversation(NewInMemoryStore(), "session")
+		if _, err := conversation.GenerateChat(ctx, &summaryProvider{err: errors.New("boom")}, "", types.NewUserMessage("Hi"), "gpt-4.1", nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if messages, _ := conversation.Messages(ctx); len(messages) != 0 {
+			t.Errorf("expected empty history, got %s", contents(messages))
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create memory directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Load(ctx context.Context, sessionID string) ([]types.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(sessionID)
+}
+
+func (s *FileStore) Append(ctx context.Context, sessionID string, messages ...types.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, err := s.load(sessionID)
+	if err != nil {
+		return err
+	}
+	return s.save(sessionID, append(existing, messages...))
+}
+
+func (s *FileStore) Replace(ctx context.Context, sessionID string, messages []types.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(sessionID, messages)
+}
+
+func (s *FileStore) Clear(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path, err := s.path(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to clear session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+func (s *FileStore) path(sessionID string) (string, error) {
+	if sessionID == "" || sessionID == "." || sessionID == ".." {
+		return "", fmt.Errorf("invalid session id %q", sessionID)
+	}
+	return filepath.Join(s.dir, url.PathEscape(sessionID)+".json"), nil
+}
+
+func (s *FileStore) load(sessionID string) ([]types.Message, error) {
+	path, err := s.path(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session %s: %w", sessionID, err)
+	}
+	messages, err := unmarshalMessages(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session %s: %w", sessionID, err)
+	}
+	return messages, nil
+}
+
+func (s *FileStore) save(sessionID string, messages []types.Message) error {
+	path, err := s.path(sessionID)
+	if err != nil {
+		return err
+	}
+	data, err := marshalMessages(messages)
+	if err != nil {
+		return fmt.Errorf("failed to encode session %s: %w", sessionID, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save session %s: %w", sessionID, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save session %s: %w", sessionID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save session %s: %w", sessionID, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save session %s: %w", sessionID, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "sessions")
+
+	t.Run("persists sessions across instances", func(t *testing.T) {
+		store, err := NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Append(ctx, "user/42", sampleMessages()[:2]...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Append(ctx, "user/42", sampleMessages()[2:]...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		reopened, _ := NewFileStore(dir)
+		messages, err := reopened.Load(ctx, "user/42")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(messages) != 5 || messages[4].Content() != "A cat." {
+			t.Errorf("unexpected messages: %+v", messages)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "user%2F42.json")); err != nil {
+			t.Errorf("expected escaped session file: %v", err)
+		}
+	})
+
+	t.Run("missing session is empty", func(t *testing.T) {
+		store, _ := NewFileStore(dir)
+		messages, err := store.Load(ctx, "unknown")
+		if err != nil || len(messages) != 0 {
+			t.Errorf("expected empty history, got %+v, %v", messages, err)
+		}
+	})
+
+	t.Run("replace and clear", func(t *testing.T) {
+		store, _ := NewFileStore(dir)
+		store.Replace(ctx, "replace", []types.Message{types.NewUserMessage("kept")})
+		if messages, _ := store.Load(ctx, "replace"); len(messages) != 1 || messages[0].Content() != "kept" {
+			t.Errorf("unexpected messages: %+v", messages)
+		}
+		if err := store.Clear(ctx, "replace"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Clear(ctx, "replace"); err != nil {
+			t.Errorf("expected clearing a missing session to succeed, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid session id", func(t *testing.T) {
+		store, _ := NewFileStore(dir)
+		if err := store.Append(ctx, "..", types.NewUserMessage("x")); err == nil {
+			t.Error("expected error for invalid session id")
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/tokenizer"
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	SummaryPrefix = "Summary of the earlier conversation:\n"
+
+	DefaultSummaryInstructions = "Summarize the conversation below for your own future reference. Keep names, facts, decisions, open questions and anything the user asked you to remember. Reply with the summary only."
+)
+
+type Policy interface {
+	Apply(ctx context.Context, messages []types.Message) ([]types.Message, error)
+}
+
+type SlidingWindow struct {
+	MaxMessages int
+}
+
+func (w SlidingWindow) Apply(ctx context.Context, messages []types.Message) ([]types.Message, error) {
+	pinned, rest := splitPinned(messages)
+	if w.MaxMessages <= 0 || len(rest) <= w.MaxMessages {
+		return messages, nil
+	}
+	cut := turnBoundary(rest, len(rest)-w.MaxMessages)
+	return append(pinned, rest[cut:]...), nil
+}
+
+type TokenBudget struct {
+	MaxTokens int
+	Count     func(types.Message) int
+}
+
+func (b TokenBudget) Apply(ctx context.Context, messages []types.Message) ([]types.Message, error) {
+	if b.MaxTokens <= 0 {
+		return messages, nil
+	}
+	count := b.Count
+	if count == nil {
+		count = EstimateTokens
+	}
+
+	pinned, rest := splitPinned(messages)
+	total := 0
+	for _, message := range messages {
+		total += count(message)
+	}
+	cut := 0
+	for cut < len(rest) && total > b.MaxTokens {
+		total -= count(rest[cut])
+		cut++
+		for cut < len(rest) && rest[cut].Role() == types.RoleTool {
+			total -= count(rest[cut])
+			cut++
+		}
+	}
+	if cut == 0 {
+		return messages, nil
+	}
+	return append(pinned, rest[cut:]...), nil
+}
+
+type Summarizer struct {
+	Provider     provider.Provider
+	Model        string
+	Parameters   map[string]any
+	MaxMessages  int
+	KeepMessages int
+	Instructions string
+}
+
+func (s Summarizer) Apply(ctx context.Context, messages []types.Message) ([]types.Message, error) {
+	pinned, rest := splitPinned(messages)
+	if s.MaxMessages <= 0 || len(rest) <= s.MaxMessages {
+		return messages, nil
+	}
+	keep := s.KeepMessages
+	if keep <= 0 || keep >= s.MaxMessages {
+		keep = s.MaxMessages / 2
+	}
+	cut := turnBoundary(rest, len(rest)-keep)
+	if cut == 0 {
+		return messages, nil
+	}
+
+	var kept []types.Message
+	var transcript strings.Builder
+	for _, message := range pinned {
+		if summary, ok := strings.CutPrefix(message.Content(), SummaryPrefix); ok {
+			transcript.WriteString("earlier summary: " + summary + "\n")
+			continue
+		}
+		kept = append(kept, message)
+	}
+	for _, message := range rest[:cut] {
+		transcript.WriteString(formatMessage(message) + "\n")
+	}
+
+	instructions := s.Instructions
+	if instructions == "" {
+		instructions = DefaultSummaryInstructions
+	}
+	result, err := s.Provider.GenerateChat(ctx, []types.Message{
+		types.NewSystemMessage(instructions),
+		types.NewUserMessage(transcript.String()),
+	}, s.Model, s.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+
+	kept = append(kept, types.NewSystemMessage(SummaryPrefix+strings.TrimSpace(result.TextContent())))
+	return append(kept, rest[cut:]...), nil
+}
+
+func EstimateTokens(message types.Message) int {
+	return tokenizer.CountMessage(tokenizer.Heuristic{}, message)
+}
+
+func splitPinned(messages []types.Message) ([]types.Message, []types.Message) {
+	i := 0
+	for i < len(messages) && messages[i].Role() == types.RoleSystem {
+		i++
+	}
+	return append([]types.Message(nil), messages[:i]...), messages[i:]
+}
+
+func turnBoundary(messages []types.Message, cut int) int {
+	for cut < len(messages) && messages[cut].Role() == types.RoleTool {
+		cut++
+	}
+	return cut
+}
+
+func formatMessage(message types.Message) string {
+	switch {
+	case len(message.ToolCalls()) > 0:
+		var calls []string
+		for _, call := range message.ToolCalls() {
+			calls = append(calls, call.Name()+"("+call.Arguments()+")")
+		}
+		return fmt.Sprintf("%s: %s [called %s]", message.Role(), message.Content(), strings.Join(calls, ", "))
+	case message.Role() == types.RoleTool:
+		return "tool result: " + message.Content()
+	default:
+		return message.Role() + ": " + message.Content()
+	}
+}
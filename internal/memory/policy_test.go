@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+type summaryProvider struct {
+	messages []types.Message
+	err      error
+}
+
+func (p *summaryProvider) Name() string {
+	return "SummaryProvider"
+}
+
+func (p *summaryProvider) AvailableModels() []provider.Model {
+	return nil
+}
+
+func (p *summaryProvider) GetModel(modelName string) (provider.Model, error) {
+	return nil, nil
+}
+
+func (p *summaryProvider) AvailableRequestParameters(modelName string) []types.Parameter {
+	return nil
+}
+
+func (p *summaryProvider) Config() map[string]any {
+	return nil
+}
+
+func (p *summaryProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *summaryProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p.messages = messages
+	if p.err != nil {
+		return types.GenerateTextResult{}, p.err
+	}
+	return types.NewGenerateTextResult(" The user is Ada. ", types.NewTokenUsage(1, 1, 2)), nil
+}
+
+func (p *summaryProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, messages, modelName, requestParameters)
+}
+
+func (p *summaryProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func toolConversation() []types.Message {
+	return []types.Message{
+		types.NewSystemMessage("Be brief."),
+		types.NewUserMessage("Weather in Paris and Rome?"),
+		types.NewAssistantMessage("", types.NewToolCall("call_1", "get_weather", `{"city":"Paris"}`), types.NewToolCall("call_2", "get_weather", `{"city":"Rome"}`)),
+		types.NewToolMessage("call_1", "sunny"),
+		types.NewToolMessage("call_2", "rainy"),
+		types.NewAssistantMessage("Sunny in Paris, rainy in Rome."),
+		types.NewUserMessage("Thanks!"),
+	}
+}
+
+func contents(messages []types.Message) string {
+	var parts []string
+	for _, message := range messages {
+		parts = append(parts, message.Role()+":"+message.Content())
+	}
+	return strings.Join(parts, "|")
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Run("keeps system messages and recent turns", func(t *testing.T) {
+		messages, _ := SlidingWindow{MaxMessages: 2}.Apply(context.Background(), toolConversation())
+		expected := "system:Be brief.|assistant:Sunny in Paris, rainy in Rome.|user:Thanks!"
+		if contents(messages) != expected {
+			t.Errorf("expected %s, got %s", expected, contents(messages))
+		}
+	})
+
+	t.Run("never starts with an orphaned tool result", func(t *testing.T) {
+		messages, _ := SlidingWindow{MaxMessages: 4}.Apply(context.Background(), toolConversation())
+		expected := "system:Be brief.|assistant:Sunny in Paris, rainy in Rome.|user:Thanks!"
+		if contents(messages) != expected {
+			t.Errorf("expected %s, got %s", expected, contents(messages))
+		}
+	})
+
+	t.Run("short history is unchanged", func(t *testing.T) {
+		messages, _ := SlidingWindow{MaxMessages: 10}.Apply(context.Background(), toolConversation())
+		if len(messages) != 7 {
+			t.Errorf("expected 7 messages, got %d", len(messages))
+		}
+	})
+}
+
+func TestTokenBudget(t *testing.T) {
+	count := func(message types.Message) int { return 10 }
+
+	messages, _ := TokenBudget{MaxTokens: 45, Count: count}.Apply(context.Background(), toolConversation())
+	expected := "system:Be brief.|assistant:Sunny in Paris, rainy in Rome.|user:Thanks!"
+	if contents(messages) != expected {
+		t.Errorf("expected %s, got %s", expected, contents(messages))
+	}
+
+	messages, _ = TokenBudget{MaxTokens: 70, Count: count}.Apply(context.Background(), toolConversation())
+	if len(messages) != 7 {
+		t.Errorf("expected history within budget to be unchanged, got %d messages", len(messages))
+	}
+
+	if tokens := EstimateTokens(types.NewUserMessage("12345678")); tokens != 7 {
+		t.Errorf("expected 7 estimated tokens, got %d", tokens)
+	}
+}
+
+func TestSummarizer(t *testing.T) {
+	t.Run("summarizes older turns", func(t *testing.T) {
+		p := &summaryProvider{}
+		summarizer := Summarizer{Provider: p, Model: "gpt-4.1-mini", MaxMessages: 4, KeepMessages: 2}
+
+		messages, err := summarizer.Apply(context.Background(), toolConversation())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "system:Be brief.|system:" + SummaryPrefix + "The user is Ada.|assistant:Sunny in Paris, rainy in Rome.|user:Thanks!"
+		if contents(messages) != expected {
+			t.Errorf("expected %s, got %s", expected, contents(messages))
+		}
+
+		transcript := p.messages[1].Content()
+		if p.messages[0].Content() != DefaultSummaryInstructions || !strings.Contains(transcript, "get_weather({\"city\":\"Rome\"})") || !strings.Contains(transcript, "tool result: rainy") {
+			t.Errorf("unexpected summary request: %+v", p.messages)
+		}
+
+		messages = append(messages, types.NewUserMessage("one"), types.NewAssistantMessage("two"), types.NewUserMessage("three"))
+		messages, _ = summarizer.Apply(context.Background(), messages)
+		if !strings.Contains(p.messages[1].Content(), "earlier summary: The user is Ada.") {
+			t.Errorf("expected previous summary to be folded in, got %s", p.messages[1].Content())
+		}
+		if len(messages) != 4 || strings.Count(contents(messages), SummaryPrefix) != 1 {
+			t.Errorf("expected a single summary, got %s", contents(messages))
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		summarizer := Summarizer{Provider: &summaryProvider{err: errors.New("boom")}, MaxMessages: 2}
+		if _, err := summarizer.Apply(context.Background(), toolConversation()); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
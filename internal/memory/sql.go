@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"agentic-ai-framework/internal/types"
+)
+
+const DefaultSQLTable = "conversation_messages"
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type Placeholders func(index int) string
+
+func QuestionPlaceholders(index int) string {
+	return "?"
+}
+
+func DollarPlaceholders(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+type SQLStore struct {
+	db           *sql.DB
+	table        string
+	placeholders Placeholders
+}
+
+func NewSQLStore(ctx context.Context, db *sql.DB, table string) (*SQLStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("%w: database is required", types.ErrInvalidConfig)
+	}
+	if table == "" {
+		table = DefaultSQLTable
+	}
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("%w: invalid table name %q", types.ErrInvalidConfig, table)
+	}
+
+	store := &SQLStore{db: db, table: table, placeholders: QuestionPlaceholders}
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+table+` (
+		session_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		message TEXT NOT NULL,
+		PRIMARY KEY (session_id, position)
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create table %s: %w", table, err)
+	}
+	return store, nil
+}
+
+func (s *SQLStore) SetPlaceholders(placeholders Placeholders) {
+	if placeholders == nil {
+		placeholders = QuestionPlaceholders
+	}
+	s.placeholders = placeholders
+}
+
+func (s *SQLStore) Load(ctx context.Context, sessionID string) ([]types.Message, error) {
+	rows, err := s.db.QueryContext(ctx, s.query(`SELECT message FROM `+s.table+` WHERE session_id = ? ORDER BY position`), sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session %s: %w", sessionID, err)
+	}
+	defer rows.Close()
+
+	var messages []types.Message
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to load session %s: %w", sessionID, err)
+		}
+		var stored storedMessage
+		if err := json.Unmarshal([]byte(data), &stored); err != nil {
+			return nil, fmt.Errorf("failed to decode session %s: %w", sessionID, err)
+		}
+		message, err := decodeMessage(stored)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode session %s: %w", sessionID, err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load session %s: %w", sessionID, err)
+	}
+	return messages, nil
+}
+
+func (s *SQLStore) Append(ctx context.Context, sessionID string, messages ...types.Message) error {
+	return s.inTx(ctx, sessionID, func(tx *sql.Tx) error {
+		var next int
+		row := tx.QueryRowContext(ctx, s.query(`SELECT COALESCE(MAX(position) + 1, 0) FROM `+s.table+` WHERE session_id = ?`), sessionID)
+		if err := row.Scan(&next); err != nil {
+			return err
+		}
+		return s.insert(ctx, tx, sessionID, next, messages)
+	})
+}
+
+func (s *SQLStore) Replace(ctx context.Context, sessionID string, messages []types.Message) error {
+	return s.inTx(ctx, sessionID, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, s.query(`DELETE FROM `+s.table+` WHERE session_id = ?`), sessionID); err != nil {
+			return err
+		}
+		return s.insert(ctx, tx, sessionID, 0, messages)
+	})
+}
+
+func (s *SQLStore) Clear(ctx context.Context, sessionID string) error {
+	if _, err := s.db.ExecContext(ctx, s.query(`DELETE FROM `+s.table+` WHERE session_id = ?`), sessionID); err != nil {
+		return fmt.Errorf("failed to clear session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+func (s *SQLStore) insert(ctx context.Context, tx *sql.Tx, sessionID string, position int, messages []types.Message) error {
+	for i, message := range messages {
+		data, err := json.Marshal(encodeMessage(message))
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, s.query(`INSERT INTO `+s.table+` (session_id, position, message) VALUES (?, ?, ?)`), sessionID, position+i, string(data))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) query(query string) string {
+	parts := strings.Split(query, "?")
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteString(s.placeholders(i))
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+func (s *SQLStore) inTx(ctx context.Context, sessionID string, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save session %s: %w", sessionID, err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to save session %s: %w", sessionID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save session %s: %w", sessionID, err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+
+	_ "modernc.org/sqlite"
+)
+
+func openSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "memory.db"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func storedPositions(t *testing.T, db *sql.DB, table string, sessionID string) []int64 {
+	t.Helper()
+	rows, err := db.Query(`SELECT position FROM `+table+` WHERE session_id = ? ORDER BY position`, sessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rows.Close()
+	var positions []int64
+	for rows.Next() {
+		var position int64
+		if err := rows.Scan(&position); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+func TestSQLStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("append numbers positions per session", func(t *testing.T) {
+		db := openSQLiteDB(t)
+		store, err := NewSQLStore(ctx, db, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Append(ctx, "a", sampleMessages()[:2]...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Append(ctx, "b", types.NewUserMessage("other")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Append(ctx, "a", sampleMessages()[2:]...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if positions := storedPositions(t, db, DefaultSQLTable, "a"); !slices.Equal(positions, []int64{0, 1, 2, 3, 4}) {
+			t.Errorf("expected consecutive positions, got %v", positions)
+		}
+		if positions := storedPositions(t, db, DefaultSQLTable, "b"); !slices.Equal(positions, []int64{0}) {
+			t.Errorf("expected session b to be numbered separately, got %v", positions)
+		}
+		if _, err := NewSQLStore(ctx, db, ""); err != nil {
+			t.Errorf("expected an existing table to be reused, got %v", err)
+		}
+	})
+
+	t.Run("load returns messages in position order", func(t *testing.T) {
+		db := openSQLiteDB(t)
+		store, _ := NewSQLStore(ctx, db, "history")
+		scratch, _ := NewSQLStore(ctx, db, "scratch")
+		scratch.Append(ctx, "a", sampleMessages()...)
+		if _, err := db.Exec(`INSERT INTO history SELECT session_id, position, message FROM scratch ORDER BY position DESC`); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		messages, err := store.Load(ctx, "a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(messages) != 5 || messages[0].Role() != types.RoleSystem || messages[4].Content() != "A cat." {
+			t.Fatalf("unexpected messages: %+v", messages)
+		}
+		if calls := messages[2].ToolCalls(); len(calls) != 1 || calls[0].Name() != "lookup" {
+			t.Errorf("expected tool calls to round-trip, got %+v", calls)
+		}
+		if parts := messages[1].Parts(); len(parts) != 3 || string(parts[2].Data()) != "hello" {
+			t.Errorf("expected content parts to round-trip, got %+v", parts)
+		}
+
+		if messages, err := store.Load(ctx, "missing"); err != nil || len(messages) != 0 {
+			t.Errorf("expected empty history, got %+v, %v", messages, err)
+		}
+	})
+
+	t.Run("replace and clear", func(t *testing.T) {
+		db := openSQLiteDB(t)
+		store, _ := NewSQLStore(ctx, db, "")
+		store.Append(ctx, "a", sampleMessages()...)
+		store.Append(ctx, "b", types.NewUserMessage("other"))
+
+		if err := store.Replace(ctx, "a", []types.Message{types.NewUserMessage("kept")}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		store.Append(ctx, "a", types.NewAssistantMessage("next"))
+		if messages, _ := store.Load(ctx, "a"); len(messages) != 2 || messages[0].Content() != "kept" || messages[1].Content() != "next" {
+			t.Errorf("unexpected replaced history: %+v", messages)
+		}
+
+		if err := store.Clear(ctx, "a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if messages, _ := store.Load(ctx, "a"); len(messages) != 0 {
+			t.Errorf("expected cleared session, got %+v", messages)
+		}
+		if messages, _ := store.Load(ctx, "b"); len(messages) != 1 {
+			t.Errorf("expected session b to be untouched, got %+v", messages)
+		}
+	})
+
+	t.Run("failed replace rolls back", func(t *testing.T) {
+		db := openSQLiteDB(t)
+		store, _ := NewSQLStore(ctx, db, "")
+		store.Append(ctx, "a", sampleMessages()...)
+
+		_, err := db.Exec(`CREATE TRIGGER reject_lost BEFORE INSERT ON ` + DefaultSQLTable + ` WHEN NEW.message LIKE '%lost%' BEGIN SELECT RAISE(ABORT, 'rejected'); END`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Replace(ctx, "a", []types.Message{types.NewUserMessage("kept"), types.NewUserMessage("lost")}); err == nil {
+			t.Fatal("expected error")
+		}
+		if messages, _ := store.Load(ctx, "a"); len(messages) != 5 {
+			t.Errorf("expected the original history after rollback, got %+v", messages)
+		}
+	})
+
+	t.Run("dollar placeholders", func(t *testing.T) {
+		db := openSQLiteDB(t)
+		store, _ := NewSQLStore(ctx, db, "")
+		store.SetPlaceholders(DollarPlaceholders)
+		if got := store.query(`INSERT INTO t (a, b) VALUES (?, ?)`); got != `INSERT INTO t (a, b) VALUES ($1, $2)` {
+			t.Errorf("unexpected query: %s", got)
+		}
+
+		if err := store.Append(ctx, "a", types.NewUserMessage("one")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Append(ctx, "a", types.NewAssistantMessage("two")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if messages, err := store.Load(ctx, "a"); err != nil || len(messages) != 2 || messages[1].Content() != "two" {
+			t.Errorf("unexpected history: %+v, %v", messages, err)
+		}
+		if err := store.Clear(ctx, "a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		if _, err := NewSQLStore(ctx, nil, ""); !errors.Is(err, types.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for a nil database, got %v", err)
+		}
+		db := openSQLiteDB(t)
+		if _, err := NewSQLStore(ctx, db, "messages; DROP TABLE users"); !errors.Is(err, types.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig for an invalid table name, got %v", err)
+		}
+	})
+}
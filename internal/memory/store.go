@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type Store interface {
+	Load(ctx context.Context, sessionID string) ([]types.Message, error)
+	Append(ctx context.Context, sessionID string, messages ...types.Message) error
+	Replace(ctx context.Context, sessionID string, messages []types.Message) error
+	Clear(ctx context.Context, sessionID string) error
+}
+
+type InMemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string][]types.Message
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{sessions: make(map[string][]types.Message)}
+}
+
+func (s *InMemoryStore) Load(ctx context.Context, sessionID string) ([]types.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]types.Message(nil), s.sessions[sessionID]...), nil
+}
+
+func (s *InMemoryStore) Append(ctx context.Context, sessionID string, messages ...types.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID] = append(s.sessions[sessionID], messages...)
+	return nil
+}
+
+func (s *InMemoryStore) Replace(ctx context.Context, sessionID string, messages []types.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID] = append([]types.Message(nil), messages...)
+	return nil
+}
+
+func (s *InMemoryStore) Clear(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+type storedMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content,omitempty"`
+	ToolCalls  []storedToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+	Parts      []storedPart     `json:"parts,omitempty"`
+}
+
+type storedToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type storedPart struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func encodeMessage(message types.Message) storedMessage {
+	stored := storedMessage{
+		Role:       message.Role(),
+		Content:    message.Content(),
+		ToolCallID: message.ToolCallID(),
+	}
+	for _, call := range message.ToolCalls() {
+		stored.ToolCalls = append(stored.ToolCalls, storedToolCall{ID: call.ID(), Name: call.Name(), Arguments: call.Arguments()})
+	}
+	for _, part := range message.Parts() {
+		stored.Parts = append(stored.Parts, storedPart{
+			Type:     part.Type(),
+			Text:     part.Text(),
+			URL:      part.URL(),
+			Data:     part.Data(),
+			MimeType: part.MimeType(),
+			Filename: part.Filename(),
+			Detail:   part.Detail(),
+		})
+	}
+	return stored
+}
+
+func decodeMessage(stored storedMessage) (types.Message, error) {
+	switch stored.Role {
+	case types.RoleAssistant:
+		var calls []types.ToolCall
+		for _, call := range stored.ToolCalls {
+			calls = append(calls, types.NewToolCall(call.ID, call.Name, call.Arguments))
+		}
+		return types.NewAssistantMessage(stored.Content, calls...), nil
+	case types.RoleTool:
+		return types.NewToolMessage(stored.ToolCallID, stored.Content), nil
+	case types.RoleUser:
+		if len(stored.Parts) == 0 {
+			return types.NewUserMessage(stored.Content), nil
+		}
+		var parts []types.ContentPart
+		for _, part := range stored.Parts {
+			switch part.Type {
+			case types.ContentPartText:
+				parts = append(parts, types.NewTextPart(part.Text))
+			case types.ContentPartImage:
+				if part.URL != "" {
+					parts = append(parts, types.NewImageURLPart(part.URL).WithDetail(part.Detail))
+				} else {
+					parts = append(parts, types.NewImagePart(part.Data, part.MimeType).WithDetail(part.Detail))
+				}
+			case types.ContentPartFile:
+				parts = append(parts, types.NewFilePart(part.Filename, part.Data, part.MimeType))
+			default:
+				return types.Message{}, fmt.Errorf("unknown content part type %q", part.Type)
+			}
+		}
+		return types.NewUserMessageWithParts(parts...), nil
+	case types.RoleSystem:
+		return types.NewSystemMessage(stored.Content), nil
+	default:
+		return types.Message{}, fmt.Errorf("unknown message role %q", stored.Role)
+	}
+}
+
+func marshalMessages(messages []types.Message) ([]byte, error) {
+	stored := make([]storedMessage, len(messages))
+	for i, message := range messages {
+		stored[i] = encodeMessage(message)
+	}
+	return json.Marshal(stored)
+}
+
+func unmarshalMessages(data []byte) ([]types.Message, error) {
+	var stored []storedMessage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	messages := make([]types.Message, len(stored))
+	for i, message := range stored {
+		decoded, err := decodeMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		messages[i] = decoded
+	}
+	return messages, nil
+}
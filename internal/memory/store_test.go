@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func sampleMessages() []types.Message {
+	return []types.Message{
+		types.NewSystemMessage("Be brief."),
+		types.NewUserMessageWithParts(types.NewTextPart("What is this?"), types.NewImageURLPart("https://example.com/cat.png").WithDetail("low"), types.NewFilePart("notes.txt", []byte("hello"), "")),
+		types.NewAssistantMessage("", types.NewToolCall("call_1", "lookup", `{"q":"cat"}`)),
+		types.NewToolMessage("call_1", "a cat"),
+		types.NewAssistantMessage("A cat."),
+	}
+}
+
+func TestInMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryStore()
+	store.Append(ctx, "a", types.NewUserMessage("one"))
+	store.Append(ctx, "a", types.NewAssistantMessage("two"))
+	store.Append(ctx, "b", types.NewUserMessage("other"))
+
+	messages, _ := store.Load(ctx, "a")
+	if len(messages) != 2 || messages[1].Content() != "two" {
+		t.Errorf("unexpected session a: %+v", messages)
+	}
+
+	store.Replace(ctx, "a", messages[1:])
+	if messages, _ := store.Load(ctx, "a"); len(messages) != 1 || messages[0].Content() != "two" {
+		t.Errorf("expected replaced history, got %+v", messages)
+	}
+
+	store.Clear(ctx, "a")
+	if messages, _ := store.Load(ctx, "a"); len(messages) != 0 {
+		t.Errorf("expected cleared session, got %+v", messages)
+	}
+	if messages, _ := store.Load(ctx, "b"); len(messages) != 1 {
+		t.Errorf("expected session b to be untouched, got %+v", messages)
+	}
+}
+
+func TestMessageEncoding(t *testing.T) {
+	data, err := marshalMessages(sampleMessages())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages, err := unmarshalMessages(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(messages) != 5 {
+		t.Fatalf("expected 5 messages, got %d", len(messages))
+	}
+	parts := messages[1].Parts()
+	if len(parts) != 3 || parts[1].URL() != "https://example.com/cat.png" || parts[1].Detail() != "low" || string(parts[2].Data()) != "hello" || parts[2].MimeType() != "text/plain" {
+		t.Errorf("unexpected parts: %+v", parts)
+	}
+	if messages[1].Content() != "What is this?" {
+		t.Errorf("unexpected content: %s", messages[1].Content())
+	}
+	calls := messages[2].ToolCalls()
+	if len(calls) != 1 || calls[0].ID() != "call_1" || calls[0].Arguments() != `{"q":"cat"}` {
+		t.Errorf("unexpected tool calls: %+v", calls)
+	}
+	if messages[3].Role() != types.RoleTool || messages[3].ToolCallID() != "call_1" {
+		t.Errorf("unexpected tool message: %+v", messages[3])
+	}
+
+	if _, err := unmarshalMessages([]byte(`[{"role":"narrator"}]`)); err == nil {
+		t.Error("expected error for unknown role")
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func Logging(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+			started := time.Now()
+			result, err := next(ctx, request)
+			attrs := []any{
+				slog.String("provider", request.Provider),
+				slog.String("model", request.Model),
+				slog.Int("messages", len(request.Messages)),
+				slog.Int("tools", len(request.Tools)),
+				slog.Duration("duration", time.Since(started)),
+			}
+			if err != nil {
+				logger.ErrorContext(ctx, "provider call failed", append(attrs, slog.Any("error", err))...)
+				return result, err
+			}
+			logger.InfoContext(ctx, "provider call",
+				append(attrs,
+					slog.String("finish_reason", result.FinishReason()),
+					slog.Int("prompt_tokens", result.Usage().PromptTokens()),
+					slog.Int("completion_tokens", result.Usage().CompletionTokens()),
+				)...)
+			return result, nil
+		}
+	}
+}
+
+func Redact(redact func(string) string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+			messages := make([]types.Message, len(request.Messages))
+			for i, message := range request.Messages {
+				messages[i] = redactMessage(message, redact)
+			}
+			request.Messages = messages
+			return next(ctx, request)
+		}
+	}
+}
+
+func Timeout(timeout time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+func redactMessage(message types.Message, redact func(string) string) types.Message {
+	switch message.Role() {
+	case types.RoleAssistant:
+		return types.NewAssistantMessage(redact(message.Content()), message.ToolCalls()...)
+	case types.RoleTool:
+		return types.NewToolMessage(message.ToolCallID(), redact(message.Content()))
+	case types.RoleUser:
+		if len(message.Parts()) == 0 {
+			return types.NewUserMessage(redact(message.Content()))
+		}
+		parts := make([]types.ContentPart, len(message.Parts()))
+		for i, part := range message.Parts() {
+			if part.Type() == types.ContentPartText {
+				part = types.NewTextPart(redact(part.Text()))
+			}
+			parts[i] = part
+		}
+		return types.NewUserMessageWithParts(parts...)
+	default:
+		return types.NewMessage(message.Role(), redact(message.Content()))
+	}
+}
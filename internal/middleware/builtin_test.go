@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	p := Wrap(&recordingProvider{}, Logging(logger))
+	if _, err := p.GenerateText(context.Background(), "Hello", "gpt-4.1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	line := buf.String()
+	for _, expected := range []string{"msg=\"provider call\"", "model=gpt-4.1", "prompt_tokens=3", "completion_tokens=2", "finish_reason=stop"} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("expected %q in log line %q", expected, line)
+		}
+	}
+
+	buf.Reset()
+	p = Wrap(&recordingProvider{err: errors.New("boom")}, Logging(logger))
+	if _, err := p.GenerateText(context.Background(), "Hello", "gpt-4.1", nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if line := buf.String(); !strings.Contains(line, "level=ERROR") || !strings.Contains(line, "error=boom") {
+		t.Errorf("unexpected error log line %q", line)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	inner := &recordingProvider{}
+	p := Wrap(inner, Redact(func(text string) string {
+		return strings.ReplaceAll(text, "555-0100", "[phone]")
+	}))
+
+	messages := []types.Message{
+		types.NewSystemMessage("Caller 555-0100"),
+		types.NewUserMessage("Call me at 555-0100"),
+		types.NewUserMessageWithParts(types.NewTextPart("555-0100"), types.NewImageURLPart("https://example.com/a.png")),
+		types.NewAssistantMessage("", types.NewToolCall("call_1", "dial", `{"n":"555-0100"}`)),
+		types.NewToolMessage("call_1", "dialed 555-0100"),
+	}
+	if _, err := p.GenerateChat(context.Background(), messages, "gpt-4.1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := inner.messages
+	if sent[0].Content() != "Caller [phone]" || sent[1].Content() != "Call me at [phone]" || sent[4].Content() != "dialed [phone]" || sent[4].ToolCallID() != "call_1" {
+		t.Errorf("unexpected redacted messages: %+v", sent)
+	}
+	if parts := sent[2].Parts(); parts[0].Text() != "[phone]" || parts[1].URL() != "https://example.com/a.png" {
+		t.Errorf("unexpected redacted parts: %+v", parts)
+	}
+	if len(sent[3].ToolCalls()) != 1 {
+		t.Errorf("expected tool calls to be kept, got %+v", sent[3])
+	}
+	if messages[1].Content() != "Call me at 555-0100" {
+		t.Error("expected caller messages to be untouched")
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	slow := func(next Handler) Handler {
+		return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+			<-ctx.Done()
+			return next(ctx, request)
+		}
+	}
+	p := Wrap(&recordingProvider{}, Timeout(10*time.Millisecond), slow)
+
+	if _, err := p.GenerateText(context.Background(), "Hello", "gpt-4.1", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+type Request struct {
+	Provider            string
+	BaseURL             string
+	Model               string
+	Messages            []types.Message
+	Tools               []types.ToolDefinition
+	Parameters          map[string]any
+	AvailableParameters []types.Parameter
+	deltas              func(textDelta string)
+}
+
+func (r Request) Streaming() bool {
+	return r.deltas != nil
+}
+
+type Handler func(ctx context.Context, request Request) (types.GenerateTextResult, error)
+
+type Middleware func(next Handler) Handler
+
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next Handler) Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+type WrappedProvider struct {
+	provider.Provider
+	baseURL string
+	handler Handler
+}
+
+func Wrap(p provider.Provider, middlewares ...Middleware) *WrappedProvider {
+	wrapped := &WrappedProvider{Provider: p}
+	wrapped.baseURL, _ = p.Config()["base_url"].(string)
+	wrapped.handler = Chain(middlewares...)(wrapped.call)
+	return wrapped
+}
+
+func (p *WrappedProvider) Unwrap() provider.Provider {
+	return p.Provider
+}
+
+func (p *WrappedProvider) ResolveModel(ctx context.Context, modelName string) (provider.Model, error) {
+	return provider.ResolveModel(ctx, p.Provider, modelName)
+}
+
+func (p *WrappedProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *WrappedProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateWithTools(ctx, messages, nil, modelName, requestParameters)
+}
+
+func (p *WrappedProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.handler(ctx, p.request(ctx, modelName, messages, tools, requestParameters))
+}
+
+func (p *WrappedProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	chunks := make(chan types.StreamChunk)
+	started := make(chan error, 1)
+	go func() {
+		defer close(chunks)
+		streaming := false
+		start := func() {
+			if !streaming {
+				streaming = true
+				started <- nil
+			}
+		}
+		request := p.request(ctx, modelName, []types.Message{types.NewUserMessage(prompt)}, nil, requestParameters)
+		request.deltas = func(textDelta string) {
+			start()
+			sendChunk(ctx, chunks, types.NewStreamDelta(textDelta))
+		}
+		result, err := p.handler(ctx, request)
+		if !streaming {
+			if err != nil {
+				started <- err
+				return
+			}
+			start()
+			if text := result.TextContent(); text != "" {
+				sendChunk(ctx, chunks, types.NewStreamDelta(text))
+			}
+		}
+		if err != nil {
+			sendChunk(ctx, chunks, types.NewStreamError(err))
+			return
+		}
+		sendChunk(ctx, chunks, types.NewStreamResult(result))
+	}()
+	if err := <-started; err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+func (p *WrappedProvider) AvailableEmbeddingModels() []provider.Model {
+	if embedder, ok := p.Provider.(provider.Embedder); ok {
+		return embedder.AvailableEmbeddingModels()
+	}
+	return nil
+}
+
+func (p *WrappedProvider) Embed(ctx context.Context, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	embedder, ok := p.Provider.(provider.Embedder)
+	if !ok {
+		return types.EmbeddingResult{}, fmt.Errorf("%w: provider %s does not support embeddings", types.ErrNotSupported, p.Name())
+	}
+	return embedder.Embed(ctx, inputs, modelName, requestParameters)
+}
+
+func (p *WrappedProvider) request(ctx context.Context, modelName string, messages []types.Message, tools []types.ToolDefinition, requestParameters map[string]any) Request {
+	request := Request{
+		Provider:   p.Name(),
+		BaseURL:    p.baseURL,
+		Model:      modelName,
+		Messages:   append([]types.Message(nil), messages...),
+		Tools:      tools,
+		Parameters: maps.Clone(requestParameters),
+	}
+	if model, err := provider.ResolveModel(ctx, p.Provider, modelName); err == nil && model != nil {
+		request.AvailableParameters = model.AvailableRequestParameters()
+	}
+	return request
+}
+
+func (p *WrappedProvider) call(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+	if request.Streaming() {
+		return p.stream(ctx, request)
+	}
+	if len(request.Tools) == 0 {
+		return p.Provider.GenerateChat(ctx, request.Messages, request.Model, request.Parameters)
+	}
+	return p.Provider.GenerateWithTools(ctx, request.Messages, request.Tools, request.Model, request.Parameters)
+}
+
+func (p *WrappedProvider) stream(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+	if len(request.Messages) != 1 || request.Messages[0].Role() != types.RoleUser || len(request.Tools) > 0 {
+		return types.GenerateTextResult{}, fmt.Errorf("%w: streaming accepts a single user prompt without tools", types.ErrNotSupported)
+	}
+	chunks, err := p.Provider.StreamText(ctx, request.Messages[0].Content(), request.Model, request.Parameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+
+	var result types.GenerateTextResult
+	var streamErr error
+	done := false
+	for chunk := range chunks {
+		switch {
+		case chunk.Err() != nil:
+			streamErr = chunk.Err()
+		case chunk.Done():
+			result, done = chunk.Result(), true
+		case streamErr == nil:
+			request.deltas(chunk.TextDelta())
+		}
+	}
+	if streamErr != nil {
+		return types.GenerateTextResult{}, streamErr
+	}
+	if !done {
+		if err := ctx.Err(); err != nil {
+			return types.GenerateTextResult{}, err
+		}
+		return types.GenerateTextResult{}, fmt.Errorf("stream ended without a result: %w", io.ErrUnexpectedEOF)
+	}
+	return result, nil
+}
+
+func sendChunk(ctx context.Context, chunks chan<- types.StreamChunk, chunk types.StreamChunk) {
+	select {
+	case chunks <- chunk:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,273 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+type recordingProvider struct {
+	method   string
+	messages []types.Message
+	tools    []types.ToolDefinition
+	params   map[string]any
+	err      error
+}
+
+func (p *recordingProvider) Name() string {
+	return "RecordingProvider"
+}
+
+func (p *recordingProvider) AvailableModels() []provider.Model {
+	return nil
+}
+
+func (p *recordingProvider) GetModel(modelName string) (provider.Model, error) {
+	return nil, nil
+}
+
+func (p *recordingProvider) AvailableRequestParameters(modelName string) []types.Parameter {
+	return nil
+}
+
+func (p *recordingProvider) Config() map[string]any {
+	return nil
+}
+
+func (p *recordingProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *recordingProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p.method, p.messages, p.params = "chat", messages, requestParameters
+	return p.respond(ctx)
+}
+
+func (p *recordingProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p.method, p.messages, p.tools, p.params = "tools", messages, tools, requestParameters
+	return p.respond(ctx)
+}
+
+func (p *recordingProvider) respond(ctx context.Context) (types.GenerateTextResult, error) {
+	if err := ctx.Err(); err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	if p.err != nil {
+		return types.GenerateTextResult{}, p.err
+	}
+	return types.NewGenerateTextResult("reply", types.NewTokenUsage(3, 2, 5)).WithFinishReason(types.FinishReasonStop), nil
+}
+
+func (p *recordingProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	p.method, p.messages, p.params = "stream", []types.Message{types.NewUserMessage(prompt)}, requestParameters
+	result, err := p.respond(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(chan types.StreamChunk, 3)
+	chunks <- types.NewStreamDelta("rep")
+	chunks <- types.NewStreamDelta("ly")
+	chunks <- types.NewStreamResult(result)
+	close(chunks)
+	return chunks, nil
+}
+
+func collectStream(t *testing.T, chunks <-chan types.StreamChunk) ([]string, types.GenerateTextResult) {
+	t.Helper()
+	var deltas []string
+	var result types.GenerateTextResult
+	for chunk := range chunks {
+		switch {
+		case chunk.Err() != nil:
+			t.Fatalf("unexpected stream error: %v", chunk.Err())
+		case chunk.Done():
+			result = chunk.Result()
+		default:
+			deltas = append(deltas, chunk.TextDelta())
+		}
+	}
+	return deltas, result
+}
+
+func tracing(name string, trace *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+			*trace = append(*trace, name+" before")
+			result, err := next(ctx, request)
+			*trace = append(*trace, name+" after")
+			return result, err
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("runs middlewares in order", func(t *testing.T) {
+		var trace []string
+		p := Wrap(&recordingProvider{}, tracing("outer", &trace), tracing("inner", &trace))
+
+		if _, err := p.GenerateText(context.Background(), "Hello", "gpt-4.1", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{"outer before", "inner before", "inner after", "outer after"}
+		if len(trace) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, trace)
+		}
+		for i := range expected {
+			if trace[i] != expected[i] {
+				t.Errorf("expected %v, got %v", expected, trace)
+			}
+		}
+	})
+
+	t.Run("middlewares see and rewrite the request", func(t *testing.T) {
+		inner := &recordingProvider{}
+		var seen Request
+		p := Wrap(inner, func(next Handler) Handler {
+			return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+				seen = request
+				request.Parameters["temperature"] = 0.0
+				return next(ctx, request)
+			}
+		})
+
+		params := map[string]any{"temperature": 0.7}
+		tools := []types.ToolDefinition{types.NewToolDefinition("lookup", "Look up", nil)}
+		_, err := p.GenerateWithTools(context.Background(), []types.Message{types.NewUserMessage("Hi")}, tools, "gpt-4.1", params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if seen.Provider != "RecordingProvider" || seen.Model != "gpt-4.1" || len(seen.Messages) != 1 || len(seen.Tools) != 1 {
+			t.Errorf("unexpected request: %+v", seen)
+		}
+		if inner.method != "tools" || inner.params["temperature"] != 0.0 {
+			t.Errorf("expected rewritten request to reach the provider, got %s %v", inner.method, inner.params)
+		}
+		if params["temperature"] != 0.7 {
+			t.Errorf("expected caller parameters to be untouched, got %v", params)
+		}
+	})
+
+	t.Run("chat calls without tools", func(t *testing.T) {
+		inner := &recordingProvider{}
+		if _, err := Wrap(inner).GenerateChat(context.Background(), []types.Message{types.NewUserMessage("Hi")}, "gpt-4.1", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if inner.method != "chat" {
+			t.Errorf("expected GenerateChat on the provider, got %s", inner.method)
+		}
+	})
+
+	t.Run("middlewares can short-circuit and see errors", func(t *testing.T) {
+		inner := &recordingProvider{err: errors.New("boom")}
+		var seenErr error
+		p := Wrap(inner, func(next Handler) Handler {
+			return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+				result, err := next(ctx, request)
+				seenErr = err
+				return result, err
+			}
+		}, func(next Handler) Handler {
+			return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+				if request.Model == "blocked" {
+					return types.GenerateTextResult{}, errors.New("model blocked")
+				}
+				return next(ctx, request)
+			}
+		})
+
+		if _, err := p.GenerateText(context.Background(), "Hi", "blocked", nil); err == nil || inner.method != "" {
+			t.Errorf("expected blocked call not to reach the provider, got %v", err)
+		}
+		if _, err := p.GenerateText(context.Background(), "Hi", "gpt-4.1", nil); err == nil || seenErr == nil || seenErr.Error() != "boom" {
+			t.Errorf("expected provider error to pass through middlewares, got %v", seenErr)
+		}
+	})
+
+	t.Run("streamed calls go through the chain", func(t *testing.T) {
+		inner := &recordingProvider{}
+		var seen Request
+		var seenResult types.GenerateTextResult
+		p := Wrap(inner, func(next Handler) Handler {
+			return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+				request.Parameters["temperature"] = 0.0
+				result, err := next(ctx, request)
+				seen, seenResult = request, result
+				return result, err
+			}
+		})
+
+		chunks, err := p.StreamText(context.Background(), "Hi", "gpt-4.1", map[string]any{"temperature": 0.7})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		deltas, result := collectStream(t, chunks)
+		if len(deltas) != 2 || deltas[0] != "rep" || deltas[1] != "ly" {
+			t.Errorf("expected the provider's deltas, got %v", deltas)
+		}
+		if result.Usage().TotalTokens() != 5 {
+			t.Errorf("expected the final result with usage, got %+v", result.Usage())
+		}
+		if !seen.Streaming() || seen.Model != "gpt-4.1" || len(seen.Messages) != 1 || seen.Messages[0].Content() != "Hi" {
+			t.Errorf("expected the middleware to see a streamed request, got %+v", seen)
+		}
+		if seenResult.Usage().TotalTokens() != 5 || seenResult.FinishReason() != types.FinishReasonStop {
+			t.Errorf("expected the middleware to see the final result, got %+v", seenResult)
+		}
+		if inner.method != "stream" || inner.params["temperature"] != 0.0 {
+			t.Errorf("expected rewritten request to reach StreamText, got %s %v", inner.method, inner.params)
+		}
+	})
+
+	t.Run("short-circuited streams replay the result", func(t *testing.T) {
+		inner := &recordingProvider{}
+		p := Wrap(inner, func(next Handler) Handler {
+			return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+				return types.NewGenerateTextResult("cached", types.NewTokenUsage(1, 1, 2)), nil
+			}
+		})
+
+		chunks, err := p.StreamText(context.Background(), "Hi", "gpt-4.1", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		deltas, result := collectStream(t, chunks)
+		if len(deltas) != 1 || deltas[0] != "cached" || result.TextContent() != "cached" {
+			t.Errorf("expected the middleware result as one delta, got %v and %q", deltas, result.TextContent())
+		}
+		if inner.method != "" {
+			t.Errorf("expected the provider not to be called, got %s", inner.method)
+		}
+	})
+
+	t.Run("stream errors before the first delta are returned", func(t *testing.T) {
+		var seenErr error
+		p := Wrap(&recordingProvider{err: errors.New("boom")}, func(next Handler) Handler {
+			return func(ctx context.Context, request Request) (types.GenerateTextResult, error) {
+				result, err := next(ctx, request)
+				seenErr = err
+				return result, err
+			}
+		})
+
+		if _, err := p.StreamText(context.Background(), "Hi", "gpt-4.1", nil); err == nil || err.Error() != "boom" {
+			t.Errorf("expected the provider error from StreamText, got %v", err)
+		}
+		if seenErr == nil || seenErr.Error() != "boom" {
+			t.Errorf("expected the middleware to see the error, got %v", seenErr)
+		}
+	})
+
+	t.Run("embeddings pass through", func(t *testing.T) {
+		p := Wrap(&recordingProvider{})
+		if _, err := p.Embed(context.Background(), []string{"a"}, "text-embedding-3-small", nil); !errors.Is(err, types.ErrNotSupported) {
+			t.Errorf("expected ErrNotSupported, got %v", err)
+		}
+		if p.Unwrap().Name() != "RecordingProvider" {
+			t.Errorf("unexpected wrapped provider: %s", p.Unwrap().Name())
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/strategy"
+	"agentic-ai-framework/internal/transport"
+	"agentic-ai-framework/internal/types"
+)
+
+type AnthropicModel struct {
+	name            string
+	parameters      []types.Parameter
+	contextWindow   int
+	maxOutputTokens int
+}
+
+func (m *AnthropicModel) Name() string {
+	return m.name
+}
+
+func (m *AnthropicModel) AvailableRequestParameters() []types.Parameter {
+	return m.parameters
+}
+
+func (m *AnthropicModel) SupportsVision() bool {
+	return true
+}
+
+func (m *AnthropicModel) ContextWindow() int {
+	return m.contextWindow
+}
+
+func (m *AnthropicModel) MaxOutputTokens() int {
+	return m.maxOutputTokens
+}
+
+type AnthropicMessagesProvider struct {
+	config          map[string]any
+	availableModels []Model
+	modelParameters map[string][]types.Parameter
+	name            string
+	apiKey          string
+	baseURL         string
+	version         string
+	httpClient      *http.Client
+	retryPolicy     transport.RetryPolicy
+	rateLimiter     RateLimiter
+}
+
+var anthropicDefaultModels = []config.ModelConfig{
+	{Name: "claude-sonnet-4-5", Vision: true, ContextWindow: 200000, MaxOutputTokens: 64000},
+	{Name: "claude-haiku-4-5", Vision: true, ContextWindow: 200000, MaxOutputTokens: 64000},
+	{Name: "claude-opus-4-1", Vision: true, ContextWindow: 200000, MaxOutputTokens: 32000},
+}
+
+func NewAnthropicMessagesProvider(configFile string) (*AnthropicMessagesProvider, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewAnthropicMessagesProviderFromConfig(cfg.Anthropic)
+}
+
+func NewAnthropicMessagesProviderFromConfig(cfg config.AnthropicConfig) (*AnthropicMessagesProvider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("%w: anthropic.api_key is required in config file", types.ErrInvalidConfig)
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1"
+	}
+
+	version := cfg.Version
+	if version == "" {
+		version = "2023-06-01"
+	}
+
+	claudeParameters := []types.Parameter{
+		types.NewParameter("max_tokens", types.ParameterInteger).WithMinimum(1).WithDefault(strategy.DefaultMessagesMaxTokens),
+		types.NewParameter("temperature", types.ParameterNumber).WithRange(0, 1).WithDefault(1.0).WithExclusive("top_p"),
+		types.NewParameter("top_p", types.ParameterNumber).WithRange(0, 1).WithExclusive("temperature"),
+		types.NewParameter("top_k", types.ParameterInteger).WithMinimum(0),
+		types.NewParameter("stop_sequences", types.ParameterStringList),
+	}
+
+	provider := &AnthropicMessagesProvider{
+		name:            "Anthropic Messages",
+		apiKey:          cfg.APIKey,
+		baseURL:         baseURL,
+		version:         version,
+		httpClient:      transport.NewContextClient(),
+		retryPolicy:     retryPolicy(cfg.Retry),
+		modelParameters: map[string][]types.Parameter{},
+		config: map[string]any{
+			"api_key":  cfg.APIKey,
+			"base_url": baseURL,
+			"version":  version,
+		},
+	}
+	for _, model := range anthropicDefaultModels {
+		provider.availableModels = append(provider.availableModels, &AnthropicModel{
+			name:            model.Name,
+			parameters:      claudeParameters,
+			contextWindow:   model.ContextWindow,
+			maxOutputTokens: model.MaxOutputTokens,
+		})
+		provider.modelParameters[model.Name] = claudeParameters
+	}
+
+	return provider, nil
+}
+
+func (p *AnthropicMessagesProvider) Name() string {
+	return p.name
+}
+
+func (p *AnthropicMessagesProvider) AvailableModels() []Model {
+	return p.availableModels
+}
+
+func (p *AnthropicMessagesProvider) AvailableRequestParameters(modelName string) []types.Parameter {
+	if params, exists := p.modelParameters[modelName]; exists {
+		return params
+	}
+	return []types.Parameter{}
+}
+
+func (p *AnthropicMessagesProvider) GetModel(modelName string) (Model, error) {
+	for _, model := range p.availableModels {
+		if model.Name() == modelName {
+			return model, nil
+		}
+	}
+	return nil, &types.ModelNotFoundError{Model: modelName, Provider: p.Name()}
+}
+
+func (p *AnthropicMessagesProvider) Config() map[string]any {
+	return p.config
+}
+
+func (p *AnthropicMessagesProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+func (p *AnthropicMessagesProvider) SetRateLimiter(limiter RateLimiter) {
+	p.rateLimiter = limiter
+}
+
+func (p *AnthropicMessagesProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *AnthropicMessagesProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateWithTools(ctx, messages, nil, modelName, requestParameters)
+}
+
+func (p *AnthropicMessagesProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	messagesRequest, err := p.buildMessagesRequest(messages, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	messagesRequest.Tools = chatTools(tools)
+	requestBody := strategy.BuildMessagesRequestBody(messagesRequest)
+
+	done, err := p.reserve(ctx, modelName, messages, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	var result types.GenerateTextResult
+	response, statusCode, err := strategy.ExecuteMessagesRequest(ctx, p.messagesConfig(), requestBody)
+	if err == nil {
+		result, err = strategy.ParseMessagesResponse(response, statusCode)
+	}
+	done(result, err)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	return result, nil
+}
+
+func (p *AnthropicMessagesProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	messages := []types.Message{types.NewUserMessage(prompt)}
+	messagesRequest, err := p.buildMessagesRequest(messages, modelName, requestParameters)
+	if err != nil {
+		return nil, err
+	}
+	messagesRequest.Stream = true
+	requestBody := strategy.BuildMessagesRequestBody(messagesRequest)
+
+	done, err := p.reserve(ctx, modelName, messages, requestParameters)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := strategy.ExecuteMessagesStreamRequest(ctx, p.messagesConfig(), requestBody)
+	if err != nil {
+		done(types.GenerateTextResult{}, err)
+		return nil, err
+	}
+	return forwardStream(ctx, chunks, done), nil
+}
+
+func (p *AnthropicMessagesProvider) reserve(ctx context.Context, modelName string, messages []types.Message, requestParameters map[string]any) (func(types.GenerateTextResult, error), error) {
+	if p.rateLimiter == nil {
+		return func(types.GenerateTextResult, error) {}, nil
+	}
+	return p.rateLimiter.Reserve(ctx, p.apiKey, modelName, messages, requestParameters)
+}
+
+func (p *AnthropicMessagesProvider) buildMessagesRequest(messages []types.Message, modelName string, requestParameters map[string]any) (strategy.MessagesRequest, error) {
+	model, err := p.GetModel(modelName)
+	if err != nil {
+		return strategy.MessagesRequest{}, err
+	}
+	if err := ValidateMessageInput(model, messages); err != nil {
+		return strategy.MessagesRequest{}, err
+	}
+
+	availableParams := p.AvailableRequestParameters(modelName)
+	if err := ValidateRequestParameters(availableParams, requestParameters, modelName); err != nil {
+		return strategy.MessagesRequest{}, err
+	}
+
+	var system []string
+	var conversation []types.Message
+	for _, msg := range messages {
+		if msg.Role() == types.RoleSystem {
+			system = append(system, msg.Content())
+			continue
+		}
+		conversation = append(conversation, msg)
+	}
+
+	return strategy.MessagesRequest{
+		Model:         modelName,
+		System:        strings.Join(system, "\n\n"),
+		Messages:      chatMessages(conversation),
+		RequestParams: NormalizeRequestParameters(availableParams, requestParameters),
+	}, nil
+}
+
+func (p *AnthropicMessagesProvider) messagesConfig() strategy.MessagesConfig {
+	return strategy.MessagesConfig{
+		BaseURL:     p.baseURL,
+		Endpoint:    "/messages",
+		APIKey:      p.apiKey,
+		Version:     p.version,
+		HTTPClient:  p.httpClient,
+		RetryPolicy: p.retryPolicy,
+	}
+}
@@ -0,0 +1,185 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+func TestNewAnthropicMessagesProvider(t *testing.T) {
+	p, err := provider.NewAnthropicMessagesProvider(writeAnthropicConfig(t, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p.Name() != "Anthropic Messages" {
+		t.Errorf("expected provider name 'Anthropic Messages', got '%s'", p.Name())
+	}
+	if len(p.AvailableModels()) == 0 {
+		t.Error("expected at least one available model")
+	}
+	providerConfig := p.Config()
+	if providerConfig["base_url"] != "https://api.anthropic.com/v1" {
+		t.Errorf("expected default base_url, got %v", providerConfig["base_url"])
+	}
+	if providerConfig["version"] != "2023-06-01" {
+		t.Errorf("expected default version, got %v", providerConfig["version"])
+	}
+	var _ provider.Provider = p
+
+	model, err := p.GetModel("claude-opus-4-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.ContextWindow() != 200000 || model.MaxOutputTokens() != 32000 {
+		t.Errorf("unexpected claude-opus-4-1 limits: %d/%d", model.ContextWindow(), model.MaxOutputTokens())
+	}
+}
+
+func TestAnthropicProviderGenerateChat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["system"] != "Be brief." {
+			t.Errorf("expected system prompt to be sent top-level, got %v", body["system"])
+		}
+		if body["max_tokens"] == nil {
+			t.Error("expected max_tokens to be sent")
+		}
+		messages := body["messages"].([]any)
+		if len(messages) != 1 {
+			t.Errorf("expected 1 non-system message, got %d", len(messages))
+		}
+		w.Write([]byte(`{"id":"msg_1","type":"message","content":[{"type":"text","text":"Hi!"}],"stop_reason":"end_turn","usage":{"input_tokens":9,"output_tokens":3}}`))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewAnthropicMessagesProvider(writeAnthropicConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := p.GenerateChat(context.Background(), []types.Message{
+		types.NewSystemMessage("Be brief."),
+		types.NewUserMessage("Hello"),
+	}, "claude-sonnet-4-5", map[string]any{"temperature": 0.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.TextContent() != "Hi!" {
+		t.Errorf("expected 'Hi!', got '%s'", result.TextContent())
+	}
+	if result.Usage().TotalTokens() != 12 {
+		t.Errorf("expected 12 total tokens, got %d", result.Usage().TotalTokens())
+	}
+}
+
+func TestAnthropicProviderValidatesRequestParameters(t *testing.T) {
+	p, err := provider.NewAnthropicMessagesProvider(writeAnthropicConfig(t, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = p.GenerateText(context.Background(), "test", "claude-sonnet-4-5", map[string]any{"frequency_penalty": 1})
+	if !errors.Is(err, types.ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter when using an unsupported parameter, got %v", err)
+	}
+
+	_, err = p.GenerateText(context.Background(), "test", "claude-sonnet-4-5", map[string]any{"temperature": 0.5, "top_p": 0.9})
+	var paramErr *types.ParameterError
+	if !errors.As(err, &paramErr) || paramErr.Reason != "cannot be combined with 'top_p'" {
+		t.Errorf("expected temperature and top_p to be rejected together, got %v", err)
+	}
+
+	_, err = p.GenerateText(context.Background(), "test", "claude-sonnet-4-5", map[string]any{"temperature": 1.5})
+	if !errors.As(err, &paramErr) || paramErr.Parameter != "temperature" {
+		t.Errorf("expected temperature above 1 to be rejected, got %v", err)
+	}
+}
+
+type anthropicLimiter struct {
+	mu       sync.Mutex
+	keys     []string
+	finished []error
+}
+
+func (l *anthropicLimiter) Reserve(ctx context.Context, key string, modelName string, messages []types.Message, requestParameters map[string]any) (func(types.GenerateTextResult, error), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.keys = append(l.keys, key)
+	return func(result types.GenerateTextResult, err error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.finished = append(l.finished, err)
+	}, nil
+}
+
+func TestAnthropicProviderRateLimits(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case fail:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"rejected"}}`))
+		case r.Header.Get("Accept") == "text/event-stream":
+			w.Write([]byte("event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_2\",\"usage\":{\"input_tokens\":5,\"output_tokens\":1}}}\n\n" +
+				"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n" +
+				"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"))
+		default:
+			w.Write([]byte(`{"id":"msg_1","type":"message","content":[{"type":"text","text":"Hi!"}],"stop_reason":"end_turn","usage":{"input_tokens":9,"output_tokens":3}}`))
+		}
+	}))
+	defer server.Close()
+
+	p, err := provider.NewAnthropicMessagesProvider(writeAnthropicConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	limiter := &anthropicLimiter{}
+	p.SetRateLimiter(limiter)
+
+	if _, err := p.GenerateText(context.Background(), "Hello", "claude-haiku-4-5", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := p.StreamText(context.Background(), "Hello", "claude-haiku-4-5", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range chunks {
+	}
+	fail = true
+	if _, err := p.GenerateText(context.Background(), "Hello", "claude-haiku-4-5", nil); err == nil {
+		t.Fatal("expected error")
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if len(limiter.keys) != 3 || limiter.keys[0] != "test-key" {
+		t.Errorf("expected each call to reserve on the API key, got %v", limiter.keys)
+	}
+	if len(limiter.finished) != 3 || limiter.finished[0] != nil || limiter.finished[1] != nil || limiter.finished[2] == nil {
+		t.Errorf("expected successful calls to complete and the failed call to be released, got %v", limiter.finished)
+	}
+}
+
+func writeAnthropicConfig(t *testing.T, baseURL string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testConfig := "anthropic:\n  api_key: \"test-key\"\n"
+	if baseURL != "" {
+		testConfig += fmt.Sprintf("  base_url: \"%s\"\n", baseURL)
+	}
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	return path
+}
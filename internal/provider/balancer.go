@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	BalanceRoundRobin  = "round_robin"
+	BalanceLeastLoaded = "least_loaded"
+	BalanceWeighted    = "weighted"
+)
+
+const DefaultEjection = 30 * time.Second
+
+type EndpointStatus struct {
+	BaseURL      string
+	Organization string
+	Project      string
+	Weight       int
+	InFlight     int
+	Requests     int
+	Failures     int
+	EjectedUntil time.Time
+}
+
+type endpoint struct {
+	apiKey       string
+	baseURL      string
+	organization string
+	project      string
+	weight       int
+	current      int
+	inFlight     int
+	requests     int
+	failures     int
+	ejectedUntil time.Time
+}
+
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	strategy  string
+	ejection  time.Duration
+	cursor    int
+	now       func() time.Time
+}
+
+func newEndpointPool(cfg config.OpenAIConfig, defaultBaseURL string) (*endpointPool, error) {
+	strategy := cfg.LoadBalancing.Strategy
+	switch strategy {
+	case "":
+		strategy = BalanceRoundRobin
+	case BalanceRoundRobin, BalanceLeastLoaded, BalanceWeighted:
+	default:
+		return nil, fmt.Errorf("%w: unknown load balancing strategy %q", types.ErrInvalidConfig, strategy)
+	}
+	ejection := cfg.LoadBalancing.Ejection
+	if ejection <= 0 {
+		ejection = DefaultEjection
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	endpoints := cfg.Endpoints
+	if len(endpoints) == 0 {
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("%w: openai.api_key is required in config file", types.ErrInvalidConfig)
+		}
+		endpoints = []config.EndpointConfig{{}}
+	}
+
+	pool := &endpointPool{strategy: strategy, ejection: ejection, now: time.Now}
+	for i, endpointConfig := range endpoints {
+		e := &endpoint{
+			apiKey:       firstNonEmpty(endpointConfig.APIKey, cfg.APIKey),
+			baseURL:      firstNonEmpty(endpointConfig.BaseURL, baseURL),
+			organization: firstNonEmpty(endpointConfig.Organization, cfg.Organization),
+			project:      firstNonEmpty(endpointConfig.Project, cfg.Project),
+			weight:       endpointConfig.Weight,
+		}
+		if e.apiKey == "" {
+			return nil, fmt.Errorf("%w: openai.endpoints[%d].api_key is required in config file", types.ErrInvalidConfig, i)
+		}
+		if e.weight < 0 {
+			return nil, fmt.Errorf("%w: openai.endpoints[%d].weight must not be negative", types.ErrInvalidConfig, i)
+		}
+		if e.weight == 0 {
+			e.weight = 1
+		}
+		pool.endpoints = append(pool.endpoints, e)
+	}
+	return pool, nil
+}
+
+func (p *endpointPool) acquire(tried map[*endpoint]bool) *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var healthy, ejected []int
+	for i, e := range p.endpoints {
+		switch {
+		case tried[e]:
+		case e.ejectedUntil.After(now):
+			ejected = append(ejected, i)
+		default:
+			healthy = append(healthy, i)
+		}
+	}
+
+	var chosen *endpoint
+	switch {
+	case len(healthy) > 0:
+		chosen = p.choose(healthy)
+	case len(ejected) > 0:
+		chosen = p.endpoints[ejected[0]]
+		for _, i := range ejected[1:] {
+			if p.endpoints[i].ejectedUntil.Before(chosen.ejectedUntil) {
+				chosen = p.endpoints[i]
+			}
+		}
+	default:
+		return nil
+	}
+	chosen.inFlight++
+	chosen.requests++
+	return chosen
+}
+
+func (p *endpointPool) choose(candidates []int) *endpoint {
+	switch p.strategy {
+	case BalanceWeighted:
+		total := 0
+		var best *endpoint
+		for _, i := range candidates {
+			e := p.endpoints[i]
+			e.current += e.weight
+			total += e.weight
+			if best == nil || e.current > best.current {
+				best = e
+			}
+		}
+		best.current -= total
+		return best
+	case BalanceLeastLoaded:
+		best := -1
+		for _, i := range p.rotate(candidates) {
+			if best < 0 || p.endpoints[i].inFlight < p.endpoints[best].inFlight {
+				best = i
+			}
+		}
+		p.cursor = best + 1
+		return p.endpoints[best]
+	default:
+		i := p.rotate(candidates)[0]
+		p.cursor = i + 1
+		return p.endpoints[i]
+	}
+}
+
+func (p *endpointPool) rotate(candidates []int) []int {
+	for j, i := range candidates {
+		if i >= p.cursor%len(p.endpoints) {
+			return append(append([]int(nil), candidates[j:]...), candidates[:j]...)
+		}
+	}
+	return candidates
+}
+
+func (p *endpointPool) release(e *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.inFlight--
+	if shouldEject(err) {
+		e.failures++
+		e.ejectedUntil = p.now().Add(p.ejection)
+	}
+}
+
+func (p *endpointPool) status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		statuses[i] = EndpointStatus{
+			BaseURL:      e.baseURL,
+			Organization: e.organization,
+			Project:      e.project,
+			Weight:       e.weight,
+			InFlight:     e.inFlight,
+			Requests:     e.requests,
+			Failures:     e.failures,
+			EjectedUntil: e.ejectedUntil,
+		}
+	}
+	return statuses
+}
+
+func shouldEject(err error) bool {
+	var apiErr *types.APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Type == "rate_limit_error")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,332 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/types"
+)
+
+type keyServer struct {
+	mu       sync.Mutex
+	requests map[string]int
+	status   map[string]int
+	failing  map[string]bool
+	headers  []http.Header
+}
+
+func newKeyServer(t *testing.T) (*keyServer, *httptest.Server) {
+	ks := &keyServer{requests: make(map[string]int), status: make(map[string]int), failing: make(map[string]bool)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		ks.mu.Lock()
+		ks.requests[key]++
+		ks.headers = append(ks.headers, r.Header.Clone())
+		status := ks.status[key]
+		failing := ks.failing[key]
+		ks.mu.Unlock()
+		if status != 0 {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":{"message":"rejected","type":"invalid_request_error"}}`))
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/embeddings"):
+			w.Write([]byte(`{"data":[{"index":0,"embedding":[0.1,0.2]}],"usage":{"prompt_tokens":1,"total_tokens":1}}`))
+		case r.Header.Get("Accept") == "text/event-stream" && failing:
+			w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\ndata: {\"error\":{\"message\":\"slow down\",\"type\":\"rate_limit_error\"}}\n\n"))
+		case r.Header.Get("Accept") == "text/event-stream":
+			w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"ok\"}}]}\n\ndata: [DONE]\n\n"))
+		default:
+			w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return ks, server
+}
+
+func pooledProvider(t *testing.T, baseURL string, strategy string, endpoints ...config.EndpointConfig) *OpenAIChatCompletionsProvider {
+	t.Helper()
+	p, err := NewOpenAIChatCompletionsProviderFromConfig(config.OpenAIConfig{
+		BaseURL:       baseURL,
+		Endpoints:     endpoints,
+		LoadBalancing: config.LoadBalancingConfig{Strategy: strategy, Ejection: time.Minute},
+		Retry:         config.RetryConfig{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return p
+}
+
+func TestNewEndpointPool(t *testing.T) {
+	cases := []config.OpenAIConfig{
+		{},
+		{Endpoints: []config.EndpointConfig{{APIKey: "key-1"}, {BaseURL: "https://eu.api.openai.com/v1"}}},
+		{APIKey: "key", Endpoints: []config.EndpointConfig{{Weight: -1}}},
+		{APIKey: "key", LoadBalancing: config.LoadBalancingConfig{Strategy: "random"}},
+	}
+	for i, cfg := range cases {
+		if _, err := NewOpenAIChatCompletionsProviderFromConfig(cfg); !errors.Is(err, types.ErrInvalidConfig) {
+			t.Errorf("case %d: expected ErrInvalidConfig, got %v", i, err)
+		}
+	}
+
+	p, err := NewOpenAIChatCompletionsProviderFromConfig(config.OpenAIConfig{
+		APIKey:       "shared-key",
+		Organization: "org-shared",
+		Endpoints:    []config.EndpointConfig{{Project: "proj_a"}, {APIKey: "eu-key", BaseURL: "https://eu.api.openai.com/v1", Weight: 2}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	endpoints := p.Endpoints()
+	if len(endpoints) != 2 || endpoints[0].BaseURL != "https://api.openai.com/v1" || endpoints[0].Organization != "org-shared" || endpoints[0].Project != "proj_a" || endpoints[0].Weight != 1 {
+		t.Errorf("expected endpoint to inherit top-level settings, got %+v", endpoints[0])
+	}
+	if endpoints[1].BaseURL != "https://eu.api.openai.com/v1" || endpoints[1].Weight != 2 || p.endpoints.endpoints[1].apiKey != "eu-key" {
+		t.Errorf("unexpected second endpoint: %+v", endpoints[1])
+	}
+}
+
+func TestProviderLoadBalancing(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round robin", func(t *testing.T) {
+		ks, server := newKeyServer(t)
+		p := pooledProvider(t, server.URL, BalanceRoundRobin, config.EndpointConfig{APIKey: "key-a"}, config.EndpointConfig{APIKey: "key-b"}, config.EndpointConfig{APIKey: "key-c"})
+
+		for range 6 {
+			if _, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if ks.requests["key-a"] != 2 || ks.requests["key-b"] != 2 || ks.requests["key-c"] != 2 {
+			t.Errorf("expected requests spread evenly, got %v", ks.requests)
+		}
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		ks, server := newKeyServer(t)
+		p := pooledProvider(t, server.URL, BalanceWeighted, config.EndpointConfig{APIKey: "key-a", Weight: 3}, config.EndpointConfig{APIKey: "key-b"})
+
+		for range 8 {
+			p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		}
+		if ks.requests["key-a"] != 6 || ks.requests["key-b"] != 2 {
+			t.Errorf("expected a 3:1 split, got %v", ks.requests)
+		}
+	})
+
+	t.Run("least loaded", func(t *testing.T) {
+		p := pooledProvider(t, "http://unused", BalanceLeastLoaded, config.EndpointConfig{APIKey: "key-a"}, config.EndpointConfig{APIKey: "key-b"}, config.EndpointConfig{APIKey: "key-c"})
+		pool := p.endpoints
+
+		first := pool.acquire(nil)
+		second := pool.acquire(nil)
+		pool.release(first, nil)
+		third := pool.acquire(nil)
+		if first.apiKey != "key-a" || second.apiKey != "key-b" || third.apiKey != "key-c" {
+			t.Fatalf("unexpected picks: %s %s %s", first.apiKey, second.apiKey, third.apiKey)
+		}
+		if fourth := pool.acquire(nil); fourth.apiKey != "key-a" {
+			t.Errorf("expected the idle endpoint to be picked, got %s", fourth.apiKey)
+		}
+	})
+
+	t.Run("sends organization and project headers", func(t *testing.T) {
+		ks, server := newKeyServer(t)
+		p := pooledProvider(t, server.URL, "", config.EndpointConfig{APIKey: "key-a", Organization: "org-a", Project: "proj_a"})
+
+		p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		if header := ks.headers[0]; header.Get("OpenAI-Organization") != "org-a" || header.Get("OpenAI-Project") != "proj_a" {
+			t.Errorf("unexpected headers: %v", header)
+		}
+	})
+}
+
+func TestProviderEjectsFailingKeys(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fails over and ejects keys returning 401 or 429", func(t *testing.T) {
+		ks, server := newKeyServer(t)
+		ks.status["key-a"] = http.StatusUnauthorized
+		ks.status["key-b"] = http.StatusTooManyRequests
+		p := pooledProvider(t, server.URL, BalanceRoundRobin, config.EndpointConfig{APIKey: "key-a"}, config.EndpointConfig{APIKey: "key-b"}, config.EndpointConfig{APIKey: "key-c"})
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		p.endpoints.now = func() time.Time { return now }
+
+		result, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		if err != nil || result.TextContent() != "ok" {
+			t.Fatalf("expected failover to the healthy key, got %v", err)
+		}
+		for range 3 {
+			p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		}
+		if ks.requests["key-a"] != 1 || ks.requests["key-b"] != 1 || ks.requests["key-c"] != 4 {
+			t.Errorf("expected ejected keys to be skipped, got %v", ks.requests)
+		}
+		endpoints := p.Endpoints()
+		if endpoints[0].Failures != 1 || !endpoints[0].EjectedUntil.Equal(now.Add(time.Minute)) || endpoints[2].InFlight != 0 {
+			t.Errorf("unexpected endpoint status: %+v", endpoints)
+		}
+
+		delete(ks.status, "key-a")
+		now = now.Add(time.Minute)
+		p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		if ks.requests["key-a"] != 2 {
+			t.Errorf("expected key to return after the ejection period, got %v", ks.requests)
+		}
+	})
+
+	t.Run("returns the last error when every key fails", func(t *testing.T) {
+		ks, server := newKeyServer(t)
+		ks.status["key-a"] = http.StatusUnauthorized
+		ks.status["key-b"] = http.StatusUnauthorized
+		p := pooledProvider(t, server.URL, BalanceRoundRobin, config.EndpointConfig{APIKey: "key-a"}, config.EndpointConfig{APIKey: "key-b"})
+
+		_, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil)
+		var apiErr *types.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 APIError, got %v", err)
+		}
+
+		delete(ks.status, "key-b")
+		if _, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil); err != nil {
+			t.Errorf("expected ejected keys to still be tried when no key is healthy, got %v", err)
+		}
+	})
+
+	t.Run("other errors do not eject", func(t *testing.T) {
+		ks, server := newKeyServer(t)
+		ks.status["key-a"] = http.StatusBadRequest
+		p := pooledProvider(t, server.URL, BalanceRoundRobin, config.EndpointConfig{APIKey: "key-a"}, config.EndpointConfig{APIKey: "key-b"})
+
+		if _, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if ks.requests["key-b"] != 0 || !p.Endpoints()[0].EjectedUntil.IsZero() {
+			t.Errorf("expected no failover for a 400, got %v", ks.requests)
+		}
+	})
+}
+
+type recordingLimiter struct {
+	mu       sync.Mutex
+	keys     []string
+	finished []error
+}
+
+func (l *recordingLimiter) Reserve(ctx context.Context, key string, modelName string, messages []types.Message, requestParameters map[string]any) (func(types.GenerateTextResult, error), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.keys = append(l.keys, key)
+	return func(result types.GenerateTextResult, err error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.finished = append(l.finished, err)
+	}, nil
+}
+
+func TestProviderRateLimitsPerKey(t *testing.T) {
+	ctx := context.Background()
+	ks, server := newKeyServer(t)
+	ks.status["key-a"] = http.StatusTooManyRequests
+	p := pooledProvider(t, server.URL, BalanceRoundRobin, config.EndpointConfig{APIKey: "key-a"}, config.EndpointConfig{APIKey: "key-b"})
+	limiter := &recordingLimiter{}
+	p.SetRateLimiter(limiter)
+
+	if _, err := p.GenerateText(ctx, "Hello", "gpt-4.1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(limiter.keys) != 2 || limiter.keys[0] != "key-a" || limiter.keys[1] != "key-b" {
+		t.Errorf("expected a reservation for each key tried, got %v", limiter.keys)
+	}
+	if len(limiter.finished) != 2 || limiter.finished[0] == nil || limiter.finished[1] != nil {
+		t.Errorf("expected the failed attempt to be released and the second completed, got %v", limiter.finished)
+	}
+
+	chunks, err := p.StreamText(ctx, "Hello", "gpt-4.1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range chunks {
+	}
+	limiter.mu.Lock()
+	if len(limiter.keys) != 3 || limiter.keys[2] != "key-b" || len(limiter.finished) != 3 || limiter.finished[2] != nil {
+		t.Errorf("expected the stream to reserve and complete once, got %v and %v", limiter.keys, limiter.finished)
+	}
+	limiter.mu.Unlock()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	if _, err := p.StreamText(streamCtx, "Hello", "gpt-4.1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		limiter.mu.Lock()
+		finished := append([]error(nil), limiter.finished...)
+		limiter.mu.Unlock()
+		if len(finished) == 4 {
+			if finished[3] != nil {
+				t.Errorf("expected an abandoned stream to keep its reservation, got %v", finished[3])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the abandoned stream to settle its reservation, got %v", finished)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := p.Embed(ctx, []string{"hello"}, "text-embedding-3-small", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if len(limiter.keys) != 5 || limiter.keys[4] != "key-b" || limiter.finished[4] != nil {
+		t.Errorf("expected embeddings to reserve on the key they use, got %v and %v", limiter.keys, limiter.finished)
+	}
+}
+
+func TestProviderStreamFailure(t *testing.T) {
+	ctx := context.Background()
+	ks, server := newKeyServer(t)
+	ks.failing["key-a"] = true
+	p := pooledProvider(t, server.URL, BalanceRoundRobin, config.EndpointConfig{APIKey: "key-a"}, config.EndpointConfig{APIKey: "key-b"})
+	limiter := &recordingLimiter{}
+	p.SetRateLimiter(limiter)
+
+	chunks, err := p.StreamText(ctx, "Hello", "gpt-4.1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var streamErr error
+	for chunk := range chunks {
+		if chunk.Err() != nil {
+			streamErr = chunk.Err()
+		}
+	}
+	if streamErr == nil {
+		t.Fatal("expected the stream to fail")
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if len(limiter.finished) != 1 || !errors.Is(limiter.finished[0], streamErr) {
+		t.Errorf("expected the reservation to be released with the stream error, got %v", limiter.finished)
+	}
+	status := p.Endpoints()[0]
+	if status.InFlight != 0 || status.Failures != 1 || status.EjectedUntil.IsZero() {
+		t.Errorf("expected the failing endpoint to be released and ejected, got %+v", status)
+	}
+}
@@ -1,19 +1,56 @@
 package provider
 
 import (
+	"context"
+	"net/http"
+
 	"agentic-ai-framework/internal/types"
 )
 
 type Model interface {
 	Name() string
-	AvailableRequestParameters() []string
+	AvailableRequestParameters() []types.Parameter
+	SupportsVision() bool
+	ContextWindow() int
+	MaxOutputTokens() int
 }
 
 type Provider interface {
 	Name() string
 	AvailableModels() []Model
 	GetModel(modelName string) (Model, error)
-	AvailableRequestParameters(modelName string) []string
+	AvailableRequestParameters(modelName string) []types.Parameter
 	Config() map[string]any
-	GenerateText(prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error)
+	GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error)
+	GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error)
+	GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error)
+	StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error)
+}
+
+type ModelResolver interface {
+	ResolveModel(ctx context.Context, modelName string) (Model, error)
+}
+
+type Embedder interface {
+	AvailableEmbeddingModels() []Model
+	Embed(ctx context.Context, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error)
+}
+
+type HTTPClientSetter interface {
+	SetHTTPClient(client *http.Client)
+}
+
+type RateLimiter interface {
+	Reserve(ctx context.Context, key string, modelName string, messages []types.Message, requestParameters map[string]any) (func(result types.GenerateTextResult, err error), error)
+}
+
+type RateLimiterSetter interface {
+	SetRateLimiter(limiter RateLimiter)
+}
+
+func ResolveModel(ctx context.Context, p Provider, modelName string) (Model, error) {
+	if resolver, ok := p.(ModelResolver); ok {
+		return resolver.ResolveModel(ctx, modelName)
+	}
+	return p.GetModel(modelName)
 }
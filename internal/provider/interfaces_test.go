@@ -6,8 +6,25 @@ import (
 
 func TestProviderInterfaceCompliance(t *testing.T) {
 	var _ Provider = &OpenAIChatCompletionsProvider{}
+	var _ Embedder = &OpenAIChatCompletionsProvider{}
+	var _ Embedder = &Registry{}
+	var _ HTTPClientSetter = &OpenAIChatCompletionsProvider{}
+	var _ RateLimiterSetter = &OpenAIChatCompletionsProvider{}
 }
 
 func TestModelInterfaceCompliance(t *testing.T) {
 	var _ Model = &OpenAIModel{}
 }
+
+func TestAnthropicProviderInterfaceCompliance(t *testing.T) {
+	var _ Provider = &AnthropicMessagesProvider{}
+	var _ Model = &AnthropicModel{}
+	var _ HTTPClientSetter = &AnthropicMessagesProvider{}
+	var _ RateLimiterSetter = &AnthropicMessagesProvider{}
+}
+
+func TestOllamaProviderInterfaceCompliance(t *testing.T) {
+	var _ Provider = &OllamaChatProvider{}
+	var _ Model = &OllamaModel{}
+	var _ HTTPClientSetter = &OllamaChatProvider{}
+}
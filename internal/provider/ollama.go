@@ -0,0 +1,356 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/strategy"
+	"agentic-ai-framework/internal/transport"
+	"agentic-ai-framework/internal/types"
+)
+
+var ollamaParameters = []types.Parameter{
+	types.NewParameter("temperature", types.ParameterNumber).WithMinimum(0).WithDefault(0.8),
+	types.NewParameter("top_p", types.ParameterNumber).WithRange(0, 1).WithDefault(0.9),
+	types.NewParameter("top_k", types.ParameterInteger).WithMinimum(0).WithDefault(40),
+	types.NewParameter("min_p", types.ParameterNumber).WithRange(0, 1).WithDefault(0.0),
+	types.NewParameter("num_ctx", types.ParameterInteger).WithMinimum(1).WithDefault(strategy.OllamaDefaultContextLength),
+	types.NewParameter("num_predict", types.ParameterInteger).WithMinimum(-2).WithDefault(-1),
+	types.NewParameter("seed", types.ParameterInteger),
+	types.NewParameter("stop", types.ParameterStringList),
+	types.NewParameter("repeat_penalty", types.ParameterNumber).WithMinimum(0).WithDefault(1.1),
+	types.NewParameter("repeat_last_n", types.ParameterInteger).WithMinimum(-1).WithDefault(64),
+	types.NewParameter("format", types.ParameterAny),
+	types.NewParameter("keep_alive", types.ParameterAny),
+}
+
+const ollamaDiscoveryRetryInterval = 10 * time.Second
+
+var ollamaVisionFamilies = map[string]bool{
+	"clip":     true,
+	"mllama":   true,
+	"gemma3":   true,
+	"qwen25vl": true,
+	"mistral3": true,
+	"llama4":   true,
+}
+
+type OllamaModel struct {
+	mu            sync.Mutex
+	name          string
+	parameters    []types.Parameter
+	family        string
+	families      []string
+	size          int64
+	contextWindow int
+	capabilities  []string
+	detailsLoaded bool
+}
+
+func (m *OllamaModel) Name() string {
+	return m.name
+}
+
+func (m *OllamaModel) AvailableRequestParameters() []types.Parameter {
+	return m.parameters
+}
+
+func (m *OllamaModel) Family() string {
+	return m.family
+}
+
+func (m *OllamaModel) SupportsVision() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.capabilities != nil {
+		return slices.Contains(m.capabilities, "vision")
+	}
+	for _, family := range m.families {
+		if ollamaVisionFamilies[family] {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *OllamaModel) ContextWindow() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.contextWindow
+}
+
+func (m *OllamaModel) loadDetails(ctx context.Context, config strategy.OllamaConfig, defaultContextLength int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.detailsLoaded {
+		return nil
+	}
+	show, err := strategy.ExecuteOllamaShowRequest(ctx, config, m.name)
+	var apiErr *types.APIError
+	if err != nil && !errors.As(err, &apiErr) {
+		return fmt.Errorf("failed to read the details of Ollama model %s: %w", m.name, err)
+	}
+	if err == nil {
+		m.contextWindow = show.ContextLength(defaultContextLength)
+		m.capabilities = show.Capabilities
+	}
+	m.detailsLoaded = true
+	return nil
+}
+
+func (m *OllamaModel) MaxOutputTokens() int {
+	return 0
+}
+
+func (m *OllamaModel) Size() int64 {
+	return m.size
+}
+
+type OllamaChatProvider struct {
+	mu              sync.RWMutex
+	config          map[string]any
+	availableModels []Model
+	modelParameters map[string][]types.Parameter
+	discovered      bool
+	discoveryErr    error
+	discoveryFailed time.Time
+	name            string
+	baseURL         string
+	contextLength   int
+	httpClient      *http.Client
+	retryPolicy     transport.RetryPolicy
+}
+
+func NewOllamaChatProvider(configFile string) (*OllamaChatProvider, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewOllamaChatProviderFromConfig(cfg.Ollama)
+}
+
+func NewOllamaChatProviderFromConfig(cfg config.OllamaConfig) (*OllamaChatProvider, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+
+	contextLength := cfg.ContextLength
+	if contextLength <= 0 {
+		contextLength = strategy.OllamaDefaultContextLength
+	}
+
+	return &OllamaChatProvider{
+		name:          "Ollama",
+		baseURL:       baseURL,
+		contextLength: contextLength,
+		httpClient:    transport.NewContextClient(),
+		retryPolicy:   retryPolicy(cfg.Retry),
+		config: map[string]any{
+			"base_url": baseURL,
+		},
+	}, nil
+}
+
+func (p *OllamaChatProvider) RefreshModels(ctx context.Context) error {
+	tags, err := strategy.ExecuteOllamaTagsRequest(ctx, p.ollamaConfig())
+	if err != nil {
+		return err
+	}
+
+	models := make([]Model, len(tags.Models))
+	modelParameters := make(map[string][]types.Parameter, len(tags.Models))
+	for i, info := range tags.Models {
+		models[i] = &OllamaModel{
+			name:       info.Name,
+			parameters: ollamaParameters,
+			family:     info.Details.Family,
+			families:   info.Details.Families,
+			size:       info.Size,
+		}
+		modelParameters[info.Name] = ollamaParameters
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.availableModels = models
+	p.modelParameters = modelParameters
+	p.discovered = true
+	p.discoveryErr = nil
+	return nil
+}
+
+func (p *OllamaChatProvider) discoverModels(ctx context.Context) error {
+	p.mu.RLock()
+	discovered, discoveryErr, discoveryFailed := p.discovered, p.discoveryErr, p.discoveryFailed
+	p.mu.RUnlock()
+	if discovered {
+		return nil
+	}
+	if discoveryErr != nil && time.Since(discoveryFailed) < ollamaDiscoveryRetryInterval {
+		return discoveryErr
+	}
+	if err := p.RefreshModels(ctx); err != nil {
+		err = fmt.Errorf("failed to discover Ollama models at %s: %w", p.baseURL, err)
+		if ctx.Err() == nil {
+			p.mu.Lock()
+			p.discoveryErr = err
+			p.discoveryFailed = time.Now()
+			p.mu.Unlock()
+		}
+		return err
+	}
+	return nil
+}
+
+func (p *OllamaChatProvider) Name() string {
+	return p.name
+}
+
+func (p *OllamaChatProvider) AvailableModels() []Model {
+	if err := p.discoverModels(context.Background()); err != nil {
+		return []Model{}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.availableModels
+}
+
+func (p *OllamaChatProvider) AvailableRequestParameters(modelName string) []types.Parameter {
+	if err := p.discoverModels(context.Background()); err != nil {
+		return []types.Parameter{}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if params, exists := p.modelParameters[modelName]; exists {
+		return params
+	}
+	if params, exists := p.modelParameters[modelName+":latest"]; exists && !strings.Contains(modelName, ":") {
+		return params
+	}
+	return []types.Parameter{}
+}
+
+func (p *OllamaChatProvider) GetModel(modelName string) (Model, error) {
+	return p.ResolveModel(context.Background(), modelName)
+}
+
+func (p *OllamaChatProvider) ResolveModel(ctx context.Context, modelName string) (Model, error) {
+	model, err := p.findModel(ctx, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.loadDetails(ctx, p.ollamaConfig(), p.contextLength); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (p *OllamaChatProvider) findModel(ctx context.Context, modelName string) (*OllamaModel, error) {
+	if err := p.discoverModels(ctx); err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	candidates := []string{modelName}
+	if !strings.Contains(modelName, ":") {
+		candidates = append(candidates, modelName+":latest")
+	}
+	for _, candidate := range candidates {
+		for _, model := range p.availableModels {
+			if model.Name() == candidate {
+				return model.(*OllamaModel), nil
+			}
+		}
+	}
+	return nil, &types.ModelNotFoundError{Model: modelName, Provider: p.Name()}
+}
+
+func (p *OllamaChatProvider) Config() map[string]any {
+	return p.config
+}
+
+func (p *OllamaChatProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+func (p *OllamaChatProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *OllamaChatProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateWithTools(ctx, messages, nil, modelName, requestParameters)
+}
+
+func (p *OllamaChatProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	chatRequest, err := p.buildChatRequest(ctx, messages, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	chatRequest.Tools = chatTools(tools)
+	requestBody := strategy.BuildOllamaChatRequestBody(chatRequest)
+
+	response, statusCode, err := strategy.ExecuteOllamaChatRequest(ctx, p.ollamaConfig(), requestBody)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+
+	return strategy.ParseOllamaChatResponse(response, statusCode)
+}
+
+func (p *OllamaChatProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	chatRequest, err := p.buildChatRequest(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+	if err != nil {
+		return nil, err
+	}
+	chatRequest.Stream = true
+	requestBody := strategy.BuildOllamaChatRequestBody(chatRequest)
+
+	return strategy.ExecuteOllamaChatStreamRequest(ctx, p.ollamaConfig(), requestBody)
+}
+
+func (p *OllamaChatProvider) buildChatRequest(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (strategy.OllamaChatRequest, error) {
+	model, err := p.ResolveModel(ctx, modelName)
+	if err != nil {
+		return strategy.OllamaChatRequest{}, err
+	}
+	if err := ValidateMessageInput(model, messages); err != nil {
+		return strategy.OllamaChatRequest{}, err
+	}
+	for _, msg := range messages {
+		for _, part := range msg.Parts() {
+			if part.Type() == types.ContentPartFile {
+				return strategy.OllamaChatRequest{}, &types.UnsupportedInputError{Model: modelName, InputType: "file"}
+			}
+			if part.URL() != "" {
+				return strategy.OllamaChatRequest{}, &types.UnsupportedInputError{Model: modelName, InputType: "image URL"}
+			}
+		}
+	}
+
+	availableParams := model.AvailableRequestParameters()
+	if err := ValidateRequestParameters(availableParams, requestParameters, modelName); err != nil {
+		return strategy.OllamaChatRequest{}, err
+	}
+
+	return strategy.OllamaChatRequest{
+		Model:         model.Name(),
+		Messages:      chatMessages(messages),
+		RequestParams: NormalizeRequestParameters(availableParams, requestParameters),
+	}, nil
+}
+
+func (p *OllamaChatProvider) ollamaConfig() strategy.OllamaConfig {
+	return strategy.OllamaConfig{
+		BaseURL:     p.baseURL,
+		HTTPClient:  p.httpClient,
+		RetryPolicy: p.retryPolicy,
+	}
+}
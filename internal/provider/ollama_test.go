@@ -0,0 +1,291 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+func newOllamaServer(t *testing.T, models ...string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			var list []map[string]any
+			for _, name := range models {
+				list = append(list, map[string]any{"name": name, "details": map[string]any{"family": "llama"}})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"models": list})
+		case "/api/show":
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			switch body["model"] {
+			case "llama3.2:latest":
+				w.Write([]byte(`{"parameters":"stop \"<|eot_id|>\"\nnum_ctx 8192","model_info":{"general.architecture":"llama","llama.context_length":131072}}`))
+			case "qwen2.5:7b":
+				w.Write([]byte(`{"capabilities":["completion","tools"],"model_info":{"general.architecture":"qwen2","qwen2.context_length":32768}}`))
+			case "gemma3:4b":
+				w.Write([]byte(`{"capabilities":["completion","vision"],"model_info":{"general.architecture":"gemma3","gemma3.context_length":131072}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"model not found"}`))
+			}
+		case "/api/chat":
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			options, _ := body["options"].(map[string]any)
+			if options["num_ctx"] != float64(4096) {
+				t.Errorf("expected num_ctx 4096 in options, got %v", options["num_ctx"])
+			}
+			w.Write([]byte(`{"model":"llama3.2:latest","message":{"role":"assistant","content":"Hello from Ollama"},"done":true,"prompt_eval_count":5,"eval_count":4}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestNewOllamaChatProviderDiscoversModels(t *testing.T) {
+	server := newOllamaServer(t, "llama3.2:latest", "qwen2.5:7b")
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	models := p.AvailableModels()
+	if len(models) != 2 {
+		t.Fatalf("expected 2 discovered models, got %d", len(models))
+	}
+	if models[0].Name() != "llama3.2:latest" || models[1].Name() != "qwen2.5:7b" {
+		t.Errorf("unexpected models: %s, %s", models[0].Name(), models[1].Name())
+	}
+	if ollamaModel, ok := models[0].(*provider.OllamaModel); !ok || ollamaModel.Family() != "llama" {
+		t.Errorf("expected OllamaModel with family 'llama', got %v", models[0])
+	}
+
+	params := p.AvailableRequestParameters("qwen2.5:7b")
+	for _, expected := range []string{"temperature", "top_p", "num_ctx", "seed"} {
+		found := false
+		for _, param := range params {
+			if param.Name() == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected parameter %s to be available, got %v", expected, params)
+		}
+	}
+	var _ provider.Provider = p
+}
+
+func TestOllamaProviderGenerateText(t *testing.T) {
+	server := newOllamaServer(t, "llama3.2:latest")
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := p.GenerateText(context.Background(), "Hi", "llama3.2:latest", map[string]any{"num_ctx": 4096})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TextContent() != "Hello from Ollama" {
+		t.Errorf("expected 'Hello from Ollama', got '%s'", result.TextContent())
+	}
+	if result.Usage().TotalTokens() != 9 {
+		t.Errorf("expected 9 total tokens, got %d", result.Usage().TotalTokens())
+	}
+}
+
+func TestOllamaProviderRejectsUnknownModel(t *testing.T) {
+	server := newOllamaServer(t, "llama3.2:latest")
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = p.GenerateText(context.Background(), "Hi", "mistral", map[string]any{})
+	if !errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("expected ErrModelNotFound when using an undiscovered model, got %v", err)
+	}
+}
+
+func TestOllamaProviderRejectsUnsupportedInput(t *testing.T) {
+	server := newOllamaServer(t, "llama3.2:latest")
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	message := types.NewUserMessageWithParts(types.NewTextPart("Describe this"), types.NewImagePart([]byte("img"), "image/png"))
+	_, err = p.GenerateChat(context.Background(), []types.Message{message}, "llama3.2:latest", nil)
+	var inputErr *types.UnsupportedInputError
+	if !errors.As(err, &inputErr) || inputErr.InputType != types.ContentPartImage {
+		t.Errorf("expected image input to be rejected for a text-only model, got %v", err)
+	}
+}
+
+func TestOllamaModelContextWindow(t *testing.T) {
+	server := newOllamaServer(t, "llama3.2:latest", "qwen2.5:7b", "mistral")
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, expected := range map[string]int{"llama3.2:latest": 8192, "qwen2.5:7b": 4096, "mistral": 0} {
+		model, err := p.GetModel(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if model.ContextWindow() != expected {
+			t.Errorf("expected %s to have a %d token window, got %d", name, expected, model.ContextWindow())
+		}
+	}
+
+	p, err = provider.NewOllamaChatProviderFromConfig(config.OllamaConfig{BaseURL: server.URL, ContextLength: 16384})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model, err := p.GetModel("qwen2.5:7b"); err != nil || model.ContextWindow() != 16384 {
+		t.Errorf("expected the configured server default without num_ctx, got %v, %v", model, err)
+	}
+}
+
+func TestOllamaModelSupportsVision(t *testing.T) {
+	server := newOllamaServer(t, "gemma3:4b", "qwen2.5:7b")
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, expected := range map[string]bool{"gemma3:4b": true, "qwen2.5:7b": false} {
+		model, err := p.GetModel(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if model.SupportsVision() != expected {
+			t.Errorf("expected vision support for %s to be %t", name, expected)
+		}
+	}
+}
+
+func TestOllamaProviderUnreachableServer(t *testing.T) {
+	server := newOllamaServer(t)
+	server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("expected the provider to be created without contacting the server, got %v", err)
+	}
+	if models := p.AvailableModels(); len(models) != 0 {
+		t.Errorf("expected no models, got %d", len(models))
+	}
+	_, err = p.GenerateText(context.Background(), "Hi", "llama3.2:latest", nil)
+	if err == nil || errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("expected a discovery error when the Ollama server is unreachable, got %v", err)
+	}
+}
+
+func TestOllamaProviderResolvesUntaggedNamesToLatest(t *testing.T) {
+	server := newOllamaServer(t, "llama3.2:latest", "qwen2.5:7b")
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model, err := p.GetModel("llama3.2")
+	if err != nil {
+		t.Fatalf("expected llama3.2 to resolve to llama3.2:latest, got %v", err)
+	}
+	if model.Name() != "llama3.2:latest" || model.ContextWindow() != 8192 {
+		t.Errorf("expected llama3.2:latest with an 8192 token window, got %s with %d", model.Name(), model.ContextWindow())
+	}
+	if _, err := p.GetModel("qwen2.5"); !errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("expected qwen2.5 without a latest tag to be unknown, got %v", err)
+	}
+	if len(p.AvailableRequestParameters("llama3.2")) == 0 {
+		t.Error("expected request parameters for the untagged name")
+	}
+
+	result, err := p.GenerateText(context.Background(), "Hi", "llama3.2", map[string]any{"num_ctx": 4096})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TextContent() != "Hello from Ollama" {
+		t.Errorf("expected 'Hello from Ollama', got '%s'", result.TextContent())
+	}
+}
+
+func TestOllamaProviderHonorsCallerContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.GenerateText(ctx, "Hi", "llama3.2", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected discovery to stop with the caller's context, got %v", err)
+	}
+	if _, err := p.ResolveModel(ctx, "llama3.2"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled discovery not to be cached as a server failure, got %v", err)
+	}
+}
+
+func TestOllamaProviderCachesDiscoveryFailure(t *testing.T) {
+	var tagRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tagRequests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"bad request"}`))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOllamaChatProvider(writeOllamaConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.AvailableModels()
+	p.AvailableRequestParameters("llama3.2")
+	_, err = p.GetModel("llama3.2")
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected the cached discovery error, got %v", err)
+	}
+	if tagRequests.Load() != 1 {
+		t.Errorf("expected one discovery request while the failure is cached, got %d", tagRequests.Load())
+	}
+}
+
+func writeOllamaConfig(t *testing.T, baseURL string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testConfig := fmt.Sprintf("ollama:\n  base_url: \"%s\"\n", baseURL)
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	return path
+}
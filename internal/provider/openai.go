@@ -1,70 +1,152 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"agentic-ai-framework/internal/config"
 	"agentic-ai-framework/internal/strategy"
+	"agentic-ai-framework/internal/tokenizer"
 	"agentic-ai-framework/internal/transport"
 	"agentic-ai-framework/internal/types"
 )
 
 type OpenAIModel struct {
-	name       string
-	parameters []string
+	name            string
+	parameters      []types.Parameter
+	vision          bool
+	contextWindow   int
+	maxOutputTokens int
 }
 
 func (m *OpenAIModel) Name() string {
 	return m.name
 }
 
-func (m *OpenAIModel) AvailableRequestParameters() []string {
+func (m *OpenAIModel) AvailableRequestParameters() []types.Parameter {
 	return m.parameters
 }
 
+func (m *OpenAIModel) SupportsVision() bool {
+	return m.vision
+}
+
+func (m *OpenAIModel) ContextWindow() int {
+	return m.contextWindow
+}
+
+func (m *OpenAIModel) MaxOutputTokens() int {
+	return m.maxOutputTokens
+}
+
 type OpenAIChatCompletionsProvider struct {
-	config            map[string]any
-	availableModels   []Model
-	modelParameters   map[string][]string
-	name              string
-	apiKey            string
-	baseURL           string
-	httpClient        *http.Client
+	config          map[string]any
+	availableModels []Model
+	modelParameters map[string][]types.Parameter
+	embeddingModels []Model
+	name            string
+	endpoints       *endpointPool
+	httpClient      *http.Client
+	retryPolicy     transport.RetryPolicy
+	rateLimiter     RateLimiter
 }
 
-func NewOpenAIChatCompletionsProvider(configFile string) *OpenAIChatCompletionsProvider {
-	cfg := config.LoadConfig(configFile)
+var openAIParameters = map[string]types.Parameter{
+	"temperature":           types.NewParameter("temperature", types.ParameterNumber).WithRange(0, 2).WithDefault(1.0),
+	"top_p":                 types.NewParameter("top_p", types.ParameterNumber).WithRange(0, 1).WithDefault(1.0),
+	"max_completion_tokens": types.NewParameter("max_completion_tokens", types.ParameterInteger).WithMinimum(1).WithAliases("max_tokens"),
+	"presence_penalty":      types.NewParameter("presence_penalty", types.ParameterNumber).WithRange(-2, 2).WithDefault(0.0),
+	"frequency_penalty":     types.NewParameter("frequency_penalty", types.ParameterNumber).WithRange(-2, 2).WithDefault(0.0),
+	"seed":                  types.NewParameter("seed", types.ParameterInteger),
+	"n":                     types.NewParameter("n", types.ParameterInteger).WithMinimum(1).WithDefault(1),
+	"stop":                  types.NewParameter("stop", types.ParameterStringList),
+	"reasoning_effort":      types.NewParameter("reasoning_effort", types.ParameterString).WithEnum("minimal", "low", "medium", "high").WithDefault("medium"),
+	"response_format":       types.NewParameter("response_format", types.ParameterAny),
+	"dimensions":            types.NewParameter("dimensions", types.ParameterInteger).WithMinimum(1),
+	"encoding_format":       types.NewParameter("encoding_format", types.ParameterString).WithEnum("float", "base64").WithDefault("float"),
+}
+
+var openAIDefaultModels = []config.ModelConfig{
+	{Name: "gpt-4.1", Parameters: []string{"temperature", "top_p", "max_completion_tokens", "n", "response_format"}, Vision: true, ContextWindow: 1047576, MaxOutputTokens: 32768},
+	{Name: "gpt-5", Parameters: []string{"max_completion_tokens", "reasoning_effort", "response_format"}, Vision: true, ContextWindow: 400000, MaxOutputTokens: 128000},
+}
+
+var openAIDefaultEmbeddingModels = []config.ModelConfig{
+	{Name: "text-embedding-3-small", Parameters: []string{"dimensions", "encoding_format"}, ContextWindow: 8191},
+	{Name: "text-embedding-3-large", Parameters: []string{"dimensions", "encoding_format"}, ContextWindow: 8191},
+	{Name: "text-embedding-ada-002", Parameters: []string{"encoding_format"}, ContextWindow: 8191},
+}
+
+func NewOpenAIChatCompletionsProvider(configFile string) (*OpenAIChatCompletionsProvider, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenAIChatCompletionsProviderFromConfig(cfg.OpenAI)
+}
 
-	if cfg.OpenAI.APIKey == "" {
-		panic("openai.api_key is required in config file")
+func NewOpenAIChatCompletionsProviderFromConfig(cfg config.OpenAIConfig) (*OpenAIChatCompletionsProvider, error) {
+	endpoints, err := newEndpointPool(cfg, "https://api.openai.com/v1")
+	if err != nil {
+		return nil, err
 	}
 
-	baseURL := cfg.OpenAI.BaseURL
-	if baseURL == "" {
-		baseURL = "https://api.openai.com/v1"
+	models := cfg.Models
+	if len(models) == 0 {
+		models = openAIDefaultModels
 	}
 
+	first := endpoints.endpoints[0]
 	provider := &OpenAIChatCompletionsProvider{
-		name:   "OpenAI Chat Completions",
-		apiKey: cfg.OpenAI.APIKey,
-		baseURL: baseURL,
-		httpClient: transport.NewClient(transport.DefaultTimeout),
-		availableModels: []Model{
-			&OpenAIModel{name: "gpt-4.1", parameters: []string{"temperature", "top_p"}},
-			&OpenAIModel{name: "gpt-5", parameters: []string{}},
-		},
-		modelParameters: map[string][]string{
-			"gpt-4.1": {"temperature", "top_p"},
-			"gpt-5":   {},
-		},
+		name:            "OpenAI Chat Completions",
+		endpoints:       endpoints,
+		httpClient:      transport.NewContextClient(),
+		retryPolicy:     retryPolicy(cfg.Retry),
+		availableModels: make([]Model, len(models)),
+		modelParameters: make(map[string][]types.Parameter, len(models)),
 		config: map[string]any{
-			"api_key":  cfg.OpenAI.APIKey,
-			"base_url": baseURL,
+			"api_key":  first.apiKey,
+			"base_url": first.baseURL,
 		},
 	}
+	for i, model := range models {
+		parameters := openAIModelParameters(model.Parameters)
+		provider.availableModels[i] = newOpenAIModel(model, parameters)
+		provider.modelParameters[model.Name] = parameters
+	}
+
+	embeddingModels := cfg.EmbeddingModels
+	if len(embeddingModels) == 0 {
+		embeddingModels = openAIDefaultEmbeddingModels
+	}
+	for _, model := range embeddingModels {
+		provider.embeddingModels = append(provider.embeddingModels, newOpenAIModel(model, openAIModelParameters(model.Parameters)))
+	}
+
+	return provider, nil
+}
+
+func newOpenAIModel(model config.ModelConfig, parameters []types.Parameter) *OpenAIModel {
+	return &OpenAIModel{
+		name:            model.Name,
+		parameters:      parameters,
+		vision:          model.Vision,
+		contextWindow:   model.ContextWindow,
+		maxOutputTokens: model.MaxOutputTokens,
+	}
+}
 
-	return provider
+func openAIModelParameters(names []string) []types.Parameter {
+	parameters := make([]types.Parameter, len(names))
+	for i, name := range names {
+		parameter, known := openAIParameters[name]
+		if !known {
+			parameter = types.NewParameter(name, types.ParameterAny)
+		}
+		parameters[i] = parameter
+	}
+	return parameters
 }
 
 func (p *OpenAIChatCompletionsProvider) Name() string {
@@ -75,11 +157,11 @@ func (p *OpenAIChatCompletionsProvider) AvailableModels() []Model {
 	return p.availableModels
 }
 
-func (p *OpenAIChatCompletionsProvider) AvailableRequestParameters(modelName string) []string {
+func (p *OpenAIChatCompletionsProvider) AvailableRequestParameters(modelName string) []types.Parameter {
 	if params, exists := p.modelParameters[modelName]; exists {
 		return params
 	}
-	return []string{}
+	return []types.Parameter{}
 }
 
 func (p *OpenAIChatCompletionsProvider) GetModel(modelName string) (Model, error) {
@@ -88,44 +170,286 @@ func (p *OpenAIChatCompletionsProvider) GetModel(modelName string) (Model, error
 			return model, nil
 		}
 	}
-	return nil, fmt.Errorf("model %s not found in provider %s", modelName, p.Name())
+	return nil, &types.ModelNotFoundError{Model: modelName, Provider: p.Name()}
 }
 
 func (p *OpenAIChatCompletionsProvider) Config() map[string]any {
 	return p.config
 }
 
-func (p *OpenAIChatCompletionsProvider) GenerateText(prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
-	if err := ValidateModel(p.availableModels, modelName, p.Name()); err != nil {
-		panic(err.Error())
+func (p *OpenAIChatCompletionsProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
+func (p *OpenAIChatCompletionsProvider) SetRateLimiter(limiter RateLimiter) {
+	p.rateLimiter = limiter
+}
+
+func (p *OpenAIChatCompletionsProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *OpenAIChatCompletionsProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateWithTools(ctx, messages, nil, modelName, requestParameters)
+}
+
+func (p *OpenAIChatCompletionsProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	chatRequest, err := p.buildChatRequest(messages, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	chatRequest.Tools = chatTools(tools)
+	requestBody := strategy.BuildChatCompletionsRequestBody(chatRequest)
+
+	var result types.GenerateTextResult
+	e, err := p.dispatch(ctx, "/chat/completions", func(config strategy.ChatCompletionsConfig) error {
+		done, err := p.reserve(ctx, config.APIKey, modelName, messages, requestParameters)
+		if err != nil {
+			return err
+		}
+		response, statusCode, err := strategy.ExecuteChatCompletionsRequest(ctx, config, requestBody)
+		if err == nil {
+			result, err = strategy.ParseChatCompletionsResponse(response, statusCode)
+		}
+		done(result, err)
+		return err
+	})
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	p.endpoints.release(e, nil)
+	return result, nil
+}
+
+func (p *OpenAIChatCompletionsProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	messages := []types.Message{types.NewUserMessage(prompt)}
+	chatRequest, err := p.buildChatRequest(messages, modelName, requestParameters)
+	if err != nil {
+		return nil, err
+	}
+	chatRequest.Stream = true
+	requestBody := strategy.BuildChatCompletionsRequestBody(chatRequest)
+
+	var chunks <-chan types.StreamChunk
+	var done func(types.GenerateTextResult, error)
+	e, err := p.dispatch(ctx, "/chat/completions", func(config strategy.ChatCompletionsConfig) error {
+		var err error
+		done, err = p.reserve(ctx, config.APIKey, modelName, messages, requestParameters)
+		if err != nil {
+			return err
+		}
+		chunks, err = strategy.ExecuteChatCompletionsStreamRequest(ctx, config, requestBody)
+		if err != nil {
+			done(types.GenerateTextResult{}, err)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return forwardStream(ctx, chunks, func(result types.GenerateTextResult, err error) {
+		p.endpoints.release(e, err)
+		done(result, err)
+	}), nil
+}
+
+func forwardStream(ctx context.Context, chunks <-chan types.StreamChunk, settle func(types.GenerateTextResult, error)) <-chan types.StreamChunk {
+	forwarded := make(chan types.StreamChunk)
+	go func() {
+		defer close(forwarded)
+		var result types.GenerateTextResult
+		var streamErr error
+		defer func() {
+			settle(result, streamErr)
+		}()
+		for chunk := range chunks {
+			if chunk.Err() != nil {
+				streamErr = chunk.Err()
+			}
+			if chunk.Done() {
+				result = chunk.Result()
+			}
+			select {
+			case forwarded <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return forwarded
+}
+
+func (p *OpenAIChatCompletionsProvider) buildChatRequest(messages []types.Message, modelName string, requestParameters map[string]any) (strategy.ChatCompletionsRequest, error) {
+	model, err := p.GetModel(modelName)
+	if err != nil {
+		return strategy.ChatCompletionsRequest{}, err
+	}
+	if err := ValidateMessageInput(model, messages); err != nil {
+		return strategy.ChatCompletionsRequest{}, err
 	}
 
 	availableParams := p.AvailableRequestParameters(modelName)
 	if err := ValidateRequestParameters(availableParams, requestParameters, modelName); err != nil {
-		panic(err.Error())
+		return strategy.ChatCompletionsRequest{}, err
 	}
 
-	chatRequest := strategy.ChatCompletionsRequest{
-		Model: modelName,
-		Messages: []strategy.ChatMessage{
-			{Role: "user", Content: prompt},
-		},
+	return strategy.ChatCompletionsRequest{
+		Model:         modelName,
+		Messages:      chatMessages(messages),
+		RequestParams: NormalizeRequestParameters(availableParams, requestParameters),
+	}, nil
+}
+
+func NewChatCompletionsRequest(messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) strategy.ChatCompletionsRequest {
+	return strategy.ChatCompletionsRequest{
+		Model:         modelName,
+		Messages:      chatMessages(messages),
+		Tools:         chatTools(tools),
 		RequestParams: requestParameters,
 	}
+}
 
-	requestBody := strategy.BuildChatCompletionsRequestBody(chatRequest)
+func chatMessages(messages []types.Message) []strategy.ChatMessage {
+	chatMessages := make([]strategy.ChatMessage, len(messages))
+	for i, msg := range messages {
+		toolCalls := make([]strategy.ChatToolCall, len(msg.ToolCalls()))
+		for j, call := range msg.ToolCalls() {
+			toolCalls[j] = strategy.ChatToolCall{ID: call.ID(), Name: call.Name(), Arguments: call.Arguments()}
+		}
+		chatMessages[i] = strategy.ChatMessage{
+			Role:       msg.Role(),
+			Content:    msg.Content(),
+			Parts:      msg.Parts(),
+			ToolCalls:  toolCalls,
+			ToolCallID: msg.ToolCallID(),
+		}
+	}
+	return chatMessages
+}
 
-	cfg := strategy.ChatCompletionsConfig{
-		BaseURL:    p.baseURL,
-		Endpoint:   "/chat/completions",
-		APIKey:     p.apiKey,
-		HTTPClient: p.httpClient,
+func chatTools(tools []types.ToolDefinition) []strategy.ChatTool {
+	chatTools := make([]strategy.ChatTool, len(tools))
+	for i, tool := range tools {
+		chatTools[i] = strategy.ChatTool{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			Parameters:  tool.Parameters(),
+		}
 	}
+	return chatTools
+}
 
-	response, statusCode, err := strategy.ExecuteChatCompletionsRequest(cfg, requestBody)
-	if err != nil {
-		return types.GenerateTextResult{}, err
+func (p *OpenAIChatCompletionsProvider) AvailableEmbeddingModels() []Model {
+	return p.embeddingModels
+}
+
+func (p *OpenAIChatCompletionsProvider) Embed(ctx context.Context, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	var model Model
+	for _, m := range p.embeddingModels {
+		if m.Name() == modelName {
+			model = m
+		}
+	}
+	if model == nil {
+		return types.EmbeddingResult{}, &types.ModelNotFoundError{Model: modelName, Provider: p.Name()}
 	}
 
-	return strategy.ParseChatCompletionsResponse(response, statusCode)
+	availableParams := model.AvailableRequestParameters()
+	if err := ValidateRequestParameters(availableParams, requestParameters, modelName); err != nil {
+		return types.EmbeddingResult{}, err
+	}
+	requestParams := NormalizeRequestParameters(availableParams, requestParameters)
+
+	counter := tokenizer.ForModel(modelName)
+	tokens := make([]int, len(inputs))
+	for i, input := range inputs {
+		tokens[i] = counter.Count(input)
+		if model.ContextWindow() > 0 && tokens[i] > model.ContextWindow() {
+			return types.EmbeddingResult{}, fmt.Errorf("embedding input %d is too long: %w", i, &types.ContextOverflowError{Model: modelName, PromptTokens: tokens[i], ContextWindow: model.ContextWindow()})
+		}
+	}
+
+	var embeddings []types.Embedding
+	var usage types.TokenUsage
+	var responseModel string
+	attempts := 0
+	for _, batch := range strategy.BatchEmbeddingInputs(inputs, tokens, strategy.EmbeddingsMaxBatchInputs, strategy.EmbeddingsMaxBatchTokens) {
+		requestBody := strategy.BuildEmbeddingsRequestBody(strategy.EmbeddingsRequest{
+			Model:         modelName,
+			Input:         batch,
+			RequestParams: requestParams,
+		})
+		messages := make([]types.Message, len(batch))
+		for i, input := range batch {
+			messages[i] = types.NewUserMessage(input)
+		}
+		var result types.EmbeddingResult
+		e, err := p.dispatch(ctx, "/embeddings", func(config strategy.ChatCompletionsConfig) error {
+			done, err := p.reserve(ctx, config.APIKey, modelName, messages, nil)
+			if err != nil {
+				return err
+			}
+			response, statusCode, err := strategy.ExecuteEmbeddingsRequest(ctx, config, requestBody)
+			if err == nil {
+				result, err = strategy.ParseEmbeddingsResponse(response, statusCode)
+			}
+			done(types.NewGenerateTextResult("", result.Usage()), err)
+			return err
+		})
+		if err != nil {
+			return types.EmbeddingResult{}, err
+		}
+		p.endpoints.release(e, nil)
+		offset := len(embeddings)
+		for _, embedding := range result.Embeddings() {
+			embeddings = append(embeddings, types.NewEmbedding(offset+embedding.Index(), embedding.Vector()))
+		}
+		usage = usage.Add(result.Usage())
+		responseModel = result.Model()
+		attempts += result.Attempts()
+	}
+
+	return types.NewEmbeddingResult(embeddings, usage).
+		WithModel(responseModel).
+		WithAttempts(attempts), nil
+}
+
+func (p *OpenAIChatCompletionsProvider) Endpoints() []EndpointStatus {
+	return p.endpoints.status()
+}
+
+func (p *OpenAIChatCompletionsProvider) reserve(ctx context.Context, apiKey string, modelName string, messages []types.Message, requestParameters map[string]any) (func(types.GenerateTextResult, error), error) {
+	if p.rateLimiter == nil {
+		return func(types.GenerateTextResult, error) {}, nil
+	}
+	return p.rateLimiter.Reserve(ctx, apiKey, modelName, messages, requestParameters)
+}
+
+func (p *OpenAIChatCompletionsProvider) dispatch(ctx context.Context, path string, call func(config strategy.ChatCompletionsConfig) error) (*endpoint, error) {
+	tried := make(map[*endpoint]bool)
+	var lastErr error
+	for {
+		e := p.endpoints.acquire(tried)
+		if e == nil {
+			return nil, lastErr
+		}
+		err := call(strategy.ChatCompletionsConfig{
+			BaseURL:      e.baseURL,
+			Endpoint:     path,
+			APIKey:       e.apiKey,
+			Organization: e.organization,
+			Project:      e.project,
+			HTTPClient:   p.httpClient,
+			RetryPolicy:  p.retryPolicy,
+		})
+		if err == nil {
+			return e, nil
+		}
+		p.endpoints.release(e, err)
+		if !shouldEject(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		tried[e] = true
+		lastErr = err
+	}
 }
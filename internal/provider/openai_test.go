@@ -1,13 +1,22 @@
 package provider_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"agentic-ai-framework/internal/config"
 	"agentic-ai-framework/internal/provider"
 	"agentic-ai-framework/internal/runtime"
+	"agentic-ai-framework/internal/strategy"
+	"agentic-ai-framework/internal/transport"
 	"agentic-ai-framework/internal/types"
 )
 
@@ -22,7 +31,10 @@ func TestNewOpenAIChatCompletionsProvider(t *testing.T) {
 	}
 	defer os.Remove("test_config.yaml")
 
-	p := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	p, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if p.Name() != "OpenAI Chat Completions" {
 		t.Errorf("expected provider name 'OpenAI Chat Completions', got '%s'", p.Name())
 	}
@@ -37,7 +49,10 @@ func TestNewOpenAIChatCompletionsProvider(t *testing.T) {
 	if providerConfig["base_url"] == "" {
 		t.Error("expected config to contain base_url")
 	}
-	testYamlConfig := config.LoadConfig("test_config.yaml")
+	testYamlConfig, err := config.LoadConfig("test_config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	if providerConfig["api_key"] != testYamlConfig.OpenAI.APIKey {
 		t.Error("provider api_key should match test config api_key")
 	}
@@ -46,6 +61,27 @@ func TestNewOpenAIChatCompletionsProvider(t *testing.T) {
 	}
 }
 
+func TestNewOpenAIChatCompletionsProviderErrors(t *testing.T) {
+	t.Run("missing config file", func(t *testing.T) {
+		_, err := provider.NewOpenAIChatCompletionsProvider("does_not_exist.yaml")
+		if err == nil {
+			t.Fatal("expected error for missing config file")
+		}
+	})
+
+	t.Run("missing api key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte("openai:\n  base_url: \"https://api.openai.com/v1\"\n"), 0644); err != nil {
+			t.Fatalf("failed to create test config: %v", err)
+		}
+
+		_, err := provider.NewOpenAIChatCompletionsProvider(path)
+		if !errors.Is(err, types.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+}
+
 func TestProviderAvailableModels(t *testing.T) {
 	testConfig := `openai:
   api_key: "test-key"
@@ -57,12 +93,10 @@ func TestProviderAvailableModels(t *testing.T) {
 	}
 	defer os.Remove("test_config.yaml")
 
-	defer func() {
-		if r := recover(); r != nil {
-			t.Fatalf("unexpected panic: %v", r)
-		}
-	}()
-	p := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	p, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	models := p.AvailableModels()
 	expectedModels := []string{"gpt-4.1", "gpt-5"}
 	if len(models) != len(expectedModels) {
@@ -75,6 +109,29 @@ func TestProviderAvailableModels(t *testing.T) {
 	}
 }
 
+func TestProviderModelLimits(t *testing.T) {
+	p, err := provider.NewOpenAIChatCompletionsProviderFromConfig(config.OpenAIConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model, _ := p.GetModel("gpt-4.1")
+	if model.ContextWindow() != 1047576 || model.MaxOutputTokens() != 32768 {
+		t.Errorf("unexpected gpt-4.1 limits: %d/%d", model.ContextWindow(), model.MaxOutputTokens())
+	}
+
+	p, err = provider.NewOpenAIChatCompletionsProviderFromConfig(config.OpenAIConfig{
+		APIKey: "test-key",
+		Models: []config.ModelConfig{{Name: "local-model", ContextWindow: 8192, MaxOutputTokens: 2048}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model, _ = p.GetModel("local-model")
+	if model.ContextWindow() != 8192 || model.MaxOutputTokens() != 2048 {
+		t.Errorf("expected limits from config, got %d/%d", model.ContextWindow(), model.MaxOutputTokens())
+	}
+}
+
 func TestProviderImplementsInterface(t *testing.T) {
 	testConfig := `openai:
   api_key: "test-key"
@@ -86,12 +143,10 @@ func TestProviderImplementsInterface(t *testing.T) {
 	}
 	defer os.Remove("test_config.yaml")
 
-	defer func() {
-		if r := recover(); r != nil {
-			t.Fatalf("unexpected panic: %v", r)
-		}
-	}()
-	p := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	p, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	var _ provider.Provider = p
 }
 
@@ -99,28 +154,16 @@ func TestGenerateTextIntegration(t *testing.T) {
 	if _, err := os.Stat("../../config.yaml"); os.IsNotExist(err) {
 		t.Skip("Skipping integration test: config.yaml not found")
 	}
-	p := provider.NewOpenAIChatCompletionsProvider("../../config.yaml")
-	var result types.GenerateTextResult
-	var panicked bool
-	var panicErr error
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				panicked = true
-				if err, ok := r.(error); ok {
-					panicErr = err
-				} else {
-					panicErr = fmt.Errorf("%v", r)
-				}
-			}
-		}()
-		result = runtime.GenerateText(p, "Say 'test' and nothing else", "gpt-4.1", map[string]any{
-			"temperature": 0.0,
-			"top_p":       0.9,
-		})
-	}()
-	if panicked {
-		t.Fatalf("GenerateText panicked with error: %v", panicErr)
+	p, err := provider.NewOpenAIChatCompletionsProvider("../../config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := runtime.GenerateText(context.Background(), p, "Say 'test' and nothing else", "gpt-4.1", map[string]any{
+		"temperature": 0.0,
+		"top_p":       0.9,
+	})
+	if err != nil {
+		t.Fatalf("GenerateText returned error: %v", err)
 	}
 	if result.TextContent() == "" {
 		t.Error("expected non-empty text content")
@@ -138,17 +181,18 @@ func TestProviderValidatesModelName(t *testing.T) {
 	}
 	defer os.Remove("test_config.yaml")
 
-	defer func() {
-		r := recover()
-		if r == nil {
-			t.Fatal("expected panic when using invalid model name")
-		}
-		if _, ok := r.(string); !ok {
-			t.Errorf("expected panic message to be a string, got %T", r)
-		}
-	}()
-	prv := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
-	prv.GenerateText("test", "invalid-model-name", map[string]any{})
+	prv, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = prv.GenerateText(context.Background(), "test", "invalid-model-name", map[string]any{})
+	if !errors.Is(err, types.ErrModelNotFound) {
+		t.Fatalf("expected ErrModelNotFound when using invalid model name, got %v", err)
+	}
+	_, err = prv.GetModel("invalid-model-name")
+	if !errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("expected ErrModelNotFound from GetModel, got %v", err)
+	}
 }
 
 func TestProviderAvailableRequestParameters(t *testing.T) {
@@ -162,19 +206,25 @@ func TestProviderAvailableRequestParameters(t *testing.T) {
 	}
 	defer os.Remove("test_config.yaml")
 
-	p := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	p, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	gpt41Params := p.AvailableRequestParameters("gpt-4.1")
-	expectedGPT41Params := []string{"temperature", "top_p"}
+	expectedGPT41Params := []string{"temperature", "top_p", "max_completion_tokens", "n", "response_format"}
 	if len(gpt41Params) != len(expectedGPT41Params) {
 		t.Errorf("expected gpt-4.1 to have %d parameters, got %d", len(expectedGPT41Params), len(gpt41Params))
 	}
 	hasTemperature := false
 	hasTopP := false
 	for _, param := range gpt41Params {
-		if param == "temperature" {
+		if param.Name() == "temperature" {
 			hasTemperature = true
+			if minimum, maximum := rangeOf(param); minimum != 0 || maximum != 2 {
+				t.Errorf("expected temperature range [0, 2], got [%v, %v]", minimum, maximum)
+			}
 		}
-		if param == "top_p" {
+		if param.Name() == "top_p" {
 			hasTopP = true
 		}
 	}
@@ -185,11 +235,18 @@ func TestProviderAvailableRequestParameters(t *testing.T) {
 		t.Error("expected gpt-4.1 to support top_p parameter")
 	}
 	gpt5Params := p.AvailableRequestParameters("gpt-5")
-	if len(gpt5Params) != 0 {
-		t.Errorf("expected gpt-5 to have no parameters, got %v", gpt5Params)
+	gpt5Names := types.ParameterNames(gpt5Params)
+	if len(gpt5Names) != 3 || gpt5Names[0] != "max_completion_tokens" || gpt5Names[1] != "reasoning_effort" || gpt5Names[2] != "response_format" {
+		t.Errorf("expected gpt-5 to support max_completion_tokens, reasoning_effort and response_format, got %v", gpt5Names)
 	}
 }
 
+func rangeOf(param types.Parameter) (float64, float64) {
+	minimum, _ := param.Minimum()
+	maximum, _ := param.Maximum()
+	return minimum, maximum
+}
+
 func TestProviderValidatesRequestParameters(t *testing.T) {
 	testConfig := `openai:
   api_key: "test-key"
@@ -200,62 +257,102 @@ func TestProviderValidatesRequestParameters(t *testing.T) {
 		t.Fatalf("failed to create test config: %v", err)
 	}
 	defer os.Remove("test_config.yaml")
-	t.Run("gpt-5 rejects any parameter", func(t *testing.T) {
-		defer func() {
-			r := recover()
-			if r == nil {
-				t.Fatal("expected panic when using any parameter for gpt-5")
-			}
-			errMsg, ok := r.(string)
-			if !ok {
-				t.Errorf("expected panic message to be a string, got %T", r)
-			}
-			if errMsg == "" {
-				t.Error("expected non-empty panic message")
-			}
-		}()
-		prv := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
-		prv.GenerateText("test", "gpt-5", map[string]any{
+	t.Run("gpt-5 rejects sampling parameters", func(t *testing.T) {
+		prv, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = prv.GenerateText(context.Background(), "test", "gpt-5", map[string]any{
 			"temperature": 0.7,
 		})
+		if !errors.Is(err, types.ErrInvalidParameter) {
+			t.Fatalf("expected ErrInvalidParameter when using temperature for gpt-5, got %v", err)
+		}
+		var paramErr *types.ParameterError
+		if !errors.As(err, &paramErr) || paramErr.Parameter != "temperature" {
+			t.Errorf("expected ParameterError for temperature, got %v", err)
+		}
 	})
 	t.Run("gpt-4.1 rejects invalid parameters", func(t *testing.T) {
-		defer func() {
-			r := recover()
-			if r == nil {
-				t.Fatal("expected panic when using invalid parameter for gpt-4.1")
-			}
-			errMsg, ok := r.(string)
-			if !ok {
-				t.Errorf("expected panic message to be a string, got %T", r)
-			}
-			if errMsg == "" {
-				t.Error("expected non-empty panic message")
-			}
-		}()
-		prv := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
-		prv.GenerateText("test", "gpt-4.1", map[string]any{
-			"max_tokens": 100,
+		prv, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = prv.GenerateText(context.Background(), "test", "gpt-4.1", map[string]any{
+			"top_k": 100,
+		})
+		if !errors.Is(err, types.ErrInvalidParameter) {
+			t.Fatalf("expected ErrInvalidParameter when using invalid parameter for gpt-4.1, got %v", err)
+		}
+	})
+	t.Run("gpt-4.1 rejects invalid values", func(t *testing.T) {
+		prv, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = prv.GenerateText(context.Background(), "test", "gpt-4.1", map[string]any{
+			"temperature": "hot",
+			"top_p":       7,
 		})
+		if !errors.Is(err, types.ErrInvalidParameter) {
+			t.Fatalf("expected ErrInvalidParameter for invalid values, got %v", err)
+		}
+		expected := "request parameter 'temperature' is invalid for model gpt-4.1: expected number, got string\n" +
+			"request parameter 'top_p' is invalid for model gpt-4.1: must be between 0 and 1, got 7"
+		if err.Error() != expected {
+			t.Errorf("expected '%s', got '%s'", expected, err.Error())
+		}
 	})
 	t.Run("gpt-4.1 accepts valid parameters", func(t *testing.T) {
-		prv := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
-		_, err := prv.GenerateText("test", "gpt-4.1", map[string]any{
+		prv, err := provider.NewOpenAIChatCompletionsProvider("test_config.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = prv.GenerateText(context.Background(), "test", "gpt-4.1", map[string]any{
 			"temperature": 0.7,
 			"top_p":       0.9,
 		})
 		if err == nil {
 			t.Error("expected error due to invalid API key in test config")
 		}
+		if errors.Is(err, types.ErrInvalidParameter) {
+			t.Errorf("expected valid parameters to pass validation, got %v", err)
+		}
 	})
 }
 
+func TestProviderRenamesDeprecatedParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["max_completion_tokens"] != float64(64) {
+			t.Errorf("expected max_completion_tokens 64, got %v", body["max_completion_tokens"])
+		}
+		if _, exists := body["max_tokens"]; exists {
+			t.Error("expected deprecated max_tokens not to be sent")
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(writeServerConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := p.GenerateText(context.Background(), "Hello", "gpt-4.1", map[string]any{"max_tokens": 64}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestProviderGenerateTextDirectly(t *testing.T) {
 	if _, err := os.Stat("../../config.yaml"); os.IsNotExist(err) {
 		t.Skip("Skipping integration test: config.yaml not found")
 	}
-	p := provider.NewOpenAIChatCompletionsProvider("../../config.yaml")
-	result, err := p.GenerateText("Hello", "gpt-4.1", map[string]any{
+	p, err := provider.NewOpenAIChatCompletionsProvider("../../config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := p.GenerateText(context.Background(), "Hello", "gpt-4.1", map[string]any{
 		"temperature": 0.7,
 		"top_p":       0.9,
 	})
@@ -280,8 +377,11 @@ func TestProviderGenerateTextWithInvalidAPIKey(t *testing.T) {
 		t.Fatalf("failed to create test config: %v", err)
 	}
 	defer os.Remove("test_config_invalid.yaml")
-	p := provider.NewOpenAIChatCompletionsProvider("test_config_invalid.yaml")
-	_, err = p.GenerateText("Hello", "gpt-4.1", map[string]any{})
+	p, err := provider.NewOpenAIChatCompletionsProvider("test_config_invalid.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = p.GenerateText(context.Background(), "Hello", "gpt-4.1", map[string]any{})
 	if err == nil {
 		t.Error("expected error with invalid API key")
 	}
@@ -297,8 +397,11 @@ func TestProviderGenerateTextHandlesEmptyChoices(t *testing.T) {
 		t.Fatalf("failed to create test config: %v", err)
 	}
 	defer os.Remove("test_config_empty_choices.yaml")
-	p := provider.NewOpenAIChatCompletionsProvider("test_config_empty_choices.yaml")
-	_, err = p.GenerateText("test", "gpt-4.1", map[string]any{})
+	p, err := provider.NewOpenAIChatCompletionsProvider("test_config_empty_choices.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = p.GenerateText(context.Background(), "test", "gpt-4.1", map[string]any{})
 	if err == nil {
 		t.Error("expected error when API call fails or returns empty response")
 	}
@@ -308,19 +411,6 @@ func TestProviderGenerateTextHandlesEmptyChoices(t *testing.T) {
 }
 
 func TestGenerateTextHelperPropagatesErrors(t *testing.T) {
-	defer func() {
-		r := recover()
-		if r == nil {
-			t.Fatal("expected GenerateText to panic when provider returns error")
-		}
-		errMsg, ok := r.(error)
-		if !ok {
-			t.Fatalf("expected panic to be error, got %T", r)
-		}
-		if errMsg.Error() == "" {
-			t.Error("expected non-empty error message")
-		}
-	}()
 	invalidConfig := `openai:
   api_key: "invalid-key"
   base_url: "https://api.openai.com/v1"
@@ -330,6 +420,375 @@ func TestGenerateTextHelperPropagatesErrors(t *testing.T) {
 		t.Fatalf("failed to create test config: %v", err)
 	}
 	defer os.Remove("test_config_error.yaml")
-	p := provider.NewOpenAIChatCompletionsProvider("test_config_error.yaml")
-	runtime.GenerateText(p, "test", "gpt-4.1", map[string]any{})
+	p, err := provider.NewOpenAIChatCompletionsProvider("test_config_error.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = runtime.GenerateText(context.Background(), p, "test", "gpt-4.1", map[string]any{})
+	if err == nil {
+		t.Fatal("expected GenerateText to return an error when provider returns error")
+	}
+	if err.Error() == "" {
+		t.Error("expected non-empty error message")
+	}
+}
+
+func TestProviderReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", "req_abc123")
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"requests","code":"rate_limit_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(writeServerConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = p.GenerateText(context.Background(), "Hello", "gpt-4.1", map[string]any{})
+
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Code != "rate_limit_exceeded" || apiErr.RequestID != "req_abc123" {
+		t.Errorf("unexpected APIError: %+v", apiErr)
+	}
+	if !apiErr.Retryable {
+		t.Error("expected 429 to be retryable")
+	}
+	if apiErr.Attempts != 3 {
+		t.Errorf("expected 3 attempts before giving up, got %d", apiErr.Attempts)
+	}
+}
+
+func TestProviderReplaysCassette(t *testing.T) {
+	cassette, err := transport.NewCassette("testdata/openai_chat_completion.json", transport.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p, err := provider.NewOpenAIChatCompletionsProviderFromConfig(config.OpenAIConfig{APIKey: "unused"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.SetHTTPClient(cassette.Client())
+
+	result, err := p.GenerateText(context.Background(), "What is the capital of France?", "gpt-4.1", map[string]any{"temperature": 0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TextContent() != "Paris is the capital of France." || result.Usage().TotalTokens() != 21 || result.ResponseID() != "chatcmpl-cassette" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(cassette.Unplayed()) != 0 {
+		t.Error("expected every recorded interaction to be played")
+	}
+
+	_, err = p.GenerateText(context.Background(), "What is the capital of Spain?", "gpt-4.1", map[string]any{"temperature": 0})
+	if !errors.Is(err, transport.ErrUnmatchedRequest) {
+		t.Errorf("expected ErrUnmatchedRequest, got %v", err)
+	}
+}
+
+func TestProviderRetriesTransientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["model"] != "gpt-4.1" {
+			t.Errorf("expected request body to be replayed on retry, got %v (%v)", body, err)
+		}
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"message":"overloaded"}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"Recovered"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testConfig := fmt.Sprintf("openai:\n  api_key: \"test-key\"\n  base_url: \"%s\"\n  retry:\n    max_attempts: 4\n    initial_backoff: 1ms\n", server.URL)
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := p.GenerateText(context.Background(), "Hello", "gpt-4.1", map[string]any{})
+	if err != nil {
+		t.Fatalf("expected retries to recover from 503, got %v", err)
+	}
+	if result.TextContent() != "Recovered" {
+		t.Errorf("expected 'Recovered', got '%s'", result.TextContent())
+	}
+	if result.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", result.Attempts())
+	}
+}
+
+func TestProviderStreamText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			t.Errorf("expected stream true in request body, got %v", body["stream"])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n"))
+		w.Write([]byte("data: {\"choices\":[],\"usage\":{\"prompt_tokens\":1,\"completion_tokens\":1,\"total_tokens\":2}}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(writeServerConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, err := p.StreamText(context.Background(), "Hi", "gpt-4.1", map[string]any{"temperature": 0.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var deltas []string
+	var result types.GenerateTextResult
+	for chunk := range chunks {
+		if chunk.Err() != nil {
+			t.Fatalf("unexpected stream error: %v", chunk.Err())
+		}
+		if chunk.Done() {
+			result = chunk.Result()
+			continue
+		}
+		deltas = append(deltas, chunk.TextDelta())
+	}
+
+	if len(deltas) != 1 || deltas[0] != "Hello" {
+		t.Errorf("expected a single 'Hello' delta, got %v", deltas)
+	}
+	if result.TextContent() != "Hello" {
+		t.Errorf("expected 'Hello', got '%s'", result.TextContent())
+	}
+	if result.Usage().TotalTokens() != 2 {
+		t.Errorf("expected 2 total tokens, got %d", result.Usage().TotalTokens())
+	}
+}
+
+func TestProviderGenerateWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		tools, ok := body["tools"].([]any)
+		if !ok || len(tools) != 1 {
+			t.Errorf("expected one tool in request body, got %v", body["tools"])
+		}
+		messages := body["messages"].([]any)
+		if len(messages) != 3 {
+			t.Errorf("expected 3 messages in request body, got %d", len(messages))
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":null,"tool_calls":[{"id":"call_2","type":"function","function":{"name":"get_weather","arguments":"{}"}}]}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(writeServerConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages := []types.Message{
+		types.NewUserMessage("Weather?"),
+		types.NewAssistantMessage("", types.NewToolCall("call_1", "get_weather", "{}")),
+		types.NewToolMessage("call_1", "sunny"),
+	}
+	tools := []types.ToolDefinition{
+		types.NewToolDefinition("get_weather", "Get the weather", map[string]any{"type": "object"}),
+	}
+
+	result, err := p.GenerateWithTools(context.Background(), messages, tools, "gpt-4.1", map[string]any{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.ToolCalls()) != 1 || result.ToolCalls()[0].ID() != "call_2" {
+		t.Errorf("expected tool call call_2, got %v", result.ToolCalls())
+	}
+}
+
+func TestProviderGenerateChat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []map[string]any `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		expectedRoles := []string{"system", "user", "assistant", "user"}
+		if len(body.Messages) != len(expectedRoles) {
+			t.Fatalf("expected %d messages, got %d", len(expectedRoles), len(body.Messages))
+		}
+		for i, role := range expectedRoles {
+			if body.Messages[i]["role"] != role {
+				t.Errorf("expected message %d role '%s', got %v", i, role, body.Messages[i]["role"])
+			}
+		}
+		w.Header().Set("x-ratelimit-limit-tokens", "30000")
+		w.Header().Set("x-ratelimit-remaining-tokens", "29990")
+		w.Header().Set("x-ratelimit-reset-tokens", "20ms")
+		w.Write([]byte(`{"choices":[{"message":{"content":"Paris"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(writeServerConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := p.GenerateChat(context.Background(), []types.Message{
+		types.NewSystemMessage("Answer with a single word."),
+		types.NewUserMessage("Capital of Italy?"),
+		types.NewAssistantMessage("Rome"),
+		types.NewUserMessage("And France?"),
+	}, "gpt-4.1", map[string]any{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TextContent() != "Paris" {
+		t.Errorf("expected 'Paris', got '%s'", result.TextContent())
+	}
+	if tokens := result.RateLimit().Tokens(); tokens.Limit() != 30000 || tokens.Remaining() != 29990 || result.RateLimit().Requests().Known() {
+		t.Errorf("expected token rate limit headers on the result, got %+v", result.RateLimit())
+	}
+}
+
+func TestProviderSendsImageInput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content []map[string]any `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Messages) != 1 || len(body.Messages[0].Content) != 2 || body.Messages[0].Content[1]["type"] != "image_url" {
+			t.Errorf("expected text and image content parts, got %+v", body.Messages)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"A chart"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(writeServerConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	message := types.NewUserMessageWithParts(types.NewTextPart("What is this?"), types.NewImageURLPart("https://example.com/chart.png"))
+	if _, err := p.GenerateChat(context.Background(), []types.Message{message}, "gpt-4.1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testConfig := fmt.Sprintf("openai:\n  api_key: \"test-key\"\n  base_url: \"%s\"\n  models:\n    - name: \"text-only\"\n", server.URL)
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	p, err = provider.NewOpenAIChatCompletionsProvider(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = p.GenerateChat(context.Background(), []types.Message{message}, "text-only", nil)
+	if !errors.Is(err, types.ErrUnsupportedInput) {
+		t.Errorf("expected ErrUnsupportedInput for a model without vision, got %v", err)
+	}
+}
+
+func TestProviderEmbed(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("expected /embeddings, got %s", r.URL.Path)
+		}
+		var body struct {
+			Input      []string `json:"input"`
+			Dimensions int      `json:"dimensions"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Dimensions != 2 {
+			t.Errorf("expected dimensions 2, got %d", body.Dimensions)
+		}
+		requests++
+		data := make([]map[string]any, len(body.Input))
+		for i := range body.Input {
+			data[i] = map[string]any{"index": i, "embedding": []float64{float64(requests), float64(i)}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"data":  data,
+			"model": "text-embedding-3-small",
+			"usage": map[string]any{"prompt_tokens": len(body.Input), "total_tokens": len(body.Input)},
+		})
+	}))
+	defer server.Close()
+
+	p, err := provider.NewOpenAIChatCompletionsProvider(writeServerConfig(t, server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inputs := make([]string, strategy.EmbeddingsMaxBatchInputs+1)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("chunk %d", i)
+	}
+	result, err := p.Embed(context.Background(), inputs, "text-embedding-3-small", map[string]any{"dimensions": 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected inputs to be split into 2 requests, got %d", requests)
+	}
+	embeddings := result.Embeddings()
+	if len(embeddings) != len(inputs) {
+		t.Fatalf("expected %d embeddings, got %d", len(inputs), len(embeddings))
+	}
+	last := embeddings[len(embeddings)-1]
+	if last.Index() != len(inputs)-1 || last.Vector()[0] != 2 {
+		t.Errorf("expected last embedding to come from the second batch with a global index, got %d %v", last.Index(), last.Vector())
+	}
+	if result.Usage().PromptTokens() != len(inputs) {
+		t.Errorf("expected usage to be summed across batches, got %d", result.Usage().PromptTokens())
+	}
+
+	requests = 0
+	cjk := strings.Repeat("東京都の天気は晴れです。", 200)
+	large := make([]string, 300)
+	for i := range large {
+		large[i] = cjk
+	}
+	if _, err := p.Embed(context.Background(), large, "text-embedding-3-small", map[string]any{"dimensions": 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests < 2 {
+		t.Errorf("expected inputs over the token limit to be split across requests, got %d", requests)
+	}
+
+	requests = 0
+	_, err = p.Embed(context.Background(), []string{"short", strings.Repeat("token ", 9000)}, "text-embedding-3-small", map[string]any{"dimensions": 2})
+	var overflow *types.ContextOverflowError
+	if !errors.As(err, &overflow) || overflow.ContextWindow != 8191 || requests != 0 {
+		t.Errorf("expected an oversized input to be rejected before sending, got %v after %d requests", err, requests)
+	}
+
+	_, err = p.Embed(context.Background(), inputs[:1], "gpt-4.1", nil)
+	if !errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("expected ErrModelNotFound for a chat model, got %v", err)
+	}
+	_, err = p.Embed(context.Background(), inputs[:1], "text-embedding-ada-002", map[string]any{"dimensions": 2})
+	if !errors.Is(err, types.ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for dimensions on ada-002, got %v", err)
+	}
+}
+
+func writeServerConfig(t *testing.T, baseURL string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testConfig := fmt.Sprintf("openai:\n  api_key: \"test-key\"\n  base_url: \"%s\"\n", baseURL)
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	return path
 }
@@ -0,0 +1,283 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/types"
+)
+
+type registryModel struct {
+	Model
+	name string
+}
+
+func (m *registryModel) Name() string {
+	return m.name
+}
+
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	order     []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]Provider)}
+}
+
+func LoadRegistry(configFile string) (*Registry, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewRegistryFromConfig(cfg)
+}
+
+func NewRegistryFromConfig(cfg config.Config) (*Registry, error) {
+	providers := cfg.Providers
+	if len(providers) == 0 {
+		providers = legacyProviderConfigs(cfg)
+	}
+
+	registry := NewRegistry()
+	for _, providerConfig := range providers {
+		p, err := newProviderFromConfig(providerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", providerConfig.ID, err)
+		}
+		if err := registry.Register(providerConfig.ID, p); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+func legacyProviderConfigs(cfg config.Config) []config.ProviderConfig {
+	var providers []config.ProviderConfig
+	if cfg.OpenAI.APIKey != "" || len(cfg.OpenAI.Endpoints) > 0 {
+		providers = append(providers, config.ProviderConfig{
+			ID:              config.ProviderTypeOpenAI,
+			APIKey:          cfg.OpenAI.APIKey,
+			BaseURL:         cfg.OpenAI.BaseURL,
+			Organization:    cfg.OpenAI.Organization,
+			Project:         cfg.OpenAI.Project,
+			Endpoints:       cfg.OpenAI.Endpoints,
+			LoadBalancing:   cfg.OpenAI.LoadBalancing,
+			Models:          cfg.OpenAI.Models,
+			EmbeddingModels: cfg.OpenAI.EmbeddingModels,
+			Retry:           cfg.OpenAI.Retry,
+		})
+	}
+	if cfg.Anthropic.APIKey != "" {
+		providers = append(providers, config.ProviderConfig{
+			ID:      config.ProviderTypeAnthropic,
+			APIKey:  cfg.Anthropic.APIKey,
+			BaseURL: cfg.Anthropic.BaseURL,
+			Version: cfg.Anthropic.Version,
+			Retry:   cfg.Anthropic.Retry,
+		})
+	}
+	if cfg.Ollama.BaseURL != "" {
+		providers = append(providers, config.ProviderConfig{
+			ID:      config.ProviderTypeOllama,
+			BaseURL: cfg.Ollama.BaseURL,
+			Retry:   cfg.Ollama.Retry,
+		})
+	}
+	return providers
+}
+
+func newProviderFromConfig(cfg config.ProviderConfig) (Provider, error) {
+	providerType := cfg.Type
+	if providerType == "" {
+		providerType = cfg.ID
+	}
+
+	switch providerType {
+	case config.ProviderTypeOpenAI:
+		return NewOpenAIChatCompletionsProviderFromConfig(cfg.OpenAI())
+	case config.ProviderTypeAnthropic:
+		return NewAnthropicMessagesProviderFromConfig(cfg.Anthropic())
+	case config.ProviderTypeOllama:
+		return NewOllamaChatProviderFromConfig(cfg.Ollama())
+	default:
+		return nil, fmt.Errorf("%w: unknown provider type %q", types.ErrInvalidConfig, providerType)
+	}
+}
+
+func (r *Registry) Register(id string, p Provider) error {
+	if id == "" {
+		return fmt.Errorf("%w: provider id is required", types.ErrInvalidConfig)
+	}
+	if strings.Contains(id, "/") {
+		return fmt.Errorf("%w: provider id %s must not contain '/'", types.ErrInvalidConfig, id)
+	}
+	if p == nil {
+		return fmt.Errorf("%w: provider %s is nil", types.ErrInvalidConfig, id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[id]; exists {
+		return fmt.Errorf("%w: provider %s is already registered", types.ErrInvalidConfig, id)
+	}
+	r.providers[id] = p
+	r.order = append(r.order, id)
+	return nil
+}
+
+func (r *Registry) Provider(id string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, exists := r.providers[id]
+	if !exists {
+		return nil, &types.ProviderNotFoundError{ID: id}
+	}
+	return p, nil
+}
+
+func (r *Registry) ProviderIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
+
+func (r *Registry) Resolve(address string) (Provider, string, error) {
+	id, modelName, found := strings.Cut(address, "/")
+	if !found || id == "" || modelName == "" {
+		return nil, "", &types.ModelNotFoundError{Model: address, Provider: r.Name()}
+	}
+	p, err := r.Provider(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return p, modelName, nil
+}
+
+func (r *Registry) Name() string {
+	return "Registry"
+}
+
+func (r *Registry) AvailableModels() []Model {
+	var models []Model
+	for _, id := range r.ProviderIDs() {
+		p, err := r.Provider(id)
+		if err != nil {
+			continue
+		}
+		for _, model := range p.AvailableModels() {
+			models = append(models, &registryModel{Model: model, name: id + "/" + model.Name()})
+		}
+	}
+	return models
+}
+
+func (r *Registry) GetModel(address string) (Model, error) {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return nil, err
+	}
+	model, err := p.GetModel(modelName)
+	if err != nil {
+		return nil, err
+	}
+	return &registryModel{Model: model, name: address}, nil
+}
+
+func (r *Registry) ResolveModel(ctx context.Context, address string) (Model, error) {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return nil, err
+	}
+	model, err := ResolveModel(ctx, p, modelName)
+	if err != nil {
+		return nil, err
+	}
+	return &registryModel{Model: model, name: address}, nil
+}
+
+func (r *Registry) AvailableRequestParameters(address string) []types.Parameter {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return []types.Parameter{}
+	}
+	return p.AvailableRequestParameters(modelName)
+}
+
+func (r *Registry) Config() map[string]any {
+	cfg := map[string]any{}
+	for _, id := range r.ProviderIDs() {
+		if p, err := r.Provider(id); err == nil {
+			cfg[id] = p.Config()
+		}
+	}
+	return cfg
+}
+
+func (r *Registry) GenerateText(ctx context.Context, prompt string, address string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	return p.GenerateText(ctx, prompt, modelName, requestParameters)
+}
+
+func (r *Registry) GenerateChat(ctx context.Context, messages []types.Message, address string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	return p.GenerateChat(ctx, messages, modelName, requestParameters)
+}
+
+func (r *Registry) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, address string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	return p.GenerateWithTools(ctx, messages, tools, modelName, requestParameters)
+}
+
+func (r *Registry) AvailableEmbeddingModels() []Model {
+	var models []Model
+	for _, id := range r.ProviderIDs() {
+		p, err := r.Provider(id)
+		if err != nil {
+			continue
+		}
+		embedder, ok := p.(Embedder)
+		if !ok {
+			continue
+		}
+		for _, model := range embedder.AvailableEmbeddingModels() {
+			models = append(models, &registryModel{Model: model, name: id + "/" + model.Name()})
+		}
+	}
+	return models
+}
+
+func (r *Registry) Embed(ctx context.Context, inputs []string, address string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return types.EmbeddingResult{}, err
+	}
+	embedder, ok := p.(Embedder)
+	if !ok {
+		return types.EmbeddingResult{}, fmt.Errorf("%w: provider %s does not support embeddings", types.ErrNotSupported, p.Name())
+	}
+	return embedder.Embed(ctx, inputs, modelName, requestParameters)
+}
+
+func (r *Registry) StreamText(ctx context.Context, prompt string, address string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	p, modelName, err := r.Resolve(address)
+	if err != nil {
+		return nil, err
+	}
+	return p.StreamText(ctx, prompt, modelName, requestParameters)
+}
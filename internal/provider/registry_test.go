@@ -0,0 +1,316 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+func newEchoModelServer(t *testing.T, reply string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprintf(w, `{"choices":[{"message":{"content":"%s from %s"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`, reply, body["model"])
+	}))
+}
+
+func TestLoadRegistry(t *testing.T) {
+	openai := newEchoModelServer(t, "openai")
+	defer openai.Close()
+	groq := newEchoModelServer(t, "groq")
+	defer groq.Close()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testConfig := fmt.Sprintf(`providers:
+  - id: openai
+    type: openai
+    api_key: "test-key"
+    base_url: "%s"
+  - id: groq
+    type: openai
+    api_key: "groq-key"
+    base_url: "%s"
+    models:
+      - name: llama-3.3-70b-versatile
+        parameters: [temperature]
+  - id: anthropic
+    api_key: "anthropic-key"
+`, openai.URL, groq.URL)
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+
+	registry, err := provider.LoadRegistry(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("providers are registered in order", func(t *testing.T) {
+		ids := registry.ProviderIDs()
+		if len(ids) != 3 || ids[0] != "openai" || ids[1] != "groq" || ids[2] != "anthropic" {
+			t.Errorf("unexpected provider ids: %v", ids)
+		}
+	})
+
+	t.Run("models are addressed by provider id", func(t *testing.T) {
+		result, err := registry.GenerateText(context.Background(), "Hello", "groq/llama-3.3-70b-versatile", map[string]any{"temperature": 0.2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.TextContent() != "groq from llama-3.3-70b-versatile" {
+			t.Errorf("unexpected response: %s", result.TextContent())
+		}
+
+		result, err = registry.GenerateText(context.Background(), "Hello", "openai/gpt-4.1", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.TextContent() != "openai from gpt-4.1" {
+			t.Errorf("unexpected response: %s", result.TextContent())
+		}
+	})
+
+	t.Run("available models are qualified", func(t *testing.T) {
+		names := map[string]bool{}
+		for _, model := range registry.AvailableModels() {
+			names[model.Name()] = true
+		}
+		for _, expected := range []string{"openai/gpt-4.1", "openai/gpt-5", "groq/llama-3.3-70b-versatile", "anthropic/claude-sonnet-4-5"} {
+			if !names[expected] {
+				t.Errorf("expected %s in available models, got %v", expected, names)
+			}
+		}
+
+		model, err := registry.GetModel("anthropic/claude-haiku-4-5")
+		if err != nil || model.Name() != "anthropic/claude-haiku-4-5" {
+			t.Errorf("unexpected model %v (%v)", model, err)
+		}
+		if params := registry.AvailableRequestParameters("groq/llama-3.3-70b-versatile"); len(params) != 1 || params[0].Name() != "temperature" || params[0].Type() != types.ParameterNumber {
+			t.Errorf("unexpected parameters: %v", params)
+		}
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := registry.GenerateText(context.Background(), "Hello", "mistral/large", nil)
+		if !errors.Is(err, types.ErrProviderNotFound) {
+			t.Errorf("expected ErrProviderNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unqualified model", func(t *testing.T) {
+		_, err := registry.GenerateText(context.Background(), "Hello", "gpt-4.1", nil)
+		if !errors.Is(err, types.ErrModelNotFound) {
+			t.Errorf("expected ErrModelNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unknown model in known provider", func(t *testing.T) {
+		_, err := registry.GenerateText(context.Background(), "Hello", "groq/gpt-4.1", nil)
+		if !errors.Is(err, types.ErrModelNotFound) {
+			t.Errorf("expected ErrModelNotFound, got %v", err)
+		}
+	})
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	t.Run("falls back to provider sections", func(t *testing.T) {
+		var cfg config.Config
+		cfg.OpenAI.APIKey = "test-key"
+		cfg.Anthropic.APIKey = "anthropic-key"
+
+		registry, err := provider.NewRegistryFromConfig(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids := registry.ProviderIDs()
+		if len(ids) != 2 || ids[0] != "openai" || ids[1] != "anthropic" {
+			t.Errorf("unexpected provider ids: %v", ids)
+		}
+	})
+
+	t.Run("keeps endpoints from the openai section", func(t *testing.T) {
+		var cfg config.Config
+		cfg.OpenAI.Organization = "org-shared"
+		cfg.OpenAI.Endpoints = []config.EndpointConfig{{APIKey: "key-a"}, {APIKey: "key-b", Project: "proj_b"}}
+		cfg.OpenAI.LoadBalancing = config.LoadBalancingConfig{Strategy: provider.BalanceLeastLoaded}
+
+		registry, err := provider.NewRegistryFromConfig(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ids := registry.ProviderIDs(); len(ids) != 1 || ids[0] != "openai" {
+			t.Fatalf("unexpected provider ids: %v", ids)
+		}
+		p, _ := registry.Provider("openai")
+		endpoints := p.(*provider.OpenAIChatCompletionsProvider).Endpoints()
+		if len(endpoints) != 2 || endpoints[0].Organization != "org-shared" || endpoints[1].Project != "proj_b" {
+			t.Errorf("unexpected endpoints: %+v", endpoints)
+		}
+	})
+
+	t.Run("does not require a running ollama server", func(t *testing.T) {
+		var cfg config.Config
+		cfg.OpenAI.APIKey = "test-key"
+		cfg.Ollama.BaseURL = "http://127.0.0.1:1"
+
+		registry, err := provider.NewRegistryFromConfig(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ids := registry.ProviderIDs(); len(ids) != 2 || ids[1] != "ollama" {
+			t.Errorf("unexpected provider ids: %v", ids)
+		}
+		if _, err := registry.GetModel("openai/gpt-4.1"); err != nil {
+			t.Errorf("expected openai models to resolve, got %v", err)
+		}
+		if _, err := registry.GetModel("ollama/llama3.2"); err == nil {
+			t.Error("expected ollama models to fail while the server is down")
+		}
+	})
+
+	t.Run("rejects unknown provider type", func(t *testing.T) {
+		cfg := config.Config{Providers: []config.ProviderConfig{{ID: "x", Type: "bedrock"}}}
+		_, err := provider.NewRegistryFromConfig(cfg)
+		if !errors.Is(err, types.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("rejects duplicate ids", func(t *testing.T) {
+		cfg := config.Config{Providers: []config.ProviderConfig{
+			{ID: "openai", APIKey: "a"},
+			{ID: "openai", APIKey: "b"},
+		}}
+		_, err := provider.NewRegistryFromConfig(cfg)
+		if !errors.Is(err, types.ErrInvalidConfig) {
+			t.Errorf("expected ErrInvalidConfig, got %v", err)
+		}
+	})
+
+	t.Run("reports which provider failed", func(t *testing.T) {
+		cfg := config.Config{Providers: []config.ProviderConfig{{ID: "backup", Type: "openai"}}}
+		_, err := provider.NewRegistryFromConfig(cfg)
+		if !errors.Is(err, types.ErrInvalidConfig) {
+			t.Fatalf("expected ErrInvalidConfig, got %v", err)
+		}
+		if err.Error() != "provider backup: invalid configuration: openai.api_key is required in config file" {
+			t.Errorf("unexpected error message: %v", err)
+		}
+	})
+}
+
+func TestLoadRegistryBalancesEndpoints(t *testing.T) {
+	keys := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys[r.Header.Get("Authorization")]++
+		if r.Header.Get("Authorization") == "Bearer key-a" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testConfig := fmt.Sprintf(`openai:
+  base_url: "%s"
+  endpoints:
+    - api_key: "key-a"
+    - api_key: "key-b"
+  retry:
+    max_attempts: 1
+`, server.URL)
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+
+	registry, err := provider.LoadRegistry(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 3 {
+		if _, err := registry.GenerateText(context.Background(), "Hello", "openai/gpt-4.1", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if keys["Bearer key-a"] != 1 || keys["Bearer key-b"] != 3 {
+		t.Errorf("expected failover away from the rejected key, got %v", keys)
+	}
+}
+
+func TestRegistryRegister(t *testing.T) {
+	p, err := provider.NewOpenAIChatCompletionsProviderFromConfig(config.OpenAIConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	registry := provider.NewRegistry()
+	if err := registry.Register("", p); err == nil {
+		t.Error("expected error for empty id")
+	}
+	if err := registry.Register("open/ai", p); err == nil {
+		t.Error("expected error for id containing '/'")
+	}
+	if err := registry.Register("openai", nil); err == nil {
+		t.Error("expected error for nil provider")
+	}
+	if err := registry.Register("openai", p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := registry.Provider("openai")
+	if err != nil || got != p {
+		t.Errorf("expected registered provider, got %v (%v)", got, err)
+	}
+
+	var _ provider.Provider = registry
+}
+
+func TestRegistryEmbed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"index":0,"embedding":[0.1,0.2]}],"model":"text-embedding-3-small","usage":{"prompt_tokens":1,"total_tokens":1}}`))
+	}))
+	defer server.Close()
+
+	openai, err := provider.NewOpenAIChatCompletionsProviderFromConfig(config.OpenAIConfig{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	anthropic, err := provider.NewAnthropicMessagesProviderFromConfig(config.AnthropicConfig{APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	registry := provider.NewRegistry()
+	registry.Register("openai", openai)
+	registry.Register("anthropic", anthropic)
+
+	result, err := registry.Embed(context.Background(), []string{"hello"}, "openai/text-embedding-3-small", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Vectors()) != 1 {
+		t.Errorf("expected one vector, got %v", result.Vectors())
+	}
+
+	models := registry.AvailableEmbeddingModels()
+	if len(models) != 3 || models[0].Name() != "openai/text-embedding-3-small" {
+		t.Errorf("expected qualified OpenAI embedding models only, got %d", len(models))
+	}
+
+	_, err = registry.Embed(context.Background(), []string{"hello"}, "anthropic/claude-sonnet-4-5", nil)
+	if !errors.Is(err, types.ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported for a provider without embeddings, got %v", err)
+	}
+}
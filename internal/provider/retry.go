@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"agentic-ai-framework/internal/config"
+	"agentic-ai-framework/internal/transport"
+)
+
+func retryPolicy(cfg config.RetryConfig) transport.RetryPolicy {
+	policy := transport.DefaultRetryPolicy()
+	if cfg.MaxAttempts > 0 {
+		policy.MaxAttempts = cfg.MaxAttempts
+	}
+	if cfg.InitialBackoff > 0 {
+		policy.InitialBackoff = cfg.InitialBackoff
+	}
+	if cfg.MaxBackoff > 0 {
+		policy.MaxBackoff = cfg.MaxBackoff
+	}
+	if cfg.MaxElapsed > 0 {
+		policy.MaxElapsed = cfg.MaxElapsed
+	}
+	if cfg.Multiplier > 0 {
+		policy.Multiplier = cfg.Multiplier
+	}
+	if cfg.Jitter > 0 {
+		policy.Jitter = cfg.Jitter
+	}
+	return policy
+}
@@ -1,6 +1,12 @@
 package provider
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"agentic-ai-framework/internal/types"
+)
 
 func ValidateModel(availableModels []Model, modelName string, providerName string) error {
 	for _, m := range availableModels {
@@ -8,20 +14,82 @@ func ValidateModel(availableModels []Model, modelName string, providerName strin
 			return nil
 		}
 	}
-	return fmt.Errorf("model %s is not available in provider %s", modelName, providerName)
+	return &types.ModelNotFoundError{Model: modelName, Provider: providerName}
 }
 
-func ValidateRequestParameters(availableParams []string, requestParameters map[string]any, modelName string) error {
-	availableParamsMap := make(map[string]bool)
-	for _, param := range availableParams {
-		availableParamsMap[param] = true
+func ValidateMessageInput(model Model, messages []types.Message) error {
+	if model.SupportsVision() {
+		return nil
+	}
+	for _, msg := range messages {
+		for _, part := range msg.Parts() {
+			if part.Type() != types.ContentPartText {
+				return &types.UnsupportedInputError{Model: model.Name(), InputType: part.Type()}
+			}
+		}
 	}
+	return nil
+}
 
+func ValidateRequestParameters(availableParams []types.Parameter, requestParameters map[string]any, modelName string) error {
+	keys := make([]string, 0, len(requestParameters))
 	for key := range requestParameters {
-		if !availableParamsMap[key] {
-			return fmt.Errorf("request parameter '%s' is not available for model %s. Available parameters: %v", key, modelName, availableParams)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	given := map[string]string{}
+	for _, key := range keys {
+		param, exists := types.FindParameter(availableParams, key)
+		if !exists {
+			errs = append(errs, &types.ParameterError{Parameter: key, Model: modelName, Available: types.ParameterNames(availableParams)})
+			continue
+		}
+		if previous, seen := given[param.Name()]; seen {
+			errs = append(errs, parameterError(key, modelName, fmt.Sprintf("cannot be combined with '%s', which is the same parameter", previous)))
+			continue
+		}
+		given[param.Name()] = key
+
+		if err := param.Check(requestParameters[key]); err != nil {
+			errs = append(errs, parameterError(key, modelName, err.Error()))
 		}
 	}
-	return nil
+
+	reported := map[[2]string]bool{}
+	for _, param := range availableParams {
+		key, set := given[param.Name()]
+		if !set {
+			continue
+		}
+		for _, other := range param.ExclusiveWith() {
+			otherKey, conflict := given[other]
+			if !conflict || reported[[2]string{other, param.Name()}] {
+				continue
+			}
+			reported[[2]string{param.Name(), other}] = true
+			errs = append(errs, parameterError(key, modelName, fmt.Sprintf("cannot be combined with '%s'", otherKey)))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
+func NormalizeRequestParameters(availableParams []types.Parameter, requestParameters map[string]any) map[string]any {
+	if len(requestParameters) == 0 {
+		return requestParameters
+	}
+	normalized := make(map[string]any, len(requestParameters))
+	for key, value := range requestParameters {
+		if param, exists := types.FindParameter(availableParams, key); exists {
+			key = param.Name()
+		}
+		normalized[key] = value
+	}
+	return normalized
+}
+
+func parameterError(parameter, modelName, reason string) error {
+	return &types.ParameterError{Parameter: parameter, Model: modelName, Reason: reason}
+}
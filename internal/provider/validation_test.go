@@ -1,7 +1,10 @@
 package provider
 
 import (
+	"errors"
 	"testing"
+
+	"agentic-ai-framework/internal/types"
 )
 
 func TestValidateModel(t *testing.T) {
@@ -35,9 +38,33 @@ func TestValidateModel(t *testing.T) {
 	})
 }
 
+func TestValidateMessageInput(t *testing.T) {
+	messages := []types.Message{
+		types.NewUserMessage("Hello"),
+		types.NewUserMessageWithParts(types.NewTextPart("Read this"), types.NewFilePart("report.pdf", []byte("%PDF"), "")),
+	}
+
+	if err := ValidateMessageInput(&mockModel{name: "vision", vision: true}, messages); err != nil {
+		t.Errorf("expected vision model to accept media, got %v", err)
+	}
+	if err := ValidateMessageInput(&mockModel{name: "text"}, messages[:1]); err != nil {
+		t.Errorf("expected text-only messages to be accepted, got %v", err)
+	}
+
+	err := ValidateMessageInput(&mockModel{name: "text"}, messages)
+	expected := "model text does not accept file input"
+	if !errors.Is(err, types.ErrUnsupportedInput) || err.Error() != expected {
+		t.Errorf("expected '%s', got %v", expected, err)
+	}
+}
+
 func TestValidateRequestParameters(t *testing.T) {
 	t.Run("valid parameters", func(t *testing.T) {
-		availableParams := []string{"temperature", "top_p", "max_tokens"}
+		availableParams := []types.Parameter{
+			types.NewParameter("temperature", types.ParameterNumber),
+			types.NewParameter("top_p", types.ParameterNumber),
+			types.NewParameter("max_tokens", types.ParameterInteger),
+		}
 		requestParams := map[string]any{
 			"temperature": 0.7,
 			"top_p":       0.9,
@@ -50,7 +77,7 @@ func TestValidateRequestParameters(t *testing.T) {
 	})
 
 	t.Run("invalid parameter", func(t *testing.T) {
-		availableParams := []string{"temperature", "top_p"}
+		availableParams := testParameters()
 		requestParams := map[string]any{
 			"temperature": 0.7,
 			"max_tokens":  100,
@@ -68,7 +95,7 @@ func TestValidateRequestParameters(t *testing.T) {
 	})
 
 	t.Run("empty request parameters", func(t *testing.T) {
-		availableParams := []string{"temperature", "top_p"}
+		availableParams := testParameters()
 		requestParams := map[string]any{}
 
 		err := ValidateRequestParameters(availableParams, requestParams, "gpt-4")
@@ -78,14 +105,139 @@ func TestValidateRequestParameters(t *testing.T) {
 	})
 }
 
+func TestValidateRequestParameterValues(t *testing.T) {
+	availableParams := []types.Parameter{
+		types.NewParameter("temperature", types.ParameterNumber).WithRange(0, 2).WithExclusive("top_p"),
+		types.NewParameter("top_p", types.ParameterNumber).WithRange(0, 1),
+		types.NewParameter("max_completion_tokens", types.ParameterInteger).WithMinimum(1).WithAliases("max_tokens"),
+		types.NewParameter("reasoning_effort", types.ParameterString).WithEnum("low", "medium", "high"),
+		types.NewParameter("stop", types.ParameterStringList),
+		types.NewParameter("stream", types.ParameterBoolean),
+	}
+
+	t.Run("accepts valid values", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{
+			"temperature":      1,
+			"max_tokens":       256,
+			"reasoning_effort": "low",
+			"stop":             []any{"\n", "END"},
+			"stream":           true,
+		}, "gpt-4.1")
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects wrong type", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{"temperature": "hot"}, "gpt-4.1")
+		expectParameterError(t, err, "temperature", "request parameter 'temperature' is invalid for model gpt-4.1: expected number, got string")
+	})
+
+	t.Run("rejects out of range value", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{"top_p": 7}, "gpt-4.1")
+		expectParameterError(t, err, "top_p", "request parameter 'top_p' is invalid for model gpt-4.1: must be between 0 and 1, got 7")
+	})
+
+	t.Run("rejects fractional integer", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{"max_completion_tokens": 1.5}, "gpt-4.1")
+		expectParameterError(t, err, "max_completion_tokens", "request parameter 'max_completion_tokens' is invalid for model gpt-4.1: expected integer, got 1.5")
+	})
+
+	t.Run("rejects value outside enum", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{"reasoning_effort": "extreme"}, "gpt-5")
+		expectParameterError(t, err, "reasoning_effort", "request parameter 'reasoning_effort' is invalid for model gpt-5: expected one of [low medium high], got extreme")
+	})
+
+	t.Run("rejects mutually exclusive parameters", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{"temperature": 0.5, "top_p": 0.5}, "claude-sonnet-4-5")
+		expectParameterError(t, err, "temperature", "request parameter 'temperature' is invalid for model claude-sonnet-4-5: cannot be combined with 'top_p'")
+	})
+
+	t.Run("rejects alias combined with canonical name", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{"max_tokens": 10, "max_completion_tokens": 10}, "gpt-4.1")
+		expectParameterError(t, err, "max_tokens", "request parameter 'max_tokens' is invalid for model gpt-4.1: cannot be combined with 'max_completion_tokens', which is the same parameter")
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		err := ValidateRequestParameters(availableParams, map[string]any{"temperature": 3, "top_k": 5, "stop": 1}, "gpt-4.1")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		var fields []string
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var paramErr *types.ParameterError
+			if errors.As(e, &paramErr) {
+				fields = append(fields, paramErr.Parameter)
+			}
+		}
+		if len(fields) != 3 || fields[0] != "stop" || fields[1] != "temperature" || fields[2] != "top_k" {
+			t.Errorf("expected errors for stop, temperature and top_k, got %v", fields)
+		}
+	})
+}
+
+func TestNormalizeRequestParameters(t *testing.T) {
+	availableParams := []types.Parameter{
+		types.NewParameter("max_completion_tokens", types.ParameterInteger).WithAliases("max_tokens"),
+	}
+
+	normalized := NormalizeRequestParameters(availableParams, map[string]any{"max_tokens": 100, "custom": true})
+	if normalized["max_completion_tokens"] != 100 {
+		t.Errorf("expected alias to be renamed, got %v", normalized)
+	}
+	if _, exists := normalized["max_tokens"]; exists {
+		t.Errorf("expected alias to be removed, got %v", normalized)
+	}
+	if normalized["custom"] != true {
+		t.Errorf("expected unknown parameters to be kept, got %v", normalized)
+	}
+}
+
+func expectParameterError(t *testing.T, err error, parameter, message string) {
+	t.Helper()
+	var paramErr *types.ParameterError
+	if !errors.As(err, &paramErr) {
+		t.Fatalf("expected ParameterError, got %v", err)
+	}
+	if !errors.Is(err, types.ErrInvalidParameter) {
+		t.Error("expected error to match ErrInvalidParameter")
+	}
+	if paramErr.Parameter != parameter {
+		t.Errorf("expected parameter %s, got %s", parameter, paramErr.Parameter)
+	}
+	if err.Error() != message {
+		t.Errorf("expected '%s', got '%s'", message, err.Error())
+	}
+}
+
 type mockModel struct {
-	name string
+	name   string
+	vision bool
 }
 
 func (m *mockModel) Name() string {
 	return m.name
 }
 
-func (m *mockModel) AvailableRequestParameters() []string {
-	return []string{"temperature", "top_p"}
+func (m *mockModel) AvailableRequestParameters() []types.Parameter {
+	return testParameters()
+}
+
+func (m *mockModel) SupportsVision() bool {
+	return m.vision
+}
+
+func (m *mockModel) ContextWindow() int {
+	return 0
+}
+
+func (m *mockModel) MaxOutputTokens() int {
+	return 0
+}
+
+func testParameters() []types.Parameter {
+	return []types.Parameter{
+		types.NewParameter("temperature", types.ParameterNumber),
+		types.NewParameter("top_p", types.ParameterNumber),
+	}
 }
@@ -0,0 +1,323 @@
+package providertest
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	DefaultName  = "Fake Provider"
+	DefaultModel = "fake-model"
+)
+
+const (
+	MethodGenerateText      = "GenerateText"
+	MethodGenerateChat      = "GenerateChat"
+	MethodGenerateWithTools = "GenerateWithTools"
+	MethodStreamText        = "StreamText"
+)
+
+var ErrNoResponse = errors.New("providertest: no scripted response left")
+
+type Model struct {
+	name            string
+	parameters      []types.Parameter
+	vision          bool
+	contextWindow   int
+	maxOutputTokens int
+}
+
+func NewModel(name string, contextWindow int, maxOutputTokens int) *Model {
+	return &Model{name: name, vision: true, contextWindow: contextWindow, maxOutputTokens: maxOutputTokens}
+}
+
+func (m *Model) Name() string {
+	return m.name
+}
+
+func (m *Model) AvailableRequestParameters() []types.Parameter {
+	return m.parameters
+}
+
+func (m *Model) SupportsVision() bool {
+	return m.vision
+}
+
+func (m *Model) ContextWindow() int {
+	return m.contextWindow
+}
+
+func (m *Model) MaxOutputTokens() int {
+	return m.maxOutputTokens
+}
+
+type Response struct {
+	Text         string
+	ToolCalls    []types.ToolCall
+	Usage        types.TokenUsage
+	FinishReason string
+	Err          error
+	Latency      time.Duration
+}
+
+func TextResponse(text string) Response {
+	return Response{Text: text}
+}
+
+func ToolCallResponse(calls ...types.ToolCall) Response {
+	return Response{ToolCalls: calls}
+}
+
+func ErrorResponse(err error) Response {
+	return Response{Err: err}
+}
+
+func (r Response) WithText(text string) Response {
+	r.Text = text
+	return r
+}
+
+func (r Response) WithUsage(promptTokens int, completionTokens int) Response {
+	r.Usage = types.NewTokenUsage(promptTokens, completionTokens, promptTokens+completionTokens)
+	return r
+}
+
+func (r Response) WithFinishReason(reason string) Response {
+	r.FinishReason = reason
+	return r
+}
+
+func (r Response) WithLatency(latency time.Duration) Response {
+	r.Latency = latency
+	return r
+}
+
+func (r Response) result(model string) types.GenerateTextResult {
+	reason := r.FinishReason
+	if reason == "" {
+		reason = types.FinishReasonStop
+		if len(r.ToolCalls) > 0 {
+			reason = types.FinishReasonToolCalls
+		}
+	}
+	return types.NewGenerateTextResult(r.Text, r.Usage).
+		WithToolCalls(r.ToolCalls).
+		WithFinishReason(reason).
+		WithModel(model).
+		WithAttempts(1)
+}
+
+type Request struct {
+	Method     string
+	Model      string
+	Messages   []types.Message
+	Tools      []types.ToolDefinition
+	Parameters map[string]any
+}
+
+func (r Request) Prompt() string {
+	for i := len(r.Messages) - 1; i >= 0; i-- {
+		if r.Messages[i].Role() == types.RoleUser {
+			return r.Messages[i].Content()
+		}
+	}
+	return ""
+}
+
+type Provider struct {
+	mu        sync.Mutex
+	name      string
+	models    []provider.Model
+	responses []Response
+	fallback  *Response
+	requests  []Request
+}
+
+func New(responses ...Response) *Provider {
+	return &Provider{
+		name:      DefaultName,
+		models:    []provider.Model{NewModel(DefaultModel, 0, 0)},
+		responses: responses,
+	}
+}
+
+func (p *Provider) SetName(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.name = name
+}
+
+func (p *Provider) SetModels(models ...provider.Model) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.models = models
+}
+
+func (p *Provider) Enqueue(responses ...Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.responses = append(p.responses, responses...)
+}
+
+func (p *Provider) SetFallback(response Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallback = &response
+}
+
+func (p *Provider) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.responses)
+}
+
+func (p *Provider) Requests() []Request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Request(nil), p.requests...)
+}
+
+func (p *Provider) LastRequest() (Request, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.requests) == 0 {
+		return Request{}, false
+	}
+	return p.requests[len(p.requests)-1], true
+}
+
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.responses = nil
+	p.fallback = nil
+	p.requests = nil
+}
+
+func (p *Provider) Name() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.name
+}
+
+func (p *Provider) AvailableModels() []provider.Model {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]provider.Model(nil), p.models...)
+}
+
+func (p *Provider) GetModel(modelName string) (provider.Model, error) {
+	for _, model := range p.AvailableModels() {
+		if model.Name() == modelName {
+			return model, nil
+		}
+	}
+	return NewModel(modelName, 0, 0), nil
+}
+
+func (p *Provider) AvailableRequestParameters(modelName string) []types.Parameter {
+	model, _ := p.GetModel(modelName)
+	return model.AvailableRequestParameters()
+}
+
+func (p *Provider) Config() map[string]any {
+	return map[string]any{}
+}
+
+func (p *Provider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.generate(ctx, MethodGenerateText, []types.Message{types.NewUserMessage(prompt)}, nil, modelName, requestParameters)
+}
+
+func (p *Provider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.generate(ctx, MethodGenerateChat, messages, nil, modelName, requestParameters)
+}
+
+func (p *Provider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.generate(ctx, MethodGenerateWithTools, messages, tools, modelName, requestParameters)
+}
+
+func (p *Provider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	response, err := p.next(ctx, MethodStreamText, []types.Message{types.NewUserMessage(prompt)}, nil, modelName, requestParameters)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan types.StreamChunk)
+	go func() {
+		defer close(chunks)
+		for _, delta := range strings.SplitAfter(response.Text, " ") {
+			if delta == "" {
+				continue
+			}
+			if err := send(ctx, chunks, types.NewStreamDelta(delta)); err != nil {
+				return
+			}
+		}
+		send(ctx, chunks, types.NewStreamResult(response.result(modelName)))
+	}()
+	return chunks, nil
+}
+
+func (p *Provider) generate(ctx context.Context, method string, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	response, err := p.next(ctx, method, messages, tools, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	return response.result(modelName), nil
+}
+
+func (p *Provider) next(ctx context.Context, method string, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (Response, error) {
+	p.mu.Lock()
+	p.requests = append(p.requests, Request{
+		Method:     method,
+		Model:      modelName,
+		Messages:   append([]types.Message(nil), messages...),
+		Tools:      append([]types.ToolDefinition(nil), tools...),
+		Parameters: maps.Clone(requestParameters),
+	})
+	var response Response
+	switch {
+	case len(p.responses) > 0:
+		response = p.responses[0]
+		p.responses = p.responses[1:]
+	case p.fallback != nil:
+		response = *p.fallback
+	default:
+		p.mu.Unlock()
+		return Response{}, ErrNoResponse
+	}
+	p.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return Response{}, err
+	}
+	if response.Latency > 0 {
+		timer := time.NewTimer(response.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return Response{}, ctx.Err()
+		}
+	}
+	if response.Err != nil {
+		return Response{}, response.Err
+	}
+	return response, nil
+}
+
+func send(ctx context.Context, chunks chan<- types.StreamChunk, chunk types.StreamChunk) error {
+	select {
+	case chunks <- chunk:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,146 @@
+package providertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/runtime"
+	"agentic-ai-framework/internal/types"
+)
+
+func TestProviderInterfaceCompliance(t *testing.T) {
+	var _ provider.Provider = &Provider{}
+	var _ provider.Model = &Model{}
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("plays scripted responses in order", func(t *testing.T) {
+		call := types.NewToolCall("call_1", "get_weather", `{"city":"Paris"}`)
+		p := New(
+			ToolCallResponse(call).WithText("Checking.").WithUsage(10, 5),
+			TextResponse("Sunny.").WithUsage(20, 3),
+		)
+
+		first, err := p.GenerateWithTools(ctx, []types.Message{types.NewUserMessage("Weather?")}, []types.ToolDefinition{types.NewToolDefinition("get_weather", "", nil)}, "gpt-4.1", map[string]any{"temperature": 0.2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first.TextContent() != "Checking." || len(first.ToolCalls()) != 1 || first.FinishReason() != types.FinishReasonToolCalls || first.Usage().TotalTokens() != 15 || first.Model() != "gpt-4.1" {
+			t.Errorf("unexpected first result: %+v", first)
+		}
+
+		second, err := runtime.GenerateText(ctx, p, "And tomorrow?", "gpt-4.1", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if second.TextContent() != "Sunny." || second.FinishReason() != types.FinishReasonStop || second.Usage().TotalTokens() != 23 {
+			t.Errorf("unexpected second result: %+v", second)
+		}
+
+		if _, err := p.GenerateChat(ctx, nil, "gpt-4.1", nil); !errors.Is(err, ErrNoResponse) {
+			t.Errorf("expected ErrNoResponse once the script is exhausted, got %v", err)
+		}
+		if p.Remaining() != 0 {
+			t.Errorf("expected no responses left, got %d", p.Remaining())
+		}
+	})
+
+	t.Run("records every request", func(t *testing.T) {
+		p := New()
+		p.SetFallback(TextResponse("ok"))
+		params := map[string]any{"temperature": 0.2}
+
+		p.GenerateWithTools(ctx, []types.Message{types.NewSystemMessage("Be brief."), types.NewUserMessage("Hi")}, []types.ToolDefinition{types.NewToolDefinition("lookup", "", nil)}, "gpt-4.1", params)
+		params["temperature"] = 1.0
+		p.GenerateText(ctx, "Bye", "gpt-5", nil)
+
+		requests := p.Requests()
+		if len(requests) != 2 {
+			t.Fatalf("expected 2 requests, got %d", len(requests))
+		}
+		first := requests[0]
+		if first.Method != MethodGenerateWithTools || first.Model != "gpt-4.1" || len(first.Messages) != 2 || first.Prompt() != "Hi" || len(first.Tools) != 1 || first.Parameters["temperature"] != 0.2 {
+			t.Errorf("unexpected first request: %+v", first)
+		}
+		last, ok := p.LastRequest()
+		if !ok || last.Method != MethodGenerateText || last.Prompt() != "Bye" || last.Model != "gpt-5" {
+			t.Errorf("unexpected last request: %+v", last)
+		}
+
+		p.Reset()
+		if len(p.Requests()) != 0 {
+			t.Error("expected requests to be cleared")
+		}
+		if _, err := p.GenerateText(ctx, "Hi", DefaultModel, nil); !errors.Is(err, ErrNoResponse) {
+			t.Errorf("expected fallback to be cleared, got %v", err)
+		}
+	})
+
+	t.Run("scripted errors and latency", func(t *testing.T) {
+		boom := errors.New("boom")
+		p := New(ErrorResponse(boom), TextResponse("slow").WithLatency(time.Second))
+
+		if _, err := p.GenerateText(ctx, "Hi", DefaultModel, nil); !errors.Is(err, boom) {
+			t.Errorf("expected scripted error, got %v", err)
+		}
+
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		if _, err := p.GenerateText(timeout, "Hi", DefaultModel, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline error, got %v", err)
+		}
+		if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+			t.Errorf("expected latency to respect the context, took %v", elapsed)
+		}
+		if len(p.Requests()) != 2 {
+			t.Errorf("expected failed calls to be recorded, got %d", len(p.Requests()))
+		}
+	})
+
+	t.Run("streams text word by word", func(t *testing.T) {
+		p := New(TextResponse("one two three").WithUsage(3, 3))
+
+		chunks, err := runtime.StreamText(ctx, p, "Count", DefaultModel, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var deltas []string
+		var result types.GenerateTextResult
+		for chunk := range chunks {
+			if chunk.Done() {
+				result = chunk.Result()
+				continue
+			}
+			deltas = append(deltas, chunk.TextDelta())
+		}
+		if len(deltas) != 3 || deltas[1] != "two " {
+			t.Errorf("unexpected deltas: %q", deltas)
+		}
+		if result.TextContent() != "one two three" || result.Usage().TotalTokens() != 6 {
+			t.Errorf("unexpected stream result: %+v", result)
+		}
+		if last, _ := p.LastRequest(); last.Method != MethodStreamText || last.Prompt() != "Count" {
+			t.Errorf("unexpected stream request: %+v", last)
+		}
+	})
+
+	t.Run("models", func(t *testing.T) {
+		p := New()
+		p.SetName("Scripted")
+		p.SetModels(NewModel("small", 8192, 1024))
+
+		model, err := p.GetModel("small")
+		if err != nil || model.ContextWindow() != 8192 || model.MaxOutputTokens() != 1024 || p.Name() != "Scripted" {
+			t.Errorf("unexpected model: %+v", model)
+		}
+		if model, _ := p.GetModel("anything"); model.Name() != "anything" || model.ContextWindow() != 0 {
+			t.Errorf("expected unknown models to be accepted, got %+v", model)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type Limit struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+type Options struct {
+	Limit  Limit
+	Models map[string]Limit
+}
+
+type Stats struct {
+	Requests int
+	Waits    int
+	Waited   time.Duration
+}
+
+type Limiter struct {
+	mu      sync.Mutex
+	options Options
+	buckets map[bucketKey]*modelLimits
+	stats   Stats
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+type bucketKey struct {
+	key   string
+	model string
+}
+
+type modelLimits struct {
+	requests *bucket
+	tokens   *bucket
+}
+
+func New(options Options) *Limiter {
+	return &Limiter{
+		options: options,
+		buckets: make(map[bucketKey]*modelLimits),
+		now:     time.Now,
+		sleep:   sleep,
+	}
+}
+
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+func (l *Limiter) Wait(ctx context.Context, key string, model string, tokens int) (*Reservation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	limits := l.limits(bucketKey{key, model}, now)
+	reservation := &Reservation{limiter: l, bucket: bucketKey{key, model}, tokens: limits.tokens.clamp(tokens), charged: limits.tokens != nil, chargedRequest: limits.requests != nil}
+	wait := max(limits.requests.take(1, now), limits.tokens.take(float64(reservation.tokens), now))
+	l.stats.Requests++
+	if wait > 0 {
+		l.stats.Waits++
+		l.stats.Waited += wait
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return reservation, nil
+	}
+	if err := l.sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		limits.requests.give(1)
+		limits.tokens.give(float64(reservation.tokens))
+		l.mu.Unlock()
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (l *Limiter) Observe(key string, model string, rateLimit types.RateLimit) {
+	l.observe(bucketKey{key, model}, rateLimit)
+}
+
+func (l *Limiter) observe(bucket bucketKey, rateLimit types.RateLimit) {
+	if !rateLimit.Known() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	limits := l.limits(bucket, now)
+	limits.requests = observe(limits.requests, rateLimit.Requests(), now)
+	limits.tokens = observe(limits.tokens, rateLimit.Tokens(), now)
+}
+
+func (l *Limiter) limits(bucket bucketKey, now time.Time) *modelLimits {
+	limits, exists := l.buckets[bucket]
+	if !exists {
+		limit, configured := l.options.Models[bucket.model]
+		if !configured {
+			limit = l.options.Limit
+		}
+		limits = &modelLimits{
+			requests: newBucket(limit.RequestsPerMinute, now),
+			tokens:   newBucket(limit.TokensPerMinute, now),
+		}
+		l.buckets[bucket] = limits
+	}
+	return limits
+}
+
+type Reservation struct {
+	limiter        *Limiter
+	bucket         bucketKey
+	tokens         int
+	charged        bool
+	chargedRequest bool
+}
+
+func (r *Reservation) Tokens() int {
+	return r.tokens
+}
+
+func (r *Reservation) Complete(usage types.TokenUsage, rateLimit types.RateLimit) {
+	if actual := usage.TotalTokens(); actual > 0 {
+		reserved := 0
+		if r.charged {
+			reserved = r.tokens
+		}
+		r.limiter.mu.Lock()
+		now := r.limiter.now()
+		limits := r.limiter.limits(r.bucket, now)
+		limits.tokens.advance(now)
+		limits.tokens.give(float64(reserved - actual))
+		r.limiter.mu.Unlock()
+	}
+	r.limiter.observe(r.bucket, rateLimit)
+}
+
+func (r *Reservation) Cancel(rateLimit types.RateLimit) {
+	r.limiter.mu.Lock()
+	now := r.limiter.now()
+	limits := r.limiter.limits(r.bucket, now)
+	if r.chargedRequest {
+		limits.requests.advance(now)
+		limits.requests.give(1)
+	}
+	if r.charged {
+		limits.tokens.advance(now)
+		limits.tokens.give(float64(r.tokens))
+	}
+	r.limiter.mu.Unlock()
+	r.limiter.observe(r.bucket, rateLimit)
+}
+
+type bucket struct {
+	capacity float64
+	level    float64
+	updated  time.Time
+}
+
+func newBucket(perMinute int, now time.Time) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{capacity: float64(perMinute), level: float64(perMinute), updated: now}
+}
+
+func (b *bucket) rate() float64 {
+	return b.capacity / float64(time.Minute)
+}
+
+func (b *bucket) advance(now time.Time) {
+	if b == nil || !now.After(b.updated) {
+		return
+	}
+	b.level = min(b.capacity, b.level+float64(now.Sub(b.updated))*b.rate())
+	b.updated = now
+}
+
+func (b *bucket) clamp(n int) int {
+	if b == nil {
+		return max(n, 0)
+	}
+	return min(max(n, 0), int(b.capacity))
+}
+
+func (b *bucket) take(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.advance(now)
+	b.level -= n
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.rate())
+}
+
+func (b *bucket) give(n float64) {
+	if b == nil {
+		return
+	}
+	b.level = min(b.capacity, b.level+n)
+}
+
+func observe(b *bucket, window types.RateLimitWindow, now time.Time) *bucket {
+	if !window.Known() {
+		return b
+	}
+	if b == nil {
+		b = newBucket(window.Limit(), now)
+	}
+	b.advance(now)
+	b.level = min(b.level, float64(window.Remaining()))
+	if window.Remaining() == 0 && window.Reset() > 0 {
+		b.level = min(b.level, -float64(window.Reset())*b.rate())
+	}
+	return b
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
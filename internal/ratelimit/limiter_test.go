@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type fakeClock struct {
+	now    time.Time
+	waits  []time.Duration
+	cancel bool
+}
+
+func newTestLimiter(options Options) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := New(options)
+	l.now = func() time.Time { return clock.now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		clock.waits = append(clock.waits, d)
+		if clock.cancel {
+			return context.Canceled
+		}
+		clock.now = clock.now.Add(d)
+		return nil
+	}
+	return l, clock
+}
+
+func TestLimiterWait(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("queues requests over the per-minute limit", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{Limit: Limit{RequestsPerMinute: 2}})
+
+		for range 3 {
+			if _, err := l.Wait(ctx, "", "gpt-4.1", 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if len(clock.waits) != 1 || clock.waits[0] != 30*time.Second {
+			t.Errorf("expected the third request to wait 30s, got %v", clock.waits)
+		}
+		if stats := l.Stats(); stats.Requests != 3 || stats.Waits != 1 || stats.Waited != 30*time.Second {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("limits tokens per model", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{
+			Limit:  Limit{TokensPerMinute: 1000},
+			Models: map[string]Limit{"gpt-5": {TokensPerMinute: 100}},
+		})
+
+		l.Wait(ctx, "", "gpt-4.1", 600)
+		l.Wait(ctx, "", "gpt-5", 100)
+		if len(clock.waits) != 0 {
+			t.Fatalf("expected separate buckets per model, got waits %v", clock.waits)
+		}
+		l.Wait(ctx, "", "gpt-4.1", 600)
+		if len(clock.waits) != 1 || clock.waits[0] != 12*time.Second {
+			t.Errorf("expected a 12s wait for 200 missing tokens, got %v", clock.waits)
+		}
+
+		reservation, _ := l.Wait(ctx, "", "gpt-5", 5000)
+		if reservation.Tokens() != 100 {
+			t.Errorf("expected oversized estimate to be capped at the limit, got %d", reservation.Tokens())
+		}
+	})
+
+	t.Run("limits each key separately", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{Limit: Limit{RequestsPerMinute: 1}})
+
+		l.Wait(ctx, "key-a", "gpt-4.1", 0)
+		l.Wait(ctx, "key-b", "gpt-4.1", 0)
+		if len(clock.waits) != 0 {
+			t.Fatalf("expected separate buckets per key, got waits %v", clock.waits)
+		}
+
+		l.Observe("key-a", "gpt-5", types.NewRateLimit(types.NewRateLimitWindow(60, 0, 5*time.Second), types.RateLimitWindow{}))
+		l.Wait(ctx, "key-b", "gpt-5", 0)
+		if len(clock.waits) != 0 {
+			t.Fatalf("expected headers from one key not to limit another, got waits %v", clock.waits)
+		}
+		l.Wait(ctx, "key-a", "gpt-5", 0)
+		if len(clock.waits) != 1 || clock.waits[0] < 5*time.Second {
+			t.Errorf("expected key-a to wait for its reset, got %v", clock.waits)
+		}
+	})
+
+	t.Run("cancel returns the reserved capacity", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{Limit: Limit{RequestsPerMinute: 1, TokensPerMinute: 1000}})
+
+		reservation, _ := l.Wait(ctx, "", "gpt-4.1", 900)
+		reservation.Cancel(types.RateLimit{})
+		l.Wait(ctx, "", "gpt-4.1", 900)
+		if len(clock.waits) != 0 {
+			t.Errorf("expected a cancelled reservation not to use the budget, got waits %v", clock.waits)
+		}
+	})
+
+	t.Run("reconciles estimates with actual usage", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{Limit: Limit{TokensPerMinute: 1000}})
+
+		reservation, _ := l.Wait(ctx, "", "gpt-4.1", 900)
+		reservation.Complete(types.NewTokenUsage(80, 20, 100), types.RateLimit{})
+		l.Wait(ctx, "", "gpt-4.1", 900)
+		if len(clock.waits) != 0 {
+			t.Errorf("expected unused estimate to be refunded, got waits %v", clock.waits)
+		}
+	})
+
+	t.Run("adapts to rate limit headers", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{})
+
+		reservation, _ := l.Wait(ctx, "", "gpt-4.1", 10)
+		reservation.Complete(types.NewTokenUsage(5, 5, 10), types.NewRateLimit(
+			types.NewRateLimitWindow(60, 0, 2*time.Second),
+			types.NewRateLimitWindow(10000, 9990, 60*time.Millisecond),
+		))
+		l.Wait(ctx, "", "gpt-4.1", 10)
+		if len(clock.waits) != 1 || clock.waits[0] != 3*time.Second {
+			t.Errorf("expected to wait for the request window to reset, got %v", clock.waits)
+		}
+	})
+
+	t.Run("cancellation releases the reservation", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{Limit: Limit{RequestsPerMinute: 1}})
+
+		l.Wait(ctx, "", "gpt-4.1", 0)
+		clock.cancel = true
+		if _, err := l.Wait(ctx, "", "gpt-4.1", 0); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		clock.cancel = false
+		l.Wait(ctx, "", "gpt-4.1", 0)
+		if len(clock.waits) != 2 || clock.waits[1] != time.Minute {
+			t.Errorf("expected cancelled request not to hold a slot, got %v", clock.waits)
+		}
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := l.Wait(cancelled, "", "gpt-4.1", 0); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("unlimited by default", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{})
+		for range 100 {
+			l.Wait(ctx, "", "gpt-4.1", 100000)
+		}
+		if len(clock.waits) != 0 {
+			t.Errorf("expected no waits without limits, got %d", len(clock.waits))
+		}
+	})
+}
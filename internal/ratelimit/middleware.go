@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+
+	"agentic-ai-framework/internal/middleware"
+	"agentic-ai-framework/internal/runtime"
+	"agentic-ai-framework/internal/types"
+)
+
+func (l *Limiter) Middleware() middleware.Middleware {
+	return func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+			done, err := l.Reserve(ctx, "", request.Model, request.Messages, request.Parameters)
+			if err != nil {
+				return types.GenerateTextResult{}, err
+			}
+			result, err := next(ctx, request)
+			done(result, err)
+			return result, err
+		}
+	}
+}
+
+func (l *Limiter) Reserve(ctx context.Context, key string, modelName string, messages []types.Message, requestParameters map[string]any) (func(result types.GenerateTextResult, err error), error) {
+	reservation, err := l.Wait(ctx, key, modelName, EstimateTokens(middleware.Request{Model: modelName, Messages: messages, Parameters: requestParameters}))
+	if err != nil {
+		return nil, err
+	}
+	return func(result types.GenerateTextResult, err error) {
+		if err != nil {
+			var rateLimit types.RateLimit
+			var apiErr *types.APIError
+			if errors.As(err, &apiErr) {
+				rateLimit = apiErr.RateLimit
+			}
+			reservation.Cancel(rateLimit)
+			return
+		}
+		reservation.Complete(result.Usage(), result.RateLimit())
+	}, nil
+}
+
+func EstimateTokens(request middleware.Request) int {
+	return runtime.EstimatePromptTokens(request.Model, request.Messages) + runtime.RequestedOutputTokens(request.Parameters)
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/middleware"
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+func TestMiddleware(t *testing.T) {
+	ctx := context.Background()
+	request := middleware.Request{
+		Provider:   "OpenAI Chat Completions",
+		Model:      "gpt-4.1",
+		Messages:   []types.Message{types.NewUserMessage("Summarize the quarterly report in three bullet points.")},
+		Parameters: map[string]any{"max_completion_tokens": 200},
+	}
+
+	t.Run("estimates prompt and output tokens", func(t *testing.T) {
+		estimate := EstimateTokens(request)
+		if estimate <= 200 || estimate > 240 {
+			t.Errorf("expected estimate of prompt plus 200 output tokens, got %d", estimate)
+		}
+	})
+
+	t.Run("waits before calling the provider and reconciles usage", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{Limit: Limit{TokensPerMinute: 300}})
+		calls := 0
+		handler := l.Middleware()(func(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+			calls++
+			return types.NewGenerateTextResult("ok", types.NewTokenUsage(20, 10, 30)), nil
+		})
+
+		for range 3 {
+			if _, err := handler(ctx, request); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if calls != 3 || len(clock.waits) != 0 {
+			t.Errorf("expected reconciled usage to leave room for three calls, got %d calls and waits %v", calls, clock.waits)
+		}
+	})
+
+	t.Run("failed calls release their reservation", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{Limit: Limit{RequestsPerMinute: 2, TokensPerMinute: 300}})
+		handler := l.Middleware()(func(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+			return types.GenerateTextResult{}, errors.New("connection reset")
+		})
+
+		for range 4 {
+			handler(ctx, request)
+		}
+		if len(clock.waits) != 0 {
+			t.Errorf("expected failed calls not to use the budget, got waits %v", clock.waits)
+		}
+	})
+
+	t.Run("observes rate limit headers on errors", func(t *testing.T) {
+		l, clock := newTestLimiter(Options{})
+		handler := l.Middleware()(func(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+			apiErr := types.NewAPIError(http.StatusTooManyRequests, "requests", "rate_limit_exceeded", "Rate limit reached", "")
+			apiErr.RateLimit = types.NewRateLimit(types.NewRateLimitWindow(60, 0, 5*time.Second), types.RateLimitWindow{})
+			return types.GenerateTextResult{}, apiErr
+		})
+
+		handler(ctx, request)
+		handler(ctx, request)
+		if len(clock.waits) != 1 || clock.waits[0] < 5*time.Second {
+			t.Errorf("expected second call to wait for the reset, got %v", clock.waits)
+		}
+	})
+
+	t.Run("respects context cancellation while queued", func(t *testing.T) {
+		l := New(Options{Limit: Limit{RequestsPerMinute: 1}})
+		handler := l.Middleware()(func(ctx context.Context, request middleware.Request) (types.GenerateTextResult, error) {
+			return types.NewGenerateTextResult("ok", types.NewTokenUsage(1, 1, 2)), nil
+		})
+		handler(ctx, request)
+
+		timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		if _, err := handler(timeout, request); err != context.DeadlineExceeded {
+			t.Errorf("expected deadline error, got %v", err)
+		}
+		if elapsed := time.Since(started); elapsed > time.Second {
+			t.Errorf("expected queued call to stop at the deadline, took %v", elapsed)
+		}
+	})
+}
+
+func TestLimiterImplementsRateLimiter(t *testing.T) {
+	var _ provider.RateLimiter = New(Options{})
+}
@@ -0,0 +1,218 @@
+package retrieval
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"agentic-ai-framework/internal/tokenizer"
+)
+
+type Document struct {
+	ID       string
+	Text     string
+	Metadata map[string]string
+}
+
+type Chunk struct {
+	ID         string
+	DocumentID string
+	Index      int
+	Text       string
+	Metadata   map[string]string
+}
+
+type Chunker interface {
+	Split(text string) []string
+}
+
+type CharacterChunker struct {
+	size    int
+	overlap int
+}
+
+func NewCharacterChunker(size int, overlap int) (*CharacterChunker, error) {
+	if err := validateChunkSize(size, overlap); err != nil {
+		return nil, err
+	}
+	return &CharacterChunker{size: size, overlap: overlap}, nil
+}
+
+func (c *CharacterChunker) Split(text string) []string {
+	runes := []rune(text)
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := min(start+c.size, len(runes))
+		if end < len(runes) {
+			end = breakAtSpace(runes, start, end)
+		}
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end == len(runes) {
+			break
+		}
+		start = startAtWord(runes, max(end-c.overlap, start+1), end)
+	}
+	return chunks
+}
+
+func startAtWord(runes []rune, start int, end int) int {
+	for i := start; i < end; i++ {
+		if i == 0 || unicode.IsSpace(runes[i-1]) {
+			return i
+		}
+	}
+	return start
+}
+
+func breakAtSpace(runes []rune, start int, end int) int {
+	for i := end; i > start+(end-start)/2; i-- {
+		if unicode.IsSpace(runes[i-1]) {
+			return i
+		}
+	}
+	return end
+}
+
+var wordPattern = regexp.MustCompile(`\S+\s*`)
+
+type WordChunker struct {
+	size    int
+	overlap int
+}
+
+func NewWordChunker(size int, overlap int) (*WordChunker, error) {
+	if err := validateChunkSize(size, overlap); err != nil {
+		return nil, err
+	}
+	return &WordChunker{size: size, overlap: overlap}, nil
+}
+
+func (c *WordChunker) Split(text string) []string {
+	spans := wordPattern.FindAllStringIndex(text, -1)
+	var chunks []string
+	for start := 0; start < len(spans); start += c.size - c.overlap {
+		end := min(start+c.size, len(spans))
+		chunks = append(chunks, strings.TrimSpace(text[spans[start][0]:spans[end-1][1]]))
+		if end == len(spans) {
+			break
+		}
+	}
+	return chunks
+}
+
+var tokenSpanPattern = regexp.MustCompile(`\s*\S+`)
+
+type TokenChunker struct {
+	tokenizer tokenizer.Tokenizer
+	size      int
+	overlap   int
+}
+
+type tokenSpan struct {
+	start  int
+	end    int
+	tokens int
+}
+
+func NewTokenChunker(modelName string, size int, overlap int) (*TokenChunker, error) {
+	if err := validateChunkSize(size, overlap); err != nil {
+		return nil, err
+	}
+	return &TokenChunker{tokenizer: tokenizer.ForModel(modelName), size: size, overlap: overlap}, nil
+}
+
+func (c *TokenChunker) Split(text string) []string {
+	spans := c.spans(text)
+	var chunks []string
+	for start := 0; start < len(spans); {
+		end, tokens := start, 0
+		for end < len(spans) && (end == start || tokens+spans[end].tokens <= c.size) {
+			tokens += spans[end].tokens
+			end++
+		}
+		for end-start > 1 && c.tokenizer.Count(strings.TrimSpace(text[spans[start].start:spans[end-1].end])) > c.size {
+			end--
+		}
+		if chunk := strings.TrimSpace(text[spans[start].start:spans[end-1].end]); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end == len(spans) {
+			break
+		}
+		next, overlap := end, 0
+		for next-1 > start && overlap+spans[next-1].tokens <= c.overlap {
+			next--
+			overlap += spans[next].tokens
+		}
+		start = next
+	}
+	return chunks
+}
+
+func (c *TokenChunker) spans(text string) []tokenSpan {
+	var spans []tokenSpan
+	for _, loc := range tokenSpanPattern.FindAllStringIndex(text, -1) {
+		for start := loc[0]; start < loc[1]; {
+			end := c.fit(text, start, loc[1])
+			spans = append(spans, tokenSpan{start: start, end: end, tokens: c.tokenizer.Count(text[start:end])})
+			start = end
+		}
+	}
+	return spans
+}
+
+func (c *TokenChunker) fit(text string, start int, end int) int {
+	if c.tokenizer.Count(text[start:end]) <= c.size {
+		return end
+	}
+	var cuts []int
+	for i := range text[start:end] {
+		if i > 0 {
+			cuts = append(cuts, start+i)
+		}
+	}
+	_, first := utf8.DecodeRuneInString(text[start:end])
+	best := start + first
+	low, high := 0, len(cuts)-1
+	for low <= high {
+		mid := (low + high) / 2
+		if c.tokenizer.Count(text[start:cuts[mid]]) <= c.size {
+			best = max(best, cuts[mid])
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	return best
+}
+
+func validateChunkSize(size int, overlap int) error {
+	if size <= 0 {
+		return fmt.Errorf("chunk size must be positive, got %d", size)
+	}
+	if overlap < 0 || overlap >= size {
+		return fmt.Errorf("chunk overlap must be between 0 and %d, got %d", size-1, overlap)
+	}
+	return nil
+}
+
+func ChunkDocuments(chunker Chunker, documents ...Document) []Chunk {
+	var chunks []Chunk
+	for _, document := range documents {
+		for i, text := range chunker.Split(document.Text) {
+			chunks = append(chunks, Chunk{
+				ID:         fmt.Sprintf("%s#%d", document.ID, i),
+				DocumentID: document.ID,
+				Index:      i,
+				Text:       text,
+				Metadata:   maps.Clone(document.Metadata),
+			})
+		}
+	}
+	return chunks
+}
@@ -0,0 +1,157 @@
+package retrieval
+
+import (
+	"strings"
+	"testing"
+
+	"agentic-ai-framework/internal/tokenizer"
+)
+
+func TestCharacterChunker(t *testing.T) {
+	t.Run("splits with overlap at word boundaries", func(t *testing.T) {
+		chunker, err := NewCharacterChunker(20, 6)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		chunks := chunker.Split("The quick brown fox jumps over the lazy dog")
+		if len(chunks) < 2 {
+			t.Fatalf("expected several chunks, got %v", chunks)
+		}
+		for _, chunk := range chunks {
+			if len(chunk) > 20 {
+				t.Errorf("chunk exceeds size: %q", chunk)
+			}
+		}
+		if chunks[0] != "The quick brown fox" {
+			t.Errorf("expected first chunk to end at a word boundary, got %q", chunks[0])
+		}
+		if !strings.HasPrefix(chunks[1], "fox") {
+			t.Errorf("expected second chunk to overlap the first, got %q", chunks[1])
+		}
+	})
+
+	t.Run("short text is a single chunk", func(t *testing.T) {
+		chunker, _ := NewCharacterChunker(100, 10)
+		if chunks := chunker.Split("hello"); len(chunks) != 1 || chunks[0] != "hello" {
+			t.Errorf("unexpected chunks: %v", chunks)
+		}
+	})
+
+	t.Run("invalid sizes", func(t *testing.T) {
+		if _, err := NewCharacterChunker(0, 0); err == nil {
+			t.Error("expected error for zero size")
+		}
+		if _, err := NewCharacterChunker(10, 10); err == nil {
+			t.Error("expected error for overlap equal to size")
+		}
+	})
+}
+
+func TestWordChunker(t *testing.T) {
+	chunker, err := NewWordChunker(4, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	chunks := chunker.Split("one two three four five six seven")
+	expected := []string{"one two three four", "four five six seven"}
+	if strings.Join(chunks, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %v, got %v", expected, chunks)
+	}
+
+	if chunks := chunker.Split("line one\nline two"); chunks[0] != "line one\nline two" {
+		t.Errorf("expected original whitespace to be kept, got %q", chunks[0])
+	}
+}
+
+func TestTokenChunker(t *testing.T) {
+	counter := tokenizer.ForModel("text-embedding-3-small")
+
+	t.Run("chunks fit the size in tokens with overlap", func(t *testing.T) {
+		chunker, err := NewTokenChunker("text-embedding-3-small", 12, 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		text := strings.Repeat("Retrieval splits long documents into overlapping chunks before embedding them. ", 6)
+		chunks := chunker.Split(text)
+		if len(chunks) < 3 {
+			t.Fatalf("expected several chunks, got %v", chunks)
+		}
+		for _, chunk := range chunks {
+			if count := counter.Count(chunk); count > 12 {
+				t.Errorf("chunk has %d tokens, more than 12: %q", count, chunk)
+			}
+		}
+		for i := 1; i < len(chunks); i++ {
+			overlap, prefix := "", ""
+			for _, word := range strings.Fields(chunks[i]) {
+				prefix = strings.TrimSpace(prefix + " " + word)
+				if strings.HasSuffix(chunks[i-1], prefix) {
+					overlap = prefix
+				}
+			}
+			if overlap == "" || counter.Count(" "+overlap) > 4 {
+				t.Errorf("expected chunk %d to overlap the previous one, got %q after %q", i, chunks[i], chunks[i-1])
+			}
+		}
+		if !strings.HasPrefix(chunks[0], "Retrieval") || !strings.HasSuffix(chunks[len(chunks)-1], "them.") {
+			t.Errorf("expected the chunks to cover the text, got %v", chunks)
+		}
+	})
+
+	t.Run("splits words longer than the size", func(t *testing.T) {
+		chunker, _ := NewTokenChunker("text-embedding-3-small", 5, 0)
+		word := strings.Repeat("SGVsbG8sIFdvcmxkIQ", 10)
+		chunks := chunker.Split("intro " + word)
+		if strings.Join(chunks, "") != "intro"+word {
+			t.Errorf("expected the long word to be kept in order, got %v", chunks)
+		}
+		for _, chunk := range chunks {
+			if count := counter.Count(chunk); count > 5 {
+				t.Errorf("chunk has %d tokens, more than 5: %q", count, chunk)
+			}
+		}
+	})
+
+	t.Run("unknown models use the heuristic", func(t *testing.T) {
+		chunker, _ := NewTokenChunker("llama3", 4, 0)
+		for _, chunk := range chunker.Split("one two three four five six seven eight nine ten") {
+			if count := (tokenizer.Heuristic{}).Count(chunk); count > 4 {
+				t.Errorf("chunk has %d tokens, more than 4: %q", count, chunk)
+			}
+		}
+		if chunks := chunker.Split("  \n "); len(chunks) != 0 {
+			t.Errorf("expected no chunks for blank text, got %q", chunks)
+		}
+	})
+
+	t.Run("invalid sizes", func(t *testing.T) {
+		if _, err := NewTokenChunker("gpt-4.1", 0, 0); err == nil {
+			t.Error("expected error for zero size")
+		}
+		if _, err := NewTokenChunker("gpt-4.1", 8, 8); err == nil {
+			t.Error("expected error for overlap equal to size")
+		}
+	})
+}
+
+func TestChunkDocuments(t *testing.T) {
+	chunker, _ := NewWordChunker(2, 0)
+	chunks := ChunkDocuments(chunker,
+		Document{ID: "a", Text: "one two three", Metadata: map[string]string{"lang": "en"}},
+		Document{ID: "b", Text: "four"},
+	)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	if chunks[1].ID != "a#1" || chunks[1].DocumentID != "a" || chunks[1].Index != 1 || chunks[1].Text != "three" {
+		t.Errorf("unexpected chunk: %+v", chunks[1])
+	}
+	chunks[0].Metadata["lang"] = "fr"
+	if chunks[1].Metadata["lang"] != "en" {
+		t.Error("expected each chunk to get its own copy of the metadata")
+	}
+}
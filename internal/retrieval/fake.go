@@ -0,0 +1,75 @@
+package retrieval
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	FakeEmbeddingModel             = "fake-embedding"
+	DefaultFakeEmbeddingDimensions = 64
+)
+
+type fakeModel struct{}
+
+func (fakeModel) Name() string {
+	return FakeEmbeddingModel
+}
+
+func (fakeModel) AvailableRequestParameters() []types.Parameter {
+	return []types.Parameter{}
+}
+
+func (fakeModel) SupportsVision() bool {
+	return false
+}
+
+func (fakeModel) ContextWindow() int {
+	return 0
+}
+
+func (fakeModel) MaxOutputTokens() int {
+	return 0
+}
+
+type FakeEmbedder struct {
+	dimensions int
+}
+
+func NewFakeEmbedder(dimensions int) *FakeEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultFakeEmbeddingDimensions
+	}
+	return &FakeEmbedder{dimensions: dimensions}
+}
+
+func (e *FakeEmbedder) AvailableEmbeddingModels() []provider.Model {
+	return []provider.Model{fakeModel{}}
+}
+
+func (e *FakeEmbedder) Embed(ctx context.Context, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	if err := ctx.Err(); err != nil {
+		return types.EmbeddingResult{}, err
+	}
+	embeddings := make([]types.Embedding, len(inputs))
+	tokens := 0
+	for i, input := range inputs {
+		vector := make([]float64, e.dimensions)
+		words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		for _, word := range words {
+			hash := fnv.New32a()
+			hash.Write([]byte(word))
+			vector[hash.Sum32()%uint32(e.dimensions)]++
+		}
+		tokens += len(words)
+		embeddings[i] = types.NewEmbedding(i, vector)
+	}
+	return types.NewEmbeddingResult(embeddings, types.NewTokenUsage(tokens, 0, tokens)).WithModel(FakeEmbeddingModel), nil
+}
@@ -0,0 +1,257 @@
+package retrieval
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+type Record struct {
+	ID         string            `json:"id"`
+	DocumentID string            `json:"document_id"`
+	Text       string            `json:"text"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Vector     []float64         `json:"vector"`
+}
+
+type SearchResult struct {
+	Record
+	Score float64
+}
+
+type Filter map[string]string
+
+func (f Filter) Matches(metadata map[string]string) bool {
+	for key, value := range f {
+		if metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+type MemoryIndex struct {
+	mu         sync.RWMutex
+	records    []Record
+	norms      []float64
+	positions  map[string]int
+	dimensions int
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{positions: make(map[string]int)}
+}
+
+func (i *MemoryIndex) Upsert(records ...Record) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err := validateRecords(records, i.dimensions); err != nil {
+		return err
+	}
+	i.upsert(records)
+	return nil
+}
+
+func (i *MemoryIndex) ReplaceDocuments(documentIDs []string, records ...Record) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	replaced := make(map[string]bool, len(documentIDs))
+	for _, id := range documentIDs {
+		replaced[id] = true
+	}
+	dimensions := i.dimensions
+	if !slices.ContainsFunc(i.records, func(record Record) bool { return !replaced[record.DocumentID] }) {
+		dimensions = 0
+	}
+	if err := validateRecords(records, dimensions); err != nil {
+		return err
+	}
+	i.deleteDocuments(replaced)
+	i.upsert(records)
+	return nil
+}
+
+func (i *MemoryIndex) DeleteDocuments(documentIDs ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	deleted := make(map[string]bool, len(documentIDs))
+	for _, id := range documentIDs {
+		deleted[id] = true
+	}
+	i.deleteDocuments(deleted)
+}
+
+func (i *MemoryIndex) Delete(ids ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.delete(ids)
+}
+
+func validateRecords(records []Record, dimensions int) error {
+	for _, record := range records {
+		if record.ID == "" {
+			return fmt.Errorf("record id is required")
+		}
+		if len(record.Vector) == 0 {
+			return fmt.Errorf("record %s has no vector", record.ID)
+		}
+		if dimensions == 0 {
+			dimensions = len(record.Vector)
+		}
+		if len(record.Vector) != dimensions {
+			return fmt.Errorf("record %s has %d dimensions, index has %d", record.ID, len(record.Vector), dimensions)
+		}
+	}
+	return nil
+}
+
+func (i *MemoryIndex) upsert(records []Record) {
+	for _, record := range records {
+		i.dimensions = len(record.Vector)
+		if position, exists := i.positions[record.ID]; exists {
+			i.records[position] = record
+			i.norms[position] = norm(record.Vector)
+			continue
+		}
+		i.positions[record.ID] = len(i.records)
+		i.records = append(i.records, record)
+		i.norms = append(i.norms, norm(record.Vector))
+	}
+}
+
+func (i *MemoryIndex) deleteDocuments(documentIDs map[string]bool) {
+	var ids []string
+	for _, record := range i.records {
+		if documentIDs[record.DocumentID] {
+			ids = append(ids, record.ID)
+		}
+	}
+	i.delete(ids)
+}
+
+func (i *MemoryIndex) delete(ids []string) {
+	for _, id := range ids {
+		position, exists := i.positions[id]
+		if !exists {
+			continue
+		}
+		last := len(i.records) - 1
+		i.records[position], i.norms[position] = i.records[last], i.norms[last]
+		i.positions[i.records[position].ID] = position
+		i.records, i.norms = i.records[:last], i.norms[:last]
+		delete(i.positions, id)
+	}
+	if len(i.records) == 0 {
+		i.dimensions = 0
+	}
+}
+
+func (i *MemoryIndex) Get(id string) (Record, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	position, exists := i.positions[id]
+	if !exists {
+		return Record{}, false
+	}
+	return i.records[position], true
+}
+
+func (i *MemoryIndex) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.records)
+}
+
+func (i *MemoryIndex) Search(vector []float64, k int, filter Filter) []SearchResult {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	queryNorm := norm(vector)
+	var results []SearchResult
+	for position, record := range i.records {
+		if len(record.Vector) != len(vector) || !filter.Matches(record.Metadata) {
+			continue
+		}
+		score := 0.0
+		if queryNorm > 0 && i.norms[position] > 0 {
+			score = dot(vector, record.Vector) / (queryNorm * i.norms[position])
+		}
+		results = append(results, SearchResult{Record: record, Score: score})
+	}
+
+	slices.SortStableFunc(results, func(a, b SearchResult) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return 0
+		}
+	})
+	if k > 0 && len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+func (i *MemoryIndex) Save(path string) error {
+	i.mu.RLock()
+	data, err := json.Marshal(i.records)
+	i.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	return nil
+}
+
+func LoadMemoryIndex(path string) (*MemoryIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index: %w", err)
+	}
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode index %s: %w", path, err)
+	}
+	index := NewMemoryIndex()
+	if err := index.Upsert(records...); err != nil {
+		return nil, fmt.Errorf("failed to load index %s: %w", path, err)
+	}
+	return index, nil
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func norm(vector []float64) float64 {
+	return math.Sqrt(dot(vector, vector))
+}
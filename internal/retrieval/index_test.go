@@ -0,0 +1,127 @@
+package retrieval
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryIndexSearch(t *testing.T) {
+	index := NewMemoryIndex()
+	err := index.Upsert(
+		Record{ID: "x", Text: "east", Vector: []float64{1, 0}, Metadata: map[string]string{"team": "a"}},
+		Record{ID: "y", Text: "north", Vector: []float64{0, 1}, Metadata: map[string]string{"team": "b"}},
+		Record{ID: "xy", Text: "north-east", Vector: []float64{1, 1}, Metadata: map[string]string{"team": "a"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("ranks by cosine similarity", func(t *testing.T) {
+		results := index.Search([]float64{2, 0.1}, 2, nil)
+		if len(results) != 2 || results[0].ID != "x" || results[1].ID != "xy" {
+			t.Fatalf("unexpected results: %+v", results)
+		}
+		if results[0].Score <= results[1].Score || results[0].Score > 1 {
+			t.Errorf("unexpected scores: %v, %v", results[0].Score, results[1].Score)
+		}
+	})
+
+	t.Run("filters by metadata", func(t *testing.T) {
+		results := index.Search([]float64{0, 1}, 10, Filter{"team": "a"})
+		if len(results) != 2 || results[0].ID != "xy" {
+			t.Errorf("expected only team a records, got %+v", results)
+		}
+	})
+
+	t.Run("upsert replaces and delete removes", func(t *testing.T) {
+		index.Upsert(Record{ID: "y", Text: "south", Vector: []float64{0, -1}})
+		if record, _ := index.Get("y"); record.Text != "south" {
+			t.Errorf("expected record to be replaced, got %+v", record)
+		}
+		index.Delete("x", "missing")
+		if index.Len() != 2 {
+			t.Errorf("expected 2 records after delete, got %d", index.Len())
+		}
+		if _, exists := index.Get("x"); exists {
+			t.Error("expected deleted record to be gone")
+		}
+		if record, exists := index.Get("xy"); !exists || record.Text != "north-east" {
+			t.Errorf("expected moved record to stay addressable, got %+v", record)
+		}
+	})
+
+	t.Run("rejects mismatched dimensions", func(t *testing.T) {
+		if err := index.Upsert(Record{ID: "z", Vector: []float64{1, 2, 3}}); err == nil {
+			t.Error("expected error for a 3-dimensional vector in a 2-dimensional index")
+		}
+	})
+}
+
+func TestMemoryIndexValidation(t *testing.T) {
+	t.Run("a rejected batch does not fix the dimensions", func(t *testing.T) {
+		index := NewMemoryIndex()
+		err := index.Upsert(
+			Record{ID: "a", Vector: []float64{1, 2, 3}},
+			Record{ID: "b", Vector: []float64{1, 2}},
+		)
+		if err == nil {
+			t.Fatal("expected error for a batch with mixed dimensions")
+		}
+		if err := index.Upsert(Record{ID: "c", Vector: []float64{1, 2}}); err != nil {
+			t.Errorf("expected an empty index to accept any dimension, got %v", err)
+		}
+		if index.Len() != 1 {
+			t.Errorf("expected only the valid record, got %d", index.Len())
+		}
+	})
+
+	t.Run("replace documents removes stale chunks", func(t *testing.T) {
+		index := NewMemoryIndex()
+		index.Upsert(
+			Record{ID: "doc#0", DocumentID: "doc", Vector: []float64{1, 0}},
+			Record{ID: "doc#1", DocumentID: "doc", Vector: []float64{0, 1}},
+			Record{ID: "other#0", DocumentID: "other", Vector: []float64{1, 1}},
+		)
+		if err := index.ReplaceDocuments([]string{"doc"}, Record{ID: "doc#0", DocumentID: "doc", Vector: []float64{1, 0}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, exists := index.Get("doc#1"); exists || index.Len() != 2 {
+			t.Errorf("expected the stale chunk to be removed, got %d records", index.Len())
+		}
+
+		if err := index.ReplaceDocuments([]string{"other"}, Record{ID: "other#0", DocumentID: "other", Vector: []float64{1}}); err == nil {
+			t.Error("expected error for a mismatched dimension")
+		}
+		if _, exists := index.Get("other#0"); !exists {
+			t.Error("expected a rejected replacement to keep the old chunks")
+		}
+
+		index.DeleteDocuments("doc", "other")
+		if index.Len() != 0 {
+			t.Errorf("expected all documents to be deleted, got %d", index.Len())
+		}
+	})
+}
+
+func TestMemoryIndexPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	index := NewMemoryIndex()
+	index.Upsert(Record{ID: "doc#0", DocumentID: "doc", Text: "hello", Vector: []float64{0.5, 0.5}, Metadata: map[string]string{"lang": "en"}})
+
+	if err := index.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := LoadMemoryIndex(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := loaded.Search([]float64{1, 1}, 1, Filter{"lang": "en"})
+	if len(results) != 1 || results[0].ID != "doc#0" || results[0].DocumentID != "doc" {
+		t.Errorf("unexpected results after reload: %+v", results)
+	}
+
+	if _, err := LoadMemoryIndex(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for a missing index file")
+	}
+}
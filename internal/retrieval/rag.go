@@ -0,0 +1,156 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	DefaultTopK         = 4
+	DefaultChunkSize    = 1000
+	DefaultChunkOverlap = 200
+)
+
+type Citation struct {
+	Number     int
+	ChunkID    string
+	DocumentID string
+	Text       string
+	Score      float64
+	Metadata   map[string]string
+}
+
+type Retriever struct {
+	embedder            provider.Embedder
+	model               string
+	embeddingParameters map[string]any
+	index               *MemoryIndex
+	chunker             Chunker
+}
+
+func NewRetriever(embedder provider.Embedder, modelName string, index *MemoryIndex) *Retriever {
+	chunker, _ := NewCharacterChunker(DefaultChunkSize, DefaultChunkOverlap)
+	return &Retriever{embedder: embedder, model: modelName, index: index, chunker: chunker}
+}
+
+func (r *Retriever) WithChunker(chunker Chunker) *Retriever {
+	r.chunker = chunker
+	return r
+}
+
+func (r *Retriever) WithEmbeddingParameters(parameters map[string]any) *Retriever {
+	r.embeddingParameters = parameters
+	return r
+}
+
+func (r *Retriever) Index() *MemoryIndex {
+	return r.index
+}
+
+func (r *Retriever) AddDocuments(ctx context.Context, documents ...Document) (types.TokenUsage, error) {
+	documentIDs := make([]string, len(documents))
+	for i, document := range documents {
+		documentIDs[i] = document.ID
+	}
+	chunks := ChunkDocuments(r.chunker, documents...)
+	if len(chunks) == 0 {
+		r.index.DeleteDocuments(documentIDs...)
+		return types.TokenUsage{}, nil
+	}
+
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+	result, err := r.embedder.Embed(ctx, texts, r.model, r.embeddingParameters)
+	if err != nil {
+		return types.TokenUsage{}, fmt.Errorf("failed to embed documents: %w", err)
+	}
+	embeddings := result.Embeddings()
+	if len(embeddings) != len(chunks) {
+		return types.TokenUsage{}, fmt.Errorf("expected %d embeddings, got %d", len(chunks), len(embeddings))
+	}
+
+	records := make([]Record, len(chunks))
+	for _, embedding := range embeddings {
+		if embedding.Index() < 0 || embedding.Index() >= len(chunks) {
+			return types.TokenUsage{}, fmt.Errorf("embedding index %d is out of range", embedding.Index())
+		}
+		chunk := chunks[embedding.Index()]
+		records[embedding.Index()] = Record{
+			ID:         chunk.ID,
+			DocumentID: chunk.DocumentID,
+			Text:       chunk.Text,
+			Metadata:   chunk.Metadata,
+			Vector:     embedding.Vector(),
+		}
+	}
+	if err := r.index.ReplaceDocuments(documentIDs, records...); err != nil {
+		return types.TokenUsage{}, err
+	}
+	return result.Usage(), nil
+}
+
+func (r *Retriever) Retrieve(ctx context.Context, query string, k int, filter Filter) ([]SearchResult, error) {
+	result, err := r.embedder.Embed(ctx, []string{query}, r.model, r.embeddingParameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	vectors := result.Vectors()
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("expected 1 query embedding, got %d", len(vectors))
+	}
+	return r.index.Search(vectors[0], k, filter), nil
+}
+
+func GenerateText(ctx context.Context, p provider.Provider, r *Retriever, prompt string, modelName string, requestParameters map[string]any, topK int, filter Filter) (types.GenerateTextResult, []Citation, error) {
+	if topK <= 0 {
+		topK = DefaultTopK
+	}
+	results, err := r.Retrieve(ctx, prompt, topK, filter)
+	if err != nil {
+		return types.GenerateTextResult{}, nil, err
+	}
+
+	citations := Citations(results)
+	messages := []types.Message{
+		ContextMessage(citations),
+		types.NewUserMessage(prompt),
+	}
+	result, err := p.GenerateChat(ctx, messages, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, nil, err
+	}
+	return result, citations, nil
+}
+
+func Citations(results []SearchResult) []Citation {
+	citations := make([]Citation, len(results))
+	for i, result := range results {
+		citations[i] = Citation{
+			Number:     i + 1,
+			ChunkID:    result.ID,
+			DocumentID: result.DocumentID,
+			Text:       result.Text,
+			Score:      result.Score,
+			Metadata:   result.Metadata,
+		}
+	}
+	return citations
+}
+
+func ContextMessage(citations []Citation) types.Message {
+	var builder strings.Builder
+	builder.WriteString("Answer the question using only the numbered context below. Cite the sources you use as [n]. If the context does not contain the answer, say that you don't know.\n")
+	for _, citation := range citations {
+		fmt.Fprintf(&builder, "\n[%d] (source: %s)\n%s\n", citation.Number, citation.DocumentID, citation.Text)
+	}
+	if len(citations) == 0 {
+		builder.WriteString("\n(no relevant context was found)\n")
+	}
+	return types.NewSystemMessage(builder.String())
+}
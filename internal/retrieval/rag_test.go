@@ -0,0 +1,182 @@
+package retrieval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+type chatProvider struct {
+	messages []types.Message
+	err      error
+}
+
+func (p *chatProvider) Name() string {
+	return "ChatProvider"
+}
+
+func (p *chatProvider) AvailableModels() []provider.Model {
+	return nil
+}
+
+func (p *chatProvider) GetModel(modelName string) (provider.Model, error) {
+	return nil, nil
+}
+
+func (p *chatProvider) AvailableRequestParameters(modelName string) []types.Parameter {
+	return nil
+}
+
+func (p *chatProvider) Config() map[string]any {
+	return nil
+}
+
+func (p *chatProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+}
+
+func (p *chatProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p.messages = messages
+	if p.err != nil {
+		return types.GenerateTextResult{}, p.err
+	}
+	return types.NewGenerateTextResult("Paris [1]", types.NewTokenUsage(1, 1, 2)), nil
+}
+
+func (p *chatProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return p.GenerateChat(ctx, messages, modelName, requestParameters)
+}
+
+func (p *chatProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func newTestRetriever(t *testing.T) *Retriever {
+	t.Helper()
+	chunker, _ := NewWordChunker(8, 0)
+	retriever := NewRetriever(NewFakeEmbedder(64), FakeEmbeddingModel, NewMemoryIndex()).WithChunker(chunker)
+	_, err := retriever.AddDocuments(context.Background(),
+		Document{ID: "france", Text: "The capital of France is Paris.", Metadata: map[string]string{"region": "europe"}},
+		Document{ID: "japan", Text: "The capital of Japan is Tokyo.", Metadata: map[string]string{"region": "asia"}},
+		Document{ID: "bread", Text: "Sourdough bread needs a starter culture.", Metadata: map[string]string{"region": "europe"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return retriever
+}
+
+func TestFakeEmbedder(t *testing.T) {
+	embedder := NewFakeEmbedder(32)
+	result, err := embedder.Embed(context.Background(), []string{"Paris, France", "paris france", "Tokyo"}, FakeEmbeddingModel, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	vectors := result.Vectors()
+	if len(vectors) != 3 || len(vectors[0]) != 32 {
+		t.Fatalf("unexpected vectors: %v", vectors)
+	}
+	for i := range vectors[0] {
+		if vectors[0][i] != vectors[1][i] {
+			t.Fatal("expected the same words to produce the same vector")
+		}
+	}
+
+	result, err = NewFakeEmbedder(0).Embed(context.Background(), []string{"hello"}, FakeEmbeddingModel, nil)
+	if err != nil || len(result.Vectors()[0]) != DefaultFakeEmbeddingDimensions {
+		t.Errorf("expected default dimensions for a zero size, got %v, %v", result.Vectors(), err)
+	}
+}
+
+func TestRetrieverRetrieve(t *testing.T) {
+	retriever := newTestRetriever(t)
+
+	if retriever.Index().Len() != 3 {
+		t.Fatalf("expected 3 indexed chunks, got %d", retriever.Index().Len())
+	}
+
+	results, err := retriever.Retrieve(context.Background(), "What is the capital of Japan?", 1, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].DocumentID != "japan" {
+		t.Errorf("expected the Japan chunk, got %+v", results)
+	}
+
+	results, err = retriever.Retrieve(context.Background(), "What is the capital of Japan?", 1, Filter{"region": "europe"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].DocumentID != "france" {
+		t.Errorf("expected the filter to exclude Japan, got %+v", results)
+	}
+}
+
+func TestRetrieverAddDocuments(t *testing.T) {
+	retriever := newTestRetriever(t)
+	ctx := context.Background()
+
+	long := Document{ID: "france", Text: "The capital of France is Paris. It lies on the Seine and is known for the Louvre."}
+	if _, err := retriever.AddDocuments(ctx, long); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := retriever.Index().Get("france#1"); !exists {
+		t.Fatal("expected the longer document to have several chunks")
+	}
+
+	if _, err := retriever.AddDocuments(ctx, Document{ID: "france", Text: "Paris."}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := retriever.Index().Get("france#1"); exists {
+		t.Error("expected chunks beyond the new count to be removed")
+	}
+	if retriever.Index().Len() != 3 {
+		t.Errorf("expected 3 indexed chunks, got %d", retriever.Index().Len())
+	}
+
+	if _, err := retriever.AddDocuments(ctx, Document{ID: "bread"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := retriever.Index().Get("bread#0"); exists {
+		t.Error("expected an emptied document to be removed")
+	}
+}
+
+func TestGenerateText(t *testing.T) {
+	t.Run("injects context and returns citations", func(t *testing.T) {
+		retriever := newTestRetriever(t)
+		p := &chatProvider{}
+
+		result, citations, err := GenerateText(context.Background(), p, retriever, "What is the capital of France?", "gpt-4.1", nil, 2, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.TextContent() != "Paris [1]" {
+			t.Errorf("unexpected answer: %s", result.TextContent())
+		}
+		if len(citations) != 2 || citations[0].Number != 1 || citations[0].DocumentID != "france" {
+			t.Fatalf("unexpected citations: %+v", citations)
+		}
+		if len(p.messages) != 2 || p.messages[0].Role() != types.RoleSystem || p.messages[1].Content() != "What is the capital of France?" {
+			t.Fatalf("unexpected messages: %+v", p.messages)
+		}
+		if !strings.Contains(p.messages[0].Content(), "[1] (source: france)\nThe capital of France is Paris.") {
+			t.Errorf("expected context to include the cited chunk, got %q", p.messages[0].Content())
+		}
+	})
+
+	t.Run("provider error is returned", func(t *testing.T) {
+		p := &chatProvider{err: errors.New("boom")}
+
+		_, citations, err := GenerateText(context.Background(), p, newTestRetriever(t), "question", "gpt-4.1", nil, 0, nil)
+		if err == nil || citations != nil {
+			t.Errorf("expected error and no citations, got %v, %v", err, citations)
+		}
+	})
+}
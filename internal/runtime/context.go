@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"context"
+
+	"agentic-ai-framework/internal/memory"
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/tokenizer"
+	"agentic-ai-framework/internal/types"
+)
+
+type ContextMode int
+
+const (
+	ContextReject ContextMode = iota
+	ContextTrim
+)
+
+var outputTokenParameters = []string{"max_completion_tokens", "max_tokens", "max_output_tokens", "num_predict"}
+
+func EstimatePromptTokens(modelName string, messages []types.Message) int {
+	return tokenizer.CountMessages(tokenizer.ForModel(modelName), messages)
+}
+
+func FitContextWindow(ctx context.Context, p provider.Provider, messages []types.Message, modelName string, requestParameters map[string]any, mode ContextMode) ([]types.Message, int, error) {
+	model, err := provider.ResolveModel(ctx, p, modelName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counter := tokenizer.ForModel(modelName)
+	estimate := tokenizer.CountMessages(counter, messages)
+	window := contextWindow(model, requestParameters)
+	output := RequestedOutputTokens(requestParameters)
+	if window <= 0 || estimate+output <= window {
+		return messages, estimate, nil
+	}
+
+	overflow := &types.ContextOverflowError{Model: modelName, PromptTokens: estimate, OutputTokens: output, ContextWindow: window}
+	if mode != ContextTrim {
+		return nil, estimate, overflow
+	}
+
+	budget := memory.TokenBudget{
+		MaxTokens: window - output - tokenizer.ReplyPrimingTokens,
+		Count: func(message types.Message) int {
+			return tokenizer.CountMessage(counter, message)
+		},
+	}
+	trimmed, err := budget.Apply(ctx, messages)
+	if err != nil {
+		return nil, estimate, err
+	}
+	estimate = tokenizer.CountMessages(counter, trimmed)
+	if estimate+output > window || !hasConversation(trimmed) {
+		overflow.PromptTokens = estimate
+		return nil, estimate, overflow
+	}
+	return trimmed, estimate, nil
+}
+
+func GenerateChatWithinContext(ctx context.Context, p provider.Provider, messages []types.Message, modelName string, requestParameters map[string]any, mode ContextMode) (types.GenerateTextResult, error) {
+	messages, estimate, err := FitContextWindow(ctx, p, messages, modelName, requestParameters, mode)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	result, err := p.GenerateChat(ctx, messages, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	return result.WithEstimatedPromptTokens(estimate), nil
+}
+
+func checkContextWindow(ctx context.Context, p provider.Provider, messages []types.Message, modelName string, requestParameters map[string]any) (int, error) {
+	estimate := EstimatePromptTokens(modelName, messages)
+	model, err := provider.ResolveModel(ctx, p, modelName)
+	if err != nil || model == nil {
+		return estimate, nil
+	}
+	window := contextWindow(model, requestParameters)
+	output := RequestedOutputTokens(requestParameters)
+	if window > 0 && estimate+output > window {
+		return estimate, &types.ContextOverflowError{Model: modelName, PromptTokens: estimate, OutputTokens: output, ContextWindow: window}
+	}
+	return estimate, nil
+}
+
+func RequestedOutputTokens(requestParameters map[string]any) int {
+	for _, name := range outputTokenParameters {
+		if value, ok := intParameter(requestParameters, name); ok {
+			return max(value, 0)
+		}
+	}
+	return 0
+}
+
+func contextWindow(model provider.Model, requestParameters map[string]any) int {
+	if numCtx, ok := intParameter(requestParameters, "num_ctx"); ok && numCtx > 0 {
+		return numCtx
+	}
+	return model.ContextWindow()
+}
+
+func intParameter(requestParameters map[string]any, name string) (int, bool) {
+	value, ok := types.ToFloat(requestParameters[name])
+	return int(value), ok
+}
+
+func hasConversation(messages []types.Message) bool {
+	for _, message := range messages {
+		if message.Role() != types.RoleSystem {
+			return true
+		}
+	}
+	return false
+}
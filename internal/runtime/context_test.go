@@ -0,0 +1,186 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+type windowModel struct {
+	contextWindow int
+}
+
+func (m *windowModel) Name() string {
+	return "tiny-model"
+}
+
+func (m *windowModel) AvailableRequestParameters() []types.Parameter {
+	return nil
+}
+
+func (m *windowModel) SupportsVision() bool {
+	return false
+}
+
+func (m *windowModel) ContextWindow() int {
+	return m.contextWindow
+}
+
+func (m *windowModel) MaxOutputTokens() int {
+	return 0
+}
+
+type windowProvider struct {
+	mockProvider
+	model    *windowModel
+	messages []types.Message
+}
+
+func (p *windowProvider) GetModel(modelName string) (provider.Model, error) {
+	return p.model, nil
+}
+
+func (p *windowProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	p.messages = messages
+	return types.NewGenerateTextResult("ok", types.NewTokenUsage(42, 1, 43)), nil
+}
+
+func longConversation() []types.Message {
+	return []types.Message{
+		types.NewSystemMessage("You are terse."),
+		types.NewUserMessage("first question about something fairly long winded"),
+		types.NewAssistantMessage("", types.NewToolCall("call_1", "search", `{"q":"long winded"}`)),
+		types.NewToolMessage("call_1", "a long tool result that takes up a lot of room"),
+		types.NewAssistantMessage("first answer"),
+		types.NewUserMessage("second question"),
+	}
+}
+
+func TestEstimatePromptTokens(t *testing.T) {
+	short := EstimatePromptTokens("gpt-4.1", []types.Message{types.NewUserMessage("Hi")})
+	long := EstimatePromptTokens("gpt-4.1", longConversation())
+	if short <= 0 || long <= short {
+		t.Errorf("expected longer conversations to need more tokens, got %d and %d", short, long)
+	}
+}
+
+func TestFitContextWindow(t *testing.T) {
+	messages := longConversation()
+	total := EstimatePromptTokens("tiny-model", messages)
+
+	t.Run("fits", func(t *testing.T) {
+		p := &windowProvider{model: &windowModel{contextWindow: total + 10}}
+		fitted, estimate, err := FitContextWindow(context.Background(), p, messages, "tiny-model", map[string]any{"max_tokens": 10}, ContextReject)
+		if err != nil || len(fitted) != len(messages) || estimate != total {
+			t.Errorf("expected messages to fit, got %d messages, %d tokens, %v", len(fitted), estimate, err)
+		}
+	})
+
+	t.Run("unknown window", func(t *testing.T) {
+		p := &windowProvider{model: &windowModel{}}
+		if _, _, err := FitContextWindow(context.Background(), p, messages, "tiny-model", nil, ContextReject); err != nil {
+			t.Errorf("expected no check without a context window, got %v", err)
+		}
+	})
+
+	t.Run("rejects overflow", func(t *testing.T) {
+		p := &windowProvider{model: &windowModel{contextWindow: total + 10}}
+		_, _, err := FitContextWindow(context.Background(), p, messages, "tiny-model", map[string]any{"max_completion_tokens": 11}, ContextReject)
+		var overflow *types.ContextOverflowError
+		if !errors.As(err, &overflow) || !errors.Is(err, types.ErrContextOverflow) {
+			t.Fatalf("expected ContextOverflowError, got %v", err)
+		}
+		if overflow.PromptTokens != total || overflow.OutputTokens != 11 || overflow.ContextWindow != total+10 {
+			t.Errorf("unexpected overflow details: %+v", overflow)
+		}
+	})
+
+	t.Run("trims oldest turns", func(t *testing.T) {
+		window := EstimatePromptTokens("tiny-model", []types.Message{messages[0], messages[4], messages[5]})
+		p := &windowProvider{model: &windowModel{contextWindow: window}}
+		fitted, estimate, err := FitContextWindow(context.Background(), p, messages, "tiny-model", nil, ContextTrim)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(fitted) != 3 || fitted[0].Role() != types.RoleSystem || fitted[1].Content() != "first answer" {
+			t.Errorf("expected system message and the latest turn, got %+v", fitted)
+		}
+		if estimate != window || estimate != EstimatePromptTokens("tiny-model", fitted) {
+			t.Errorf("unexpected estimate %d", estimate)
+		}
+	})
+
+	t.Run("trimming cannot help", func(t *testing.T) {
+		p := &windowProvider{model: &windowModel{contextWindow: 10}}
+		if _, _, err := FitContextWindow(context.Background(), p, messages, "tiny-model", nil, ContextTrim); !errors.Is(err, types.ErrContextOverflow) {
+			t.Errorf("expected ErrContextOverflow, got %v", err)
+		}
+	})
+}
+
+func TestGenerateChatWithinContext(t *testing.T) {
+	messages := longConversation()
+	p := &windowProvider{model: &windowModel{contextWindow: 1000}}
+
+	result, err := GenerateChatWithinContext(context.Background(), p, messages, "tiny-model", nil, ContextReject)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.EstimatedPromptTokens() != EstimatePromptTokens("tiny-model", messages) || result.Usage().PromptTokens() != 42 {
+		t.Errorf("expected estimated and actual prompt tokens, got %d and %d", result.EstimatedPromptTokens(), result.Usage().PromptTokens())
+	}
+	if len(p.messages) != len(messages) {
+		t.Errorf("expected all messages to be sent, got %d", len(p.messages))
+	}
+
+	p.model.contextWindow = 10
+	if _, err := GenerateChatWithinContext(context.Background(), p, messages, "tiny-model", nil, ContextReject); !errors.Is(err, types.ErrContextOverflow) {
+		t.Errorf("expected ErrContextOverflow, got %v", err)
+	}
+}
+
+func TestRuntimeChecksContextWindow(t *testing.T) {
+	messages := longConversation()
+	p := &windowProvider{model: &windowModel{contextWindow: 1000}}
+
+	result, err := GenerateChat(context.Background(), p, messages, "tiny-model", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.EstimatedPromptTokens() != EstimatePromptTokens("tiny-model", messages) {
+		t.Errorf("expected estimated prompt tokens, got %d", result.EstimatedPromptTokens())
+	}
+
+	p.model.contextWindow = 10
+	p.messages = nil
+	if _, err := GenerateChat(context.Background(), p, messages, "tiny-model", nil); !errors.Is(err, types.ErrContextOverflow) {
+		t.Errorf("expected ErrContextOverflow from GenerateChat, got %v", err)
+	}
+	if p.messages != nil {
+		t.Error("expected the request not to be sent")
+	}
+	if _, err := GenerateText(context.Background(), p, "Hi", "tiny-model", map[string]any{"max_tokens": 10}); !errors.Is(err, types.ErrContextOverflow) {
+		t.Errorf("expected ErrContextOverflow from GenerateText, got %v", err)
+	}
+	if _, err := StreamText(context.Background(), p, "Hi", "tiny-model", map[string]any{"max_tokens": 10}); !errors.Is(err, types.ErrContextOverflow) {
+		t.Errorf("expected ErrContextOverflow from StreamText, got %v", err)
+	}
+	if _, err := GenerateChatWithTools(context.Background(), p, NewToolRegistry(), messages, "tiny-model", nil, 1); !errors.Is(err, types.ErrContextOverflow) {
+		t.Errorf("expected ErrContextOverflow from GenerateChatWithTools, got %v", err)
+	}
+
+	if _, err := GenerateChat(context.Background(), p, messages, "tiny-model", map[string]any{"num_ctx": 1000}); err != nil {
+		t.Errorf("expected num_ctx to widen the window, got %v", err)
+	}
+	p.model.contextWindow = 1000
+	var overflow *types.ContextOverflowError
+	if _, err := GenerateChat(context.Background(), p, messages, "tiny-model", map[string]any{"num_ctx": 10}); !errors.As(err, &overflow) || overflow.ContextWindow != 10 {
+		t.Errorf("expected num_ctx to narrow the window, got %v", err)
+	}
+	if _, err := GenerateChat(context.Background(), p, messages, "tiny-model", map[string]any{"num_ctx": int32(10)}); !errors.As(err, &overflow) || overflow.ContextWindow != 10 {
+		t.Errorf("expected an int32 num_ctx to narrow the window, got %v", err)
+	}
+}
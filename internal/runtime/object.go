@@ -0,0 +1,117 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/schema"
+	"agentic-ai-framework/internal/types"
+)
+
+const DefaultMaxObjectAttempts = 3
+
+var ErrInvalidObject = errors.New("model output does not match schema")
+
+var schemaNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func GenerateObject[T any](ctx context.Context, p provider.Provider, prompt string, modelName string, requestParameters map[string]any, maxAttempts int) (T, types.GenerateTextResult, error) {
+	return GenerateChatObject[T](ctx, p, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters, maxAttempts)
+}
+
+func GenerateChatObject[T any](ctx context.Context, p provider.Provider, messages []types.Message, modelName string, requestParameters map[string]any, maxAttempts int) (T, types.GenerateTextResult, error) {
+	var object T
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxObjectAttempts
+	}
+
+	objectSchema := schema.For[T]()
+	messages = append([]types.Message(nil), messages...)
+	requestParameters, native := objectRequestParameters(p, modelName, requestParameters, schemaName[T](), objectSchema)
+	if !native {
+		messages = append([]types.Message{types.NewSystemMessage(objectInstructions(objectSchema))}, messages...)
+	}
+
+	var usage types.TokenUsage
+	var lastErr error
+	for range maxAttempts {
+		response, err := p.GenerateChat(ctx, messages, modelName, requestParameters)
+		if err != nil {
+			return object, types.GenerateTextResult{}.WithUsage(usage), err
+		}
+		usage = usage.Add(response.Usage())
+
+		text := extractJSON(response.TextContent())
+		if lastErr = decodeObject(objectSchema, text, &object); lastErr == nil {
+			return object, response.WithUsage(usage), nil
+		}
+
+		messages = append(messages,
+			types.NewAssistantMessage(response.TextContent()),
+			types.NewUserMessage(fmt.Sprintf("Your reply did not match the required JSON schema: %v. Reply again with only the corrected JSON.", lastErr)),
+		)
+	}
+
+	return object, types.GenerateTextResult{}.WithUsage(usage), fmt.Errorf("%w after %d attempts: %w", ErrInvalidObject, maxAttempts, lastErr)
+}
+
+func objectRequestParameters(p provider.Provider, modelName string, requestParameters map[string]any, name string, objectSchema map[string]any) (map[string]any, bool) {
+	if objectSchema["type"] != "object" {
+		return requestParameters, false
+	}
+
+	available := types.ParameterNames(p.AvailableRequestParameters(modelName))
+	for _, key := range []string{"response_format", "format"} {
+		if !slices.Contains(available, key) {
+			continue
+		}
+		if _, set := requestParameters[key]; set {
+			return requestParameters, true
+		}
+		params := make(map[string]any, len(requestParameters)+1)
+		for k, v := range requestParameters {
+			params[k] = v
+		}
+		params[key] = types.NewJSONSchemaFormat(name, objectSchema, schema.IsStrict(objectSchema))
+		return params, true
+	}
+	return requestParameters, false
+}
+
+func objectInstructions(objectSchema map[string]any) string {
+	encoded, _ := json.MarshalIndent(objectSchema, "", "  ")
+	return "Respond only with a JSON value that conforms to this JSON Schema, without any surrounding text:\n" + string(encoded)
+}
+
+func decodeObject(objectSchema map[string]any, text string, object any) error {
+	if err := schema.Validate(objectSchema, []byte(text)); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(text), object)
+}
+
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(text, "```")
+	if newline := strings.IndexByte(text, '\n'); newline >= 0 {
+		text = text[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
+
+func schemaName[T any]() string {
+	name := schemaNamePattern.ReplaceAllString(reflect.TypeFor[T]().Name(), "_")
+	if name == "" {
+		return "response"
+	}
+	return name
+}
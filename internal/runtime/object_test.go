@@ -0,0 +1,151 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"agentic-ai-framework/internal/schema"
+	"agentic-ai-framework/internal/types"
+)
+
+type objectProvider struct {
+	mockProvider
+	parameters []types.Parameter
+	replies    []string
+	requests   [][]types.Message
+	params     []map[string]any
+}
+
+func (m *objectProvider) AvailableRequestParameters(modelName string) []types.Parameter {
+	return m.parameters
+}
+
+func (m *objectProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	m.requests = append(m.requests, messages)
+	m.params = append(m.params, requestParameters)
+	if len(m.replies) == 0 {
+		return types.GenerateTextResult{}, errors.New("no scripted replies left")
+	}
+	reply := m.replies[0]
+	m.replies = m.replies[1:]
+	return types.NewGenerateTextResult(reply, types.NewTokenUsage(1, 1, 2)), nil
+}
+
+type invoice struct {
+	Number string  `json:"number" description:"Invoice number"`
+	Total  float64 `json:"total"`
+	Status string  `json:"status" enum:"paid,unpaid"`
+}
+
+func TestGenerateObject(t *testing.T) {
+	t.Run("uses response_format when supported", func(t *testing.T) {
+		p := &objectProvider{
+			parameters: []types.Parameter{types.NewParameter("temperature", types.ParameterNumber), types.NewParameter("response_format", types.ParameterAny)},
+			replies:    []string{`{"number":"INV-1","total":12.5,"status":"paid"}`},
+		}
+
+		object, result, err := GenerateObject[invoice](context.Background(), p, "Extract the invoice", "gpt-4.1", map[string]any{"temperature": 0.0}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if object.Number != "INV-1" || object.Total != 12.5 || object.Status != "paid" {
+			t.Errorf("unexpected object: %+v", object)
+		}
+		if result.Usage().TotalTokens() != 2 {
+			t.Errorf("expected 2 total tokens, got %d", result.Usage().TotalTokens())
+		}
+
+		format, ok := p.params[0]["response_format"].(types.ResponseFormat)
+		if !ok {
+			t.Fatalf("expected response_format parameter, got %v", p.params[0])
+		}
+		if format.Type() != types.ResponseFormatJSONSchema || format.Name() != "invoice" || !format.Strict() {
+			t.Errorf("unexpected response format: %+v", format)
+		}
+		if p.params[0]["temperature"] != 0.0 {
+			t.Errorf("expected caller parameters to be preserved, got %v", p.params[0])
+		}
+		if len(p.requests[0]) != 1 {
+			t.Errorf("expected no schema instructions when response_format is supported, got %d messages", len(p.requests[0]))
+		}
+	})
+
+	t.Run("falls back to schema instructions", func(t *testing.T) {
+		p := &objectProvider{
+			replies: []string{"```json\n{\"number\":\"INV-2\",\"total\":3,\"status\":\"unpaid\"}\n```"},
+		}
+
+		object, _, err := GenerateObject[invoice](context.Background(), p, "Extract the invoice", "claude-sonnet-4-5", nil, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if object.Number != "INV-2" {
+			t.Errorf("unexpected object: %+v", object)
+		}
+
+		first := p.requests[0][0]
+		if first.Role() != types.RoleSystem || !strings.Contains(first.Content(), `"status"`) {
+			t.Errorf("expected system message with schema, got %s: %s", first.Role(), first.Content())
+		}
+	})
+
+	t.Run("retries with validation error fed back", func(t *testing.T) {
+		p := &objectProvider{
+			parameters: []types.Parameter{types.NewParameter("response_format", types.ParameterAny)},
+			replies: []string{
+				`{"number":"INV-3","total":"ten","status":"paid"}`,
+				`{"number":"INV-3","total":10,"status":"paid"}`,
+			},
+		}
+
+		object, result, err := GenerateObject[invoice](context.Background(), p, "Extract the invoice", "gpt-4.1", nil, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if object.Total != 10 {
+			t.Errorf("unexpected object: %+v", object)
+		}
+		if result.Usage().TotalTokens() != 4 {
+			t.Errorf("expected usage across attempts to be aggregated, got %d", result.Usage().TotalTokens())
+		}
+
+		retry := p.requests[1]
+		feedback := retry[len(retry)-1]
+		if feedback.Role() != types.RoleUser || !strings.Contains(feedback.Content(), "$.total: expected number, got string") {
+			t.Errorf("expected validation error to be fed back, got %q", feedback.Content())
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		p := &objectProvider{
+			parameters: []types.Parameter{types.NewParameter("response_format", types.ParameterAny)},
+			replies:    []string{`not json`, `{"number":"INV-4"}`},
+		}
+
+		_, result, err := GenerateObject[invoice](context.Background(), p, "Extract the invoice", "gpt-4.1", nil, 2)
+		if !errors.Is(err, ErrInvalidObject) {
+			t.Fatalf("expected ErrInvalidObject, got %v", err)
+		}
+		var validationErr *schema.ValidationError
+		if !errors.As(err, &validationErr) || !strings.Contains(validationErr.Message, "total") {
+			t.Errorf("expected ValidationError for missing total, got %v", err)
+		}
+		if len(p.requests) != 2 {
+			t.Errorf("expected 2 requests, got %d", len(p.requests))
+		}
+		if result.Usage().TotalTokens() != 4 {
+			t.Errorf("expected usage to be reported on failure, got %d", result.Usage().TotalTokens())
+		}
+	})
+
+	t.Run("provider error is returned", func(t *testing.T) {
+		p := &objectProvider{}
+
+		_, _, err := GenerateObject[invoice](context.Background(), p, "Extract the invoice", "gpt-4.1", nil, 0)
+		if err == nil || errors.Is(err, ErrInvalidObject) {
+			t.Fatalf("expected provider error, got %v", err)
+		}
+	})
+}
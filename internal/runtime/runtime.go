@@ -1,15 +1,43 @@
 package runtime
 
 import (
+	"context"
+
 	"agentic-ai-framework/internal/provider"
 	"agentic-ai-framework/internal/types"
 )
 
-func GenerateText(p provider.Provider, prompt string, modelName string, requestParameters map[string]any) types.GenerateTextResult {
-	response, err := p.GenerateText(prompt, modelName, requestParameters)
+func GenerateText(ctx context.Context, p provider.Provider, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	estimate, err := checkContextWindow(ctx, p, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	result, err := p.GenerateText(ctx, prompt, modelName, requestParameters)
 	if err != nil {
-		panic(err)
+		return result, err
 	}
-	return response
+	return result.WithEstimatedPromptTokens(estimate), nil
 }
 
+func GenerateChat(ctx context.Context, p provider.Provider, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	estimate, err := checkContextWindow(ctx, p, messages, modelName, requestParameters)
+	if err != nil {
+		return types.GenerateTextResult{}, err
+	}
+	result, err := p.GenerateChat(ctx, messages, modelName, requestParameters)
+	if err != nil {
+		return result, err
+	}
+	return result.WithEstimatedPromptTokens(estimate), nil
+}
+
+func Embed(ctx context.Context, e provider.Embedder, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	return e.Embed(ctx, inputs, modelName, requestParameters)
+}
+
+func StreamText(ctx context.Context, p provider.Provider, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	if _, err := checkContextWindow(ctx, p, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters); err != nil {
+		return nil, err
+	}
+	return p.StreamText(ctx, prompt, modelName, requestParameters)
+}
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -24,7 +25,7 @@ func (m *mockProvider) GetModel(modelName string) (provider.Model, error) {
 	return nil, nil
 }
 
-func (m *mockProvider) AvailableRequestParameters(modelName string) []string {
+func (m *mockProvider) AvailableRequestParameters(modelName string) []types.Parameter {
 	return nil
 }
 
@@ -32,24 +33,41 @@ func (m *mockProvider) Config() map[string]any {
 	return nil
 }
 
-func (m *mockProvider) GenerateText(prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+func (m *mockProvider) GenerateText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
 	if m.shouldError {
 		return types.GenerateTextResult{}, errors.New("mock provider error")
 	}
 	return types.NewGenerateTextResult("Mock response", types.NewTokenUsage(10, 20, 30)), nil
 }
 
+func (m *mockProvider) GenerateChat(ctx context.Context, messages []types.Message, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return m.GenerateText(ctx, "", modelName, requestParameters)
+}
+
+func (m *mockProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	return m.GenerateText(ctx, "", modelName, requestParameters)
+}
+
+func (m *mockProvider) StreamText(ctx context.Context, prompt string, modelName string, requestParameters map[string]any) (<-chan types.StreamChunk, error) {
+	if m.shouldError {
+		return nil, errors.New("mock provider error")
+	}
+	chunks := make(chan types.StreamChunk, 3)
+	chunks <- types.NewStreamDelta("Mock ")
+	chunks <- types.NewStreamDelta("response")
+	chunks <- types.NewStreamResult(types.NewGenerateTextResult("Mock response", types.NewTokenUsage(10, 20, 30)))
+	close(chunks)
+	return chunks, nil
+}
+
 func TestGenerateText(t *testing.T) {
 	t.Run("successful generation", func(t *testing.T) {
 		provider := &mockProvider{shouldError: false}
 
-		defer func() {
-			if r := recover(); r != nil {
-				t.Fatalf("unexpected panic: %v", r)
-			}
-		}()
-
-		result := GenerateText(provider, "test prompt", "gpt-4", map[string]any{})
+		result, err := GenerateText(context.Background(), provider, "test prompt", "gpt-4", map[string]any{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
 		if result.TextContent() != "Mock response" {
 			t.Errorf("expected 'Mock response', got '%s'", result.TextContent())
@@ -68,23 +86,107 @@ func TestGenerateText(t *testing.T) {
 		}
 	})
 
-	t.Run("provider error causes panic", func(t *testing.T) {
+	t.Run("provider error is returned", func(t *testing.T) {
 		provider := &mockProvider{shouldError: true}
 
-		defer func() {
-			r := recover()
-			if r == nil {
-				t.Fatal("expected panic when provider returns error")
-			}
-			err, ok := r.(error)
-			if !ok {
-				t.Errorf("expected panic to be error, got %T", r)
-			}
-			if err.Error() != "mock provider error" {
-				t.Errorf("expected 'mock provider error', got '%s'", err.Error())
+		_, err := GenerateText(context.Background(), provider, "test prompt", "gpt-4", map[string]any{})
+		if err == nil {
+			t.Fatal("expected error when provider returns error")
+		}
+		if err.Error() != "mock provider error" {
+			t.Errorf("expected 'mock provider error', got '%s'", err.Error())
+		}
+	})
+}
+
+func TestGenerateChat(t *testing.T) {
+	t.Run("successful generation", func(t *testing.T) {
+		provider := &mockProvider{shouldError: false}
+
+		result, err := GenerateChat(context.Background(), provider, []types.Message{
+			types.NewSystemMessage("You are terse"),
+			types.NewUserMessage("Hi"),
+		}, "gpt-4", map[string]any{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.TextContent() != "Mock response" {
+			t.Errorf("expected 'Mock response', got '%s'", result.TextContent())
+		}
+	})
+
+	t.Run("provider error is returned", func(t *testing.T) {
+		provider := &mockProvider{shouldError: true}
+
+		_, err := GenerateChat(context.Background(), provider, []types.Message{types.NewUserMessage("Hi")}, "gpt-4", map[string]any{})
+		if err == nil {
+			t.Fatal("expected error when provider returns error")
+		}
+	})
+}
+
+func TestStreamText(t *testing.T) {
+	t.Run("successful stream", func(t *testing.T) {
+		provider := &mockProvider{shouldError: false}
+
+		chunks, err := StreamText(context.Background(), provider, "test prompt", "gpt-4", map[string]any{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var text string
+		var final types.GenerateTextResult
+		for chunk := range chunks {
+			text += chunk.TextDelta()
+			if chunk.Done() {
+				final = chunk.Result()
 			}
-		}()
+		}
 
-		GenerateText(provider, "test prompt", "gpt-4", map[string]any{})
+		if text != "Mock response" {
+			t.Errorf("expected streamed text 'Mock response', got '%s'", text)
+		}
+		if final.Usage().TotalTokens() != 30 {
+			t.Errorf("expected 30 total tokens, got %d", final.Usage().TotalTokens())
+		}
 	})
+
+	t.Run("provider error is returned", func(t *testing.T) {
+		provider := &mockProvider{shouldError: true}
+
+		_, err := StreamText(context.Background(), provider, "test prompt", "gpt-4", map[string]any{})
+		if err == nil {
+			t.Fatal("expected error when provider returns error")
+		}
+	})
+}
+
+type mockEmbedder struct {
+	inputs []string
+}
+
+func (m *mockEmbedder) AvailableEmbeddingModels() []provider.Model {
+	return nil
+}
+
+func (m *mockEmbedder) Embed(ctx context.Context, inputs []string, modelName string, requestParameters map[string]any) (types.EmbeddingResult, error) {
+	m.inputs = inputs
+	embeddings := make([]types.Embedding, len(inputs))
+	for i := range inputs {
+		embeddings[i] = types.NewEmbedding(i, []float64{float64(i)})
+	}
+	return types.NewEmbeddingResult(embeddings, types.NewTokenUsage(len(inputs), 0, len(inputs))), nil
+}
+
+func TestEmbed(t *testing.T) {
+	embedder := &mockEmbedder{}
+
+	result, err := Embed(context.Background(), embedder, []string{"a", "b"}, "text-embedding-3-small", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(embedder.inputs) != 2 || len(result.Vectors()) != 2 {
+		t.Errorf("expected 2 inputs to be embedded, got %v", result.Vectors())
+	}
 }
@@ -0,0 +1,171 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"agentic-ai-framework/internal/provider"
+	"agentic-ai-framework/internal/types"
+)
+
+const DefaultMaxToolIterations = 10
+
+var ErrMaxToolIterations = errors.New("tool iteration limit reached")
+
+type ToolLoopStepKind string
+
+const (
+	ToolLoopModelCall   ToolLoopStepKind = "model_call"
+	ToolLoopToolCall    ToolLoopStepKind = "tool_call"
+	ToolLoopObservation ToolLoopStepKind = "observation"
+)
+
+type ToolLoopStep struct {
+	Kind     ToolLoopStepKind
+	Response types.GenerateTextResult
+	ToolCall types.ToolCall
+	Output   string
+	Err      error
+	Duration time.Duration
+}
+
+type ToolFunc func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+	Function    ToolFunc
+}
+
+func NewTypedTool[T any](name, description string, parameters map[string]any, fn func(ctx context.Context, arguments T) (string, error)) Tool {
+	return Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+		Function: func(ctx context.Context, arguments json.RawMessage) (string, error) {
+			var typed T
+			if len(arguments) > 0 {
+				if err := json.Unmarshal(arguments, &typed); err != nil {
+					return "", fmt.Errorf("invalid arguments for tool %s: %v", name, err)
+				}
+			}
+			return fn(ctx, typed)
+		},
+	}
+}
+
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+	order []string
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]Tool)}
+}
+
+func (r *ToolRegistry) Register(tool Tool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if tool.Function == nil {
+		return fmt.Errorf("tool %s has no function", tool.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[tool.Name]; exists {
+		return fmt.Errorf("tool %s is already registered", tool.Name)
+	}
+	if tool.Parameters == nil {
+		tool.Parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	r.tools[tool.Name] = tool
+	r.order = append(r.order, tool.Name)
+	return nil
+}
+
+func (r *ToolRegistry) Definitions() []types.ToolDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]types.ToolDefinition, len(r.order))
+	for i, name := range r.order {
+		tool := r.tools[name]
+		definitions[i] = types.NewToolDefinition(tool.Name, tool.Description, tool.Parameters)
+	}
+	return definitions
+}
+
+func (r *ToolRegistry) Execute(ctx context.Context, call types.ToolCall) (string, error) {
+	r.mu.RLock()
+	tool, exists := r.tools[call.Name()]
+	r.mu.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("tool %s is not registered", call.Name())
+	}
+	return tool.Function(ctx, json.RawMessage(call.Arguments()))
+}
+
+func GenerateTextWithTools(ctx context.Context, p provider.Provider, registry *ToolRegistry, prompt string, modelName string, requestParameters map[string]any, maxIterations int) (types.GenerateTextResult, error) {
+	return GenerateChatWithTools(ctx, p, registry, []types.Message{types.NewUserMessage(prompt)}, modelName, requestParameters, maxIterations)
+}
+
+func GenerateChatWithTools(ctx context.Context, p provider.Provider, registry *ToolRegistry, messages []types.Message, modelName string, requestParameters map[string]any, maxIterations int) (types.GenerateTextResult, error) {
+	_, result, err := RunToolLoop(ctx, p, registry, messages, modelName, requestParameters, maxIterations, nil)
+	return result, err
+}
+
+func RunToolLoop(ctx context.Context, p provider.Provider, registry *ToolRegistry, messages []types.Message, modelName string, requestParameters map[string]any, maxIterations int, onStep func(ToolLoopStep)) ([]types.Message, types.GenerateTextResult, error) {
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxToolIterations
+	}
+	if onStep == nil {
+		onStep = func(ToolLoopStep) {}
+	}
+
+	messages = append([]types.Message(nil), messages...)
+	definitions := registry.Definitions()
+	var usage types.TokenUsage
+	var cost float64
+
+	for i := 0; i < maxIterations; i++ {
+		if _, err := checkContextWindow(ctx, p, messages, modelName, requestParameters); err != nil {
+			return messages, types.GenerateTextResult{}.WithUsage(usage).WithCost(cost), err
+		}
+		started := time.Now()
+		response, err := p.GenerateWithTools(ctx, messages, definitions, modelName, requestParameters)
+		onStep(ToolLoopStep{Kind: ToolLoopModelCall, Response: response, Err: err, Duration: time.Since(started)})
+		if err != nil {
+			return messages, types.GenerateTextResult{}.WithUsage(usage).WithCost(cost), err
+		}
+		usage = usage.Add(response.Usage())
+		cost += response.Cost()
+
+		toolCalls := response.ToolCalls()
+		messages = append(messages, types.NewAssistantMessage(response.TextContent(), toolCalls...))
+		if len(toolCalls) == 0 {
+			return messages, response.WithUsage(usage).WithCost(cost), nil
+		}
+
+		for _, call := range toolCalls {
+			onStep(ToolLoopStep{Kind: ToolLoopToolCall, ToolCall: call})
+			started := time.Now()
+			output, err := registry.Execute(ctx, call)
+			if err != nil {
+				output = "error: " + err.Error()
+			}
+			onStep(ToolLoopStep{Kind: ToolLoopObservation, ToolCall: call, Output: output, Err: err, Duration: time.Since(started)})
+			messages = append(messages, types.NewToolMessage(call.ID(), output))
+		}
+	}
+
+	return messages, types.GenerateTextResult{}.WithUsage(usage).WithCost(cost), fmt.Errorf("%w: model did not return a final answer within %d tool iterations", ErrMaxToolIterations, maxIterations)
+}
@@ -0,0 +1,204 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type toolCallingProvider struct {
+	mockProvider
+	responses []types.GenerateTextResult
+	requests  [][]types.Message
+	tools     []types.ToolDefinition
+}
+
+func (m *toolCallingProvider) GenerateWithTools(ctx context.Context, messages []types.Message, tools []types.ToolDefinition, modelName string, requestParameters map[string]any) (types.GenerateTextResult, error) {
+	m.requests = append(m.requests, messages)
+	m.tools = tools
+	if len(m.responses) == 0 {
+		return types.GenerateTextResult{}, errors.New("no scripted responses left")
+	}
+	response := m.responses[0]
+	m.responses = m.responses[1:]
+	return response, nil
+}
+
+func weatherTool() Tool {
+	return NewTypedTool("get_weather", "Get the weather for a city", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city": map[string]any{"type": "string"},
+		},
+		"required": []string{"city"},
+	}, func(ctx context.Context, arguments struct {
+		City string `json:"city"`
+	}) (string, error) {
+		if arguments.City == "" {
+			return "", errors.New("city is required")
+		}
+		return fmt.Sprintf("sunny in %s", arguments.City), nil
+	})
+}
+
+func TestToolRegistry(t *testing.T) {
+	t.Run("register and execute", func(t *testing.T) {
+		registry := NewToolRegistry()
+		if err := registry.Register(weatherTool()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		definitions := registry.Definitions()
+		if len(definitions) != 1 || definitions[0].Name() != "get_weather" {
+			t.Fatalf("unexpected definitions: %v", definitions)
+		}
+
+		output, err := registry.Execute(context.Background(), types.NewToolCall("call_1", "get_weather", `{"city":"Paris"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output != "sunny in Paris" {
+			t.Errorf("expected 'sunny in Paris', got '%s'", output)
+		}
+	})
+
+	t.Run("duplicate registration", func(t *testing.T) {
+		registry := NewToolRegistry()
+		registry.Register(weatherTool())
+		if err := registry.Register(weatherTool()); err == nil {
+			t.Fatal("expected error for duplicate tool")
+		}
+	})
+
+	t.Run("unknown tool", func(t *testing.T) {
+		registry := NewToolRegistry()
+		_, err := registry.Execute(context.Background(), types.NewToolCall("call_1", "missing", "{}"))
+		if err == nil {
+			t.Fatal("expected error for unknown tool")
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		registry := NewToolRegistry()
+		registry.Register(weatherTool())
+		_, err := registry.Execute(context.Background(), types.NewToolCall("call_1", "get_weather", `not json`))
+		if err == nil || !strings.Contains(err.Error(), "invalid arguments") {
+			t.Errorf("expected invalid arguments error, got %v", err)
+		}
+	})
+}
+
+func TestGenerateTextWithTools(t *testing.T) {
+	t.Run("executes tool calls until final answer", func(t *testing.T) {
+		registry := NewToolRegistry()
+		registry.Register(weatherTool())
+
+		p := &toolCallingProvider{responses: []types.GenerateTextResult{
+			types.NewGenerateTextResult("", types.NewTokenUsage(10, 5, 15)).
+				WithToolCalls([]types.ToolCall{types.NewToolCall("call_1", "get_weather", `{"city":"Paris"}`)}),
+			types.NewGenerateTextResult("It is sunny in Paris.", types.NewTokenUsage(20, 5, 25)),
+		}}
+
+		result, err := GenerateTextWithTools(context.Background(), p, registry, "Weather in Paris?", "gpt-4", nil, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.TextContent() != "It is sunny in Paris." {
+			t.Errorf("unexpected final answer: '%s'", result.TextContent())
+		}
+		if result.Usage().TotalTokens() != 40 {
+			t.Errorf("expected aggregated 40 total tokens, got %d", result.Usage().TotalTokens())
+		}
+		if len(p.tools) != 1 {
+			t.Errorf("expected tool definitions to be sent, got %v", p.tools)
+		}
+
+		second := p.requests[1]
+		if len(second) != 3 {
+			t.Fatalf("expected 3 messages in second request, got %d", len(second))
+		}
+		if second[1].Role() != "assistant" || len(second[1].ToolCalls()) != 1 {
+			t.Errorf("expected assistant tool call message, got %+v", second[1])
+		}
+		if second[2].Role() != "tool" || second[2].ToolCallID() != "call_1" || second[2].Content() != "sunny in Paris" {
+			t.Errorf("unexpected tool result message: %+v", second[2])
+		}
+	})
+
+	t.Run("feeds tool errors back to the model", func(t *testing.T) {
+		registry := NewToolRegistry()
+		registry.Register(weatherTool())
+
+		p := &toolCallingProvider{responses: []types.GenerateTextResult{
+			types.NewGenerateTextResult("", types.NewTokenUsage(1, 1, 2)).
+				WithToolCalls([]types.ToolCall{types.NewToolCall("call_1", "get_weather", `{}`)}),
+			types.NewGenerateTextResult("Which city?", types.NewTokenUsage(1, 1, 2)),
+		}}
+
+		if _, err := GenerateTextWithTools(context.Background(), p, registry, "Weather?", "gpt-4", nil, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		toolMessage := p.requests[1][2]
+		if toolMessage.Content() != "error: city is required" {
+			t.Errorf("expected tool error to be fed back, got '%s'", toolMessage.Content())
+		}
+	})
+
+	t.Run("iteration cap returns error", func(t *testing.T) {
+		registry := NewToolRegistry()
+		registry.Register(weatherTool())
+
+		call := types.NewGenerateTextResult("", types.NewTokenUsage(1, 1, 2)).
+			WithToolCalls([]types.ToolCall{types.NewToolCall("call_1", "get_weather", `{"city":"Paris"}`)})
+		p := &toolCallingProvider{responses: []types.GenerateTextResult{call, call, call}}
+
+		result, err := GenerateTextWithTools(context.Background(), p, registry, "Weather?", "gpt-4", nil, 2)
+		if !errors.Is(err, ErrMaxToolIterations) {
+			t.Fatalf("expected ErrMaxToolIterations, got %v", err)
+		}
+		if result.Usage().TotalTokens() != 4 {
+			t.Errorf("expected usage of both iterations to be reported, got %d", result.Usage().TotalTokens())
+		}
+	})
+}
+
+func TestRunToolLoop(t *testing.T) {
+	registry := NewToolRegistry()
+	registry.Register(weatherTool())
+
+	p := &toolCallingProvider{responses: []types.GenerateTextResult{
+		types.NewGenerateTextResult("", types.NewTokenUsage(1, 1, 2)).
+			WithCost(0.5).
+			WithToolCalls([]types.ToolCall{types.NewToolCall("call_1", "get_weather", `{"city":"Paris"}`)}),
+		types.NewGenerateTextResult("It is sunny.", types.NewTokenUsage(2, 1, 3)).WithCost(0.25),
+	}}
+
+	var kinds []ToolLoopStepKind
+	transcript, result, err := RunToolLoop(context.Background(), p, registry, []types.Message{types.NewUserMessage("Weather?")}, "gpt-4", nil, 0, func(step ToolLoopStep) {
+		kinds = append(kinds, step.Kind)
+		if step.Kind == ToolLoopObservation && step.Output != "sunny in Paris" {
+			t.Errorf("unexpected observation: %q", step.Output)
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []ToolLoopStepKind{ToolLoopModelCall, ToolLoopToolCall, ToolLoopObservation, ToolLoopModelCall}
+	if !slices.Equal(kinds, expected) {
+		t.Errorf("expected steps %v, got %v", expected, kinds)
+	}
+	if len(transcript) != 4 || transcript[3].Role() != "assistant" || transcript[3].Content() != "It is sunny." {
+		t.Errorf("expected the transcript to end with the final answer, got %+v", transcript)
+	}
+	if result.Usage().TotalTokens() != 5 || result.Cost() != 0.75 {
+		t.Errorf("expected usage and cost of both calls, got %d tokens and %v", result.Usage().TotalTokens(), result.Cost())
+	}
+}
@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+func For[T any]() map[string]any {
+	return Generate(reflect.TypeFor[T]())
+}
+
+func Generate(t reflect.Type) map[string]any {
+	return generate(t, map[reflect.Type]bool{})
+}
+
+func IsStrict(schema map[string]any) bool {
+	schemaType, _ := Type(schema)
+	switch schemaType {
+	case "object":
+		properties, _ := schema["properties"].(map[string]any)
+		if schema["additionalProperties"] != false || len(Required(schema)) != len(properties) {
+			return false
+		}
+		for _, property := range properties {
+			property, ok := property.(map[string]any)
+			if !ok || !IsStrict(property) {
+				return false
+			}
+		}
+		return true
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		return IsStrict(items)
+	case "":
+		return false
+	default:
+		return true
+	}
+}
+
+func Type(schema map[string]any) (string, bool) {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		return schemaType, false
+	case []any:
+		name, nullable := "", false
+		for _, value := range schemaType {
+			if value == "null" {
+				nullable = true
+			} else if value, ok := value.(string); ok {
+				name = value
+			}
+		}
+		return name, nullable
+	}
+	return "", false
+}
+
+func Required(schema map[string]any) []string {
+	switch required := schema["required"].(type) {
+	case []string:
+		return required
+	case []any:
+		names := make([]string, 0, len(required))
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+func generate(t reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{"type": "array", "items": generate(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": generate(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return map[string]any{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		properties := map[string]any{}
+		required := []string{}
+		addStructFields(t, properties, &required, visiting)
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]any{}
+	}
+}
+
+func addStructFields(t reflect.Type, properties map[string]any, required *[]string, visiting map[reflect.Type]bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			addStructFields(fieldType, properties, required, visiting)
+			continue
+		}
+
+		property := generate(field.Type, visiting)
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			property["enum"] = enumValues(enum, property["type"])
+		}
+		if field.Type.Kind() == reflect.Pointer {
+			nullable(property)
+		}
+
+		properties[name] = property
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func nullable(property map[string]any) {
+	schemaType, ok := property["type"].(string)
+	if !ok {
+		return
+	}
+	property["type"] = []any{schemaType, "null"}
+	if enum, ok := property["enum"].([]any); ok {
+		property["enum"] = append(enum, nil)
+	}
+}
+
+func enumValues(enum string, schemaType any) []any {
+	values := []any{}
+	for _, value := range strings.Split(enum, ",") {
+		value = strings.TrimSpace(value)
+		if schemaType == "integer" || schemaType == "number" {
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				values = append(values, number)
+				continue
+			}
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool, bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false, true
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	omitEmpty := false
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" || option == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City    string `json:"city"`
+	Country string `json:"country,omitempty"`
+}
+
+type Base struct {
+	ID string `json:"id"`
+}
+
+type person struct {
+	Base
+	Name     string            `json:"name" description:"Full name"`
+	Age      int               `json:"age"`
+	Score    float64           `json:"score"`
+	Active   bool              `json:"active"`
+	Tags     []string          `json:"tags"`
+	Address  *address          `json:"address"`
+	Role     string            `json:"role" enum:"admin, member"`
+	Level    int               `json:"level" enum:"1,2,3"`
+	Born     time.Time         `json:"born"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Internal string            `json:"-"`
+	private  string
+}
+
+type node struct {
+	Value    string `json:"value"`
+	Children []node `json:"children"`
+}
+
+func TestFor(t *testing.T) {
+	t.Run("struct fields", func(t *testing.T) {
+		schema := For[person]()
+		if schema["type"] != "object" || schema["additionalProperties"] != false {
+			t.Fatalf("unexpected root schema: %v", schema)
+		}
+
+		properties := schema["properties"].(map[string]any)
+		expected := map[string]string{
+			"id":      "string",
+			"name":    "string",
+			"age":     "integer",
+			"score":   "number",
+			"active":  "boolean",
+			"tags":    "array",
+			"address": "object",
+			"role":    "string",
+			"level":   "integer",
+			"born":    "string",
+			"labels":  "object",
+		}
+		if len(properties) != len(expected) {
+			t.Errorf("expected %d properties, got %d: %v", len(expected), len(properties), properties)
+		}
+		for name, schemaType := range expected {
+			property, ok := properties[name].(map[string]any)
+			if actual, _ := Type(property); !ok || actual != schemaType {
+				t.Errorf("expected %s to be %s, got %v", name, schemaType, properties[name])
+			}
+		}
+
+		name := properties["name"].(map[string]any)
+		if name["description"] != "Full name" {
+			t.Errorf("expected description, got %v", name)
+		}
+		role := properties["role"].(map[string]any)
+		if !reflect.DeepEqual(role["enum"], []any{"admin", "member"}) {
+			t.Errorf("unexpected role enum: %v", role["enum"])
+		}
+		level := properties["level"].(map[string]any)
+		if !reflect.DeepEqual(level["enum"], []any{1.0, 2.0, 3.0}) {
+			t.Errorf("unexpected level enum: %v", level["enum"])
+		}
+
+		required := schema["required"].([]string)
+		for _, name := range required {
+			if name == "labels" {
+				t.Error("expected omitempty field to be optional")
+			}
+		}
+		if len(required) != len(expected)-1 {
+			t.Errorf("expected %d required properties, got %v", len(expected)-1, required)
+		}
+	})
+
+	t.Run("pointer fields are nullable", func(t *testing.T) {
+		properties := For[struct {
+			Address *address `json:"address"`
+			Rank    *int     `json:"rank" enum:"1,2"`
+			Name    string   `json:"name"`
+		}]()["properties"].(map[string]any)
+
+		address := properties["address"].(map[string]any)
+		if !reflect.DeepEqual(address["type"], []any{"object", "null"}) || address["properties"] == nil {
+			t.Errorf("expected a nullable object, got %v", address)
+		}
+		rank := properties["rank"].(map[string]any)
+		if !reflect.DeepEqual(rank["enum"], []any{1.0, 2.0, nil}) {
+			t.Errorf("expected null to be allowed in the enum, got %v", rank["enum"])
+		}
+		if schemaType, nullable := Type(properties["name"].(map[string]any)); schemaType != "string" || nullable {
+			t.Errorf("expected a plain string, got %v", properties["name"])
+		}
+	})
+
+	t.Run("recursive types", func(t *testing.T) {
+		schema := For[node]()
+		children := schema["properties"].(map[string]any)["children"].(map[string]any)
+		items := children["items"].(map[string]any)
+		if items["type"] != "object" || items["properties"] != nil {
+			t.Errorf("expected recursive reference to collapse to a plain object, got %v", items)
+		}
+	})
+}
+
+func TestIsStrict(t *testing.T) {
+	if !IsStrict(For[struct {
+		Name  string   `json:"name"`
+		Items []string `json:"items"`
+	}]()) {
+		t.Error("expected fully required struct to be strict")
+	}
+	if IsStrict(For[address]()) {
+		t.Error("expected struct with optional field not to be strict")
+	}
+	if !IsStrict(For[struct {
+		Rank *int `json:"rank"`
+	}]()) {
+		t.Error("expected a required nullable field to be strict")
+	}
+	if IsStrict(For[struct {
+		Address *address `json:"address"`
+	}]()) {
+		t.Error("expected a nullable object with optional fields not to be strict")
+	}
+	if IsStrict(For[map[string]int]()) {
+		t.Error("expected map not to be strict")
+	}
+
+	var decoded map[string]any
+	json.Unmarshal([]byte(`{"type":"object","properties":{"name":{"type":"string"}},"required":["name"],"additionalProperties":false}`), &decoded)
+	if !IsStrict(decoded) {
+		t.Error("expected a strict schema decoded from JSON to be strict")
+	}
+	malformed := map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{"name": "string"},
+		"required":             []string{"name"},
+		"additionalProperties": false,
+	}
+	if IsStrict(malformed) {
+		t.Error("expected a property that is not a schema not to be strict")
+	}
+}
+
+func TestRequired(t *testing.T) {
+	if names := Required(map[string]any{"required": []string{"a", "b"}}); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+	if names := Required(map[string]any{"required": []any{"a", 1, "b"}}); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("unexpected names: %v", names)
+	}
+	if names := Required(map[string]any{}); len(names) != 0 {
+		t.Errorf("expected no names, got %v", names)
+	}
+}
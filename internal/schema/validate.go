@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+func Validate(schema map[string]any, data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &ValidationError{Path: "$", Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+	return validate(schema, value, "$")
+}
+
+func validate(schema map[string]any, value any, path string) error {
+	schemaType, nullable := Type(schema)
+	if value == nil && nullable {
+		return nil
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return &ValidationError{Path: path, Message: fmt.Sprintf("value %v is not one of %v", value, enum)}
+	}
+
+	switch schemaType {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return typeError(path, "object", value)
+		}
+		return validateObject(schema, object, path)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return typeError(path, "array", value)
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range array {
+			if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return typeError(path, "string", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeError(path, "boolean", value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return typeError(path, "number", value)
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return typeError(path, "integer", value)
+		}
+	}
+	return nil
+}
+
+func validateObject(schema map[string]any, object map[string]any, path string) error {
+	for _, name := range Required(schema) {
+		if _, ok := object[name]; !ok {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("missing required property %q", name)}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPath := path + "." + name
+		if property, ok := properties[name].(map[string]any); ok {
+			if err := validate(property, object[name], propertyPath); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return &ValidationError{Path: propertyPath, Message: "unexpected property"}
+			}
+		case map[string]any:
+			if err := validate(additional, object[name], propertyPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func typeError(path, expected string, value any) error {
+	actual := "null"
+	switch value.(type) {
+	case map[string]any:
+		actual = "object"
+	case []any:
+		actual = "array"
+	case string:
+		actual = "string"
+	case bool:
+		actual = "boolean"
+	case float64:
+		actual = "number"
+	}
+	return &ValidationError{Path: path, Message: fmt.Sprintf("expected %s, got %s", expected, actual)}
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	schema := For[struct {
+		Name   string   `json:"name"`
+		Count  int      `json:"count"`
+		Tags   []string `json:"tags,omitempty"`
+		Status string   `json:"status" enum:"open,closed"`
+	}]()
+
+	t.Run("valid document", func(t *testing.T) {
+		err := Validate(schema, []byte(`{"name":"a","count":2,"tags":["x"],"status":"open"}`))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("optional property may be omitted", func(t *testing.T) {
+		err := Validate(schema, []byte(`{"name":"a","count":2,"status":"closed"}`))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`{"name":`)), "$", "")
+	})
+
+	t.Run("wrong root type", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`[]`)), "$", "expected object, got array")
+	})
+
+	t.Run("missing required property", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`{"name":"a","status":"open"}`)), "$", `missing required property "count"`)
+	})
+
+	t.Run("wrong property type", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`{"name":1,"count":2,"status":"open"}`)), "$.name", "expected string, got number")
+	})
+
+	t.Run("non-integer number", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`{"name":"a","count":2.5,"status":"open"}`)), "$.count", "expected integer, got number")
+	})
+
+	t.Run("invalid array item", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`{"name":"a","count":2,"tags":[true],"status":"open"}`)), "$.tags[0]", "expected string, got boolean")
+	})
+
+	t.Run("value outside enum", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`{"name":"a","count":2,"status":"pending"}`)), "$.status", "value pending is not one of [open closed]")
+	})
+
+	t.Run("null for a pointer field", func(t *testing.T) {
+		nullable := For[struct {
+			Name   string  `json:"name"`
+			Nick   *string `json:"nick"`
+			Status *string `json:"status" enum:"open,closed"`
+		}]()
+		if err := Validate(nullable, []byte(`{"name":"a","nick":null,"status":null}`)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := Validate(nullable, []byte(`{"name":"a","nick":"b","status":"open"}`)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		expectValidationError(t, Validate(nullable, []byte(`{"name":null,"nick":null,"status":null}`)), "$.name", "expected string, got null")
+	})
+
+	t.Run("unexpected property", func(t *testing.T) {
+		expectValidationError(t, Validate(schema, []byte(`{"name":"a","count":2,"status":"open","extra":1}`)), "$.extra", "unexpected property")
+	})
+}
+
+func TestValidateDecodedSchema(t *testing.T) {
+	var schema map[string]any
+	err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"address": {
+				"type": "object",
+				"properties": {"city": {"type": "string"}},
+				"required": ["city"]
+			}
+		},
+		"required": ["name", "address"]
+	}`), &schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := Validate(schema, []byte(`{"name":"a","address":{"city":"Paris"}}`)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectValidationError(t, Validate(schema, []byte(`{"address":{"city":"Paris"}}`)), "$", `missing required property "name"`)
+	expectValidationError(t, Validate(schema, []byte(`{"name":"a","address":{}}`)), "$.address", `missing required property "city"`)
+}
+
+func expectValidationError(t *testing.T, err error, path, message string) {
+	t.Helper()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if validationErr.Path != path {
+		t.Errorf("expected path %s, got %s", path, validationErr.Path)
+	}
+	if message != "" && validationErr.Message != message {
+		t.Errorf("expected message %q, got %q", message, validationErr.Message)
+	}
+}
@@ -1,56 +1,151 @@
 package strategy
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"agentic-ai-framework/internal/transport"
 	"agentic-ai-framework/internal/types"
 )
 
 type ChatCompletionsRequest struct {
-	Model            string
-	Messages         []ChatMessage
-	RequestParams    map[string]any
+	Model         string
+	Messages      []ChatMessage
+	RequestParams map[string]any
+	Stream        bool
+	Tools         []ChatTool
+	ToolChoice    any
 }
 
 type ChatMessage struct {
-	Role    string
-	Content string
+	Role       string
+	Content    string
+	Parts      []types.ContentPart
+	ToolCalls  []ChatToolCall
+	ToolCallID string
+}
+
+type ChatTool struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+}
+
+type ChatToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
 }
 
 type ChatCompletionsResponse struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-	Usage struct {
-		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
-		TotalTokens      int `json:"total_tokens"`
-	} `json:"usage"`
-	Error struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-		Code    string `json:"code"`
-	} `json:"error"`
+	ID                string                  `json:"id"`
+	Created           int64                   `json:"created"`
+	Model             string                  `json:"model"`
+	SystemFingerprint string                  `json:"system_fingerprint"`
+	Choices           []ChatCompletionsChoice `json:"choices"`
+	Usage             ChatCompletionsUsage    `json:"usage"`
+	Error             ChatCompletionsError    `json:"error"`
+	RequestID         string                  `json:"-"`
+	RateLimit         types.RateLimit         `json:"-"`
+	Attempts          int                     `json:"-"`
+	Raw               json.RawMessage         `json:"-"`
+}
+
+type ChatCompletionsChoice struct {
+	Index        int                    `json:"index"`
+	Message      ChatCompletionsMessage `json:"message"`
+	FinishReason string                 `json:"finish_reason"`
+}
+
+type ChatCompletionsMessage struct {
+	Content   string                    `json:"content"`
+	Refusal   string                    `json:"refusal"`
+	ToolCalls []ChatCompletionsToolCall `json:"tool_calls"`
+}
+
+type ChatCompletionsToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type ChatCompletionsToolCallDelta struct {
+	Index int `json:"index"`
+	ChatCompletionsToolCall
+}
+
+type ChatCompletionsUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	TotalTokens         int `json:"total_tokens"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+	CompletionTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details"`
+}
+
+type ChatCompletionsError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+}
+
+type ChatCompletionsStreamChunk struct {
+	ID                string                        `json:"id"`
+	Created           int64                         `json:"created"`
+	Model             string                        `json:"model"`
+	SystemFingerprint string                        `json:"system_fingerprint"`
+	Choices           []ChatCompletionsStreamChoice `json:"choices"`
+	Usage             *ChatCompletionsUsage         `json:"usage"`
+	Error             *ChatCompletionsError         `json:"error"`
+}
+
+type ChatCompletionsStreamChoice struct {
+	Delta struct {
+		Content   string                         `json:"content"`
+		Refusal   string                         `json:"refusal"`
+		ToolCalls []ChatCompletionsToolCallDelta `json:"tool_calls"`
+	} `json:"delta"`
+	FinishReason string `json:"finish_reason"`
 }
 
 type ChatCompletionsConfig struct {
-	BaseURL    string
-	Endpoint   string
-	APIKey     string
-	HTTPClient *http.Client
+	BaseURL      string
+	Endpoint     string
+	APIKey       string
+	Organization string
+	Project      string
+	HTTPClient   *http.Client
+	RetryPolicy  transport.RetryPolicy
+}
+
+func (c ChatCompletionsConfig) Headers() map[string]string {
+	headers := map[string]string{
+		"Authorization": "Bearer " + c.APIKey,
+	}
+	if c.Organization != "" {
+		headers["OpenAI-Organization"] = c.Organization
+	}
+	if c.Project != "" {
+		headers["OpenAI-Project"] = c.Project
+	}
+	return headers
 }
 
 func BuildChatCompletionsRequestBody(req ChatCompletionsRequest) map[string]any {
-	messages := make([]map[string]string, len(req.Messages))
+	messages := make([]map[string]any, len(req.Messages))
 	for i, msg := range req.Messages {
-		messages[i] = map[string]string{
-			"role":    msg.Role,
-			"content": msg.Content,
-		}
+		messages[i] = buildChatMessage(msg)
 	}
 
 	requestBody := map[string]any{
@@ -58,28 +153,130 @@ func BuildChatCompletionsRequestBody(req ChatCompletionsRequest) map[string]any
 		"messages": messages,
 	}
 
+	if len(req.Tools) > 0 {
+		tools := make([]map[string]any, len(req.Tools))
+		for i, tool := range req.Tools {
+			function := map[string]any{
+				"name":       tool.Name,
+				"parameters": tool.Parameters,
+			}
+			if tool.Description != "" {
+				function["description"] = tool.Description
+			}
+			tools[i] = map[string]any{
+				"type":     "function",
+				"function": function,
+			}
+		}
+		requestBody["tools"] = tools
+	}
+
+	if req.ToolChoice != nil {
+		requestBody["tool_choice"] = req.ToolChoice
+	}
+
 	for key, value := range req.RequestParams {
+		if format, ok := value.(types.ResponseFormat); ok {
+			value = buildChatResponseFormat(format)
+		}
 		requestBody[key] = value
 	}
 
+	if req.Stream {
+		requestBody["stream"] = true
+		requestBody["stream_options"] = map[string]any{"include_usage": true}
+	}
+
 	return requestBody
 }
 
-func ExecuteChatCompletionsRequest(config ChatCompletionsConfig, requestBody map[string]any) (ChatCompletionsResponse, int, error) {
-	url := config.BaseURL + config.Endpoint
-	headers := map[string]string{
-		"Authorization": "Bearer " + config.APIKey,
+func buildChatResponseFormat(format types.ResponseFormat) map[string]any {
+	if format.Type() != types.ResponseFormatJSONSchema {
+		return map[string]any{"type": format.Type()}
+	}
+	return map[string]any{
+		"type": types.ResponseFormatJSONSchema,
+		"json_schema": map[string]any{
+			"name":   format.Name(),
+			"schema": format.Schema(),
+			"strict": format.Strict(),
+		},
+	}
+}
+
+func buildChatMessage(msg ChatMessage) map[string]any {
+	message := map[string]any{
+		"role":    msg.Role,
+		"content": msg.Content,
 	}
 
-	ctx, cancel := transport.CreateRequestContext(transport.DefaultTimeout)
+	if len(msg.Parts) > 0 {
+		message["content"] = buildChatContentParts(msg.Parts)
+	}
+
+	if len(msg.ToolCalls) > 0 {
+		toolCalls := make([]map[string]any, len(msg.ToolCalls))
+		for i, call := range msg.ToolCalls {
+			toolCalls[i] = map[string]any{
+				"id":   call.ID,
+				"type": "function",
+				"function": map[string]any{
+					"name":      call.Name,
+					"arguments": call.Arguments,
+				},
+			}
+		}
+		message["tool_calls"] = toolCalls
+		if msg.Content == "" {
+			message["content"] = nil
+		}
+	}
+
+	if msg.ToolCallID != "" {
+		message["tool_call_id"] = msg.ToolCallID
+	}
+
+	return message
+}
+
+func buildChatContentParts(parts []types.ContentPart) []map[string]any {
+	content := make([]map[string]any, len(parts))
+	for i, part := range parts {
+		switch part.Type() {
+		case types.ContentPartImage:
+			imageURL := map[string]any{"url": part.DataURL()}
+			if part.Detail() != "" {
+				imageURL["detail"] = part.Detail()
+			}
+			content[i] = map[string]any{"type": "image_url", "image_url": imageURL}
+		case types.ContentPartFile:
+			content[i] = map[string]any{
+				"type": "file",
+				"file": map[string]any{
+					"filename":  part.Filename(),
+					"file_data": part.DataURL(),
+				},
+			}
+		default:
+			content[i] = map[string]any{"type": "text", "text": part.Text()}
+		}
+	}
+	return content
+}
+
+func ExecuteChatCompletionsRequest(ctx context.Context, config ChatCompletionsConfig, requestBody map[string]any) (ChatCompletionsResponse, int, error) {
+	url := config.BaseURL + config.Endpoint
+	headers := config.Headers()
+
+	requestCtx, cancel := transport.CreateRequestContext(ctx, transport.DefaultTimeout)
 	defer cancel()
 
-	req, err := transport.CreateJSONRequest(ctx, "POST", url, requestBody, headers)
+	req, err := transport.CreateJSONRequest(requestCtx, "POST", url, requestBody, headers)
 	if err != nil {
 		return ChatCompletionsResponse{}, 0, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	resp, err := transport.ExecuteRequest(config.HTTPClient, req)
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
 	if err != nil {
 		return ChatCompletionsResponse{}, 0, err
 	}
@@ -92,39 +289,225 @@ func ExecuteChatCompletionsRequest(config ChatCompletionsConfig, requestBody map
 
 	var responseBody ChatCompletionsResponse
 	err = transport.DecodeJSONResponse(bodyBytes, &responseBody)
-	if err != nil {
+	if err != nil && statusCode == http.StatusOK {
 		return ChatCompletionsResponse{}, statusCode, fmt.Errorf("failed to decode response: %v", err)
 	}
+	responseBody.RequestID = resp.Header.Get("x-request-id")
+	responseBody.RateLimit = transport.ParseRateLimit(resp.Header)
+	responseBody.Attempts = attempts
+	responseBody.Raw = bodyBytes
 
 	return responseBody, statusCode, nil
 }
 
 func ParseChatCompletionsResponse(response ChatCompletionsResponse, statusCode int) (types.GenerateTextResult, error) {
-	if statusCode != http.StatusOK {
-		if response.Error.Message != "" {
-			return types.GenerateTextResult{}, fmt.Errorf("API error (status %d): %s (type: %s, code: %s)", statusCode, response.Error.Message, response.Error.Type, response.Error.Code)
-		}
-		return types.GenerateTextResult{}, fmt.Errorf("API request failed with status %d", statusCode)
-	}
-
-	if response.Error.Message != "" {
-		return types.GenerateTextResult{}, fmt.Errorf("API error: %s (type: %s)", response.Error.Message, response.Error.Type)
+	if statusCode != http.StatusOK || response.Error.Message != "" {
+		apiErr := types.NewAPIError(statusCode, response.Error.Type, response.Error.Code, response.Error.Message, response.RequestID)
+		apiErr.Attempts = response.Attempts
+		apiErr.RateLimit = response.RateLimit
+		return types.GenerateTextResult{}, apiErr
 	}
 
 	if len(response.Choices) == 0 {
 		return types.GenerateTextResult{}, fmt.Errorf("no choices in API response")
 	}
 
-	usage := types.NewTokenUsage(
-		response.Usage.PromptTokens,
-		response.Usage.CompletionTokens,
-		response.Usage.TotalTokens,
-	)
+	choices := make([]types.Choice, len(response.Choices))
+	for i, choice := range response.Choices {
+		message := choice.Message
+		choices[i] = types.NewChoice(choice.Index, message.Content, choice.FinishReason, chatToolCalls(message.ToolCalls), message.Refusal)
+	}
+	first := choices[0]
 
-	result := types.NewGenerateTextResult(
-		response.Choices[0].Message.Content,
-		usage,
-	)
+	result := types.NewGenerateTextResult(first.TextContent(), chatUsage(response.Usage)).
+		WithAttempts(response.Attempts).
+		WithFinishReason(first.FinishReason()).
+		WithRefusal(first.Refusal()).
+		WithResponseID(response.ID).
+		WithModel(response.Model).
+		WithSystemFingerprint(response.SystemFingerprint).
+		WithChoices(choices).
+		WithRawResponse(response.Raw).
+		WithRateLimit(response.RateLimit)
+	if response.Created != 0 {
+		result = result.WithCreated(time.Unix(response.Created, 0))
+	}
+	if len(first.ToolCalls()) > 0 {
+		result = result.WithToolCalls(first.ToolCalls())
+	}
 
 	return result, nil
 }
+
+func chatUsage(usage ChatCompletionsUsage) types.TokenUsage {
+	return types.NewTokenUsage(usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens).
+		WithCachedTokens(usage.PromptTokensDetails.CachedTokens).
+		WithReasoningTokens(usage.CompletionTokensDetails.ReasoningTokens)
+}
+
+func chatToolCalls(calls []ChatCompletionsToolCall) []types.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	toolCalls := make([]types.ToolCall, len(calls))
+	for i, call := range calls {
+		toolCalls[i] = types.NewToolCall(call.ID, call.Function.Name, call.Function.Arguments)
+	}
+	return toolCalls
+}
+
+func ExecuteChatCompletionsStreamRequest(ctx context.Context, config ChatCompletionsConfig, requestBody map[string]any) (<-chan types.StreamChunk, error) {
+	url := config.BaseURL + config.Endpoint
+	headers := config.Headers()
+	headers["Accept"] = "text/event-stream"
+
+	requestCtx, headersReceived, cancel := transport.CreateStreamContext(ctx, transport.DefaultTimeout)
+
+	req, err := transport.CreateJSONRequest(requestCtx, "POST", url, requestBody, headers)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
+	headersReceived()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer cancel()
+		bodyBytes, err := transport.ReadResponseBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		var responseBody ChatCompletionsResponse
+		transport.DecodeJSONResponse(bodyBytes, &responseBody)
+		responseBody.RequestID = resp.Header.Get("x-request-id")
+		responseBody.RateLimit = transport.ParseRateLimit(resp.Header)
+		responseBody.Attempts = attempts
+		_, err = ParseChatCompletionsResponse(responseBody, resp.StatusCode)
+		return nil, err
+	}
+
+	chunks := make(chan types.StreamChunk)
+	go func() {
+		defer cancel()
+		defer resp.Body.Close()
+		defer close(chunks)
+		parseChatCompletionsStream(requestCtx, resp.Body, chunks, attempts, transport.ParseRateLimit(resp.Header))
+	}()
+
+	return chunks, nil
+}
+
+func ParseChatCompletionsStream(ctx context.Context, body io.Reader, chunks chan<- types.StreamChunk) {
+	parseChatCompletionsStream(ctx, body, chunks, 0, types.RateLimit{})
+}
+
+func parseChatCompletionsStream(ctx context.Context, body io.Reader, chunks chan<- types.StreamChunk, attempts int, rateLimit types.RateLimit) {
+	var text, refusal strings.Builder
+	var usage types.TokenUsage
+	var toolCalls []ChatCompletionsToolCall
+	var meta ChatCompletionsStreamChunk
+	var finishReason string
+	done := false
+
+	err := transport.ReadEventStream(body, func(data string) error {
+		if data == "[DONE]" {
+			done = true
+			return transport.ErrStreamDone
+		}
+
+		var chunk ChatCompletionsStreamChunk
+		if err := transport.DecodeJSONResponse([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to decode stream chunk: %v", err)
+		}
+		if chunk.Error != nil {
+			return types.NewAPIError(0, chunk.Error.Type, chunk.Error.Code, chunk.Error.Message, "")
+		}
+		if meta.ID == "" {
+			meta.ID, meta.Created, meta.Model = chunk.ID, chunk.Created, chunk.Model
+		}
+		if chunk.SystemFingerprint != "" {
+			meta.SystemFingerprint = chunk.SystemFingerprint
+		}
+		if chunk.Usage != nil {
+			usage = chatUsage(*chunk.Usage)
+		}
+		if len(chunk.Choices) > 0 {
+			refusal.WriteString(chunk.Choices[0].Delta.Refusal)
+			if chunk.Choices[0].FinishReason != "" {
+				finishReason = chunk.Choices[0].FinishReason
+			}
+			var err error
+			toolCalls, err = mergeToolCallDeltas(toolCalls, chunk.Choices[0].Delta.ToolCalls)
+			if err != nil {
+				return err
+			}
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			delta := chunk.Choices[0].Delta.Content
+			text.WriteString(delta)
+			if err := sendStreamChunk(ctx, chunks, types.NewStreamDelta(delta)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		sendStreamChunk(ctx, chunks, types.NewStreamError(err))
+		return
+	}
+	if !done {
+		sendStreamChunk(ctx, chunks, types.NewStreamError(fmt.Errorf("stream ended before [DONE] event")))
+		return
+	}
+
+	result := types.NewGenerateTextResult(text.String(), usage).
+		WithAttempts(attempts).
+		WithFinishReason(finishReason).
+		WithRefusal(refusal.String()).
+		WithResponseID(meta.ID).
+		WithModel(meta.Model).
+		WithSystemFingerprint(meta.SystemFingerprint).
+		WithRateLimit(rateLimit)
+	if meta.Created != 0 {
+		result = result.WithCreated(time.Unix(meta.Created, 0))
+	}
+	if calls := chatToolCalls(toolCalls); len(calls) > 0 {
+		result = result.WithToolCalls(calls)
+	}
+	sendStreamChunk(ctx, chunks, types.NewStreamResult(result))
+}
+
+func mergeToolCallDeltas(calls []ChatCompletionsToolCall, deltas []ChatCompletionsToolCallDelta) ([]ChatCompletionsToolCall, error) {
+	for _, delta := range deltas {
+		if delta.Index < 0 || delta.Index > len(calls) {
+			return calls, fmt.Errorf("invalid tool call index %d in stream chunk after %d tool calls", delta.Index, len(calls))
+		}
+		if delta.Index == len(calls) {
+			calls = append(calls, ChatCompletionsToolCall{})
+		}
+		call := &calls[delta.Index]
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Type != "" {
+			call.Type = delta.Type
+		}
+		call.Function.Name += delta.Function.Name
+		call.Function.Arguments += delta.Function.Arguments
+	}
+	return calls, nil
+}
+
+func sendStreamChunk(ctx context.Context, chunks chan<- types.StreamChunk, chunk types.StreamChunk) error {
+	select {
+	case chunks <- chunk:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
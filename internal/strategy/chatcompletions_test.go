@@ -1,8 +1,16 @@
 package strategy
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/types"
 )
 
 func TestBuildChatCompletionsRequestBody(t *testing.T) {
@@ -24,9 +32,9 @@ func TestBuildChatCompletionsRequestBody(t *testing.T) {
 		t.Errorf("expected model 'gpt-4', got %v", body["model"])
 	}
 
-	messages, ok := body["messages"].([]map[string]string)
+	messages, ok := body["messages"].([]map[string]any)
 	if !ok {
-		t.Fatal("expected messages to be []map[string]string")
+		t.Fatal("expected messages to be []map[string]any")
 	}
 
 	if len(messages) != 2 {
@@ -50,23 +58,94 @@ func TestBuildChatCompletionsRequestBody(t *testing.T) {
 	}
 }
 
+func TestBuildChatCompletionsRequestBodyTools(t *testing.T) {
+	req := ChatCompletionsRequest{
+		Model: "gpt-4",
+		Messages: []ChatMessage{
+			{Role: "user", Content: "Weather in Paris?"},
+			{Role: "assistant", ToolCalls: []ChatToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"city":"Paris"}`}}},
+			{Role: "tool", Content: "sunny", ToolCallID: "call_1"},
+		},
+		Tools: []ChatTool{
+			{Name: "get_weather", Description: "Get the weather", Parameters: map[string]any{"type": "object"}},
+		},
+		ToolChoice: "auto",
+	}
+
+	body := BuildChatCompletionsRequestBody(req)
+
+	tools, ok := body["tools"].([]map[string]any)
+	if !ok || len(tools) != 1 {
+		t.Fatalf("expected one tool, got %v", body["tools"])
+	}
+	if tools[0]["type"] != "function" {
+		t.Errorf("expected tool type 'function', got %v", tools[0]["type"])
+	}
+	function := tools[0]["function"].(map[string]any)
+	if function["name"] != "get_weather" || function["description"] != "Get the weather" {
+		t.Errorf("unexpected function definition: %v", function)
+	}
+	if body["tool_choice"] != "auto" {
+		t.Errorf("expected tool_choice 'auto', got %v", body["tool_choice"])
+	}
+
+	messages := body["messages"].([]map[string]any)
+	if messages[1]["content"] != nil {
+		t.Errorf("expected nil content for tool call message, got %v", messages[1]["content"])
+	}
+	toolCalls := messages[1]["tool_calls"].([]map[string]any)
+	if toolCalls[0]["id"] != "call_1" {
+		t.Errorf("expected tool call id 'call_1', got %v", toolCalls[0]["id"])
+	}
+	if messages[2]["tool_call_id"] != "call_1" {
+		t.Errorf("expected tool_call_id 'call_1', got %v", messages[2]["tool_call_id"])
+	}
+}
+
+func TestBuildChatCompletionsRequestBodyContentParts(t *testing.T) {
+	req := ChatCompletionsRequest{
+		Model: "gpt-4.1",
+		Messages: []ChatMessage{{
+			Role:    "user",
+			Content: "What is on this page?",
+			Parts: []types.ContentPart{
+				types.NewTextPart("What is on this page?"),
+				types.NewImagePart([]byte("img"), "image/png").WithDetail("high"),
+				types.NewImageURLPart("https://example.com/page-2.png"),
+				types.NewFilePart("report.pdf", []byte("%PDF"), "application/pdf"),
+			},
+		}},
+	}
+
+	body := BuildChatCompletionsRequestBody(req)
+
+	content, ok := body["messages"].([]map[string]any)[0]["content"].([]map[string]any)
+	if !ok || len(content) != 4 {
+		t.Fatalf("expected 4 content parts, got %v", body["messages"])
+	}
+	if content[0]["type"] != "text" || content[0]["text"] != "What is on this page?" {
+		t.Errorf("unexpected text part: %v", content[0])
+	}
+	imageURL := content[1]["image_url"].(map[string]any)
+	if content[1]["type"] != "image_url" || imageURL["url"] != "data:image/png;base64,aW1n" || imageURL["detail"] != "high" {
+		t.Errorf("unexpected inline image part: %v", content[1])
+	}
+	if content[2]["image_url"].(map[string]any)["url"] != "https://example.com/page-2.png" {
+		t.Errorf("unexpected image URL part: %v", content[2])
+	}
+	file := content[3]["file"].(map[string]any)
+	if content[3]["type"] != "file" || file["filename"] != "report.pdf" || file["file_data"] != "data:application/pdf;base64,JVBERg==" {
+		t.Errorf("unexpected file part: %v", content[3])
+	}
+}
+
 func TestParseChatCompletionsResponse(t *testing.T) {
 	t.Run("successful parsing", func(t *testing.T) {
 		response := ChatCompletionsResponse{
-			Choices: []struct {
-				Message struct {
-					Content string `json:"content"`
-				} `json:"message"`
-			}{
-				{Message: struct {
-					Content string `json:"content"`
-				}{Content: "Hello world"}},
+			Choices: []ChatCompletionsChoice{
+				{Message: ChatCompletionsMessage{Content: "Hello world"}},
 			},
-			Usage: struct {
-				PromptTokens     int `json:"prompt_tokens"`
-				CompletionTokens int `json:"completion_tokens"`
-				TotalTokens      int `json:"total_tokens"`
-			}{
+			Usage: ChatCompletionsUsage{
 				PromptTokens:     10,
 				CompletionTokens: 20,
 				TotalTokens:      30,
@@ -92,13 +171,67 @@ func TestParseChatCompletionsResponse(t *testing.T) {
 		}
 	})
 
+	t.Run("tool calls", func(t *testing.T) {
+		var response ChatCompletionsResponse
+		err := json.Unmarshal([]byte(`{"choices":[{"message":{"content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]}}]}`), &response)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		result, err := ParseChatCompletionsResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		toolCalls := result.ToolCalls()
+		if len(toolCalls) != 1 {
+			t.Fatalf("expected 1 tool call, got %d", len(toolCalls))
+		}
+		if toolCalls[0].ID() != "call_1" || toolCalls[0].Name() != "get_weather" || toolCalls[0].Arguments() != `{"city":"Paris"}` {
+			t.Errorf("unexpected tool call: %+v", toolCalls[0])
+		}
+	})
+
+	t.Run("response metadata", func(t *testing.T) {
+		raw := []byte(`{"id":"chatcmpl-1","created":1700000000,"model":"gpt-4.1-2025-04-14","system_fingerprint":"fp_1","choices":[{"index":0,"message":{"content":"One"},"finish_reason":"length"},{"index":1,"message":{"content":null,"refusal":"I can't help with that."},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15,"prompt_tokens_details":{"cached_tokens":4},"completion_tokens_details":{"reasoning_tokens":2}}}`)
+		var response ChatCompletionsResponse
+		if err := json.Unmarshal(raw, &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		response.Raw = raw
+
+		result, err := ParseChatCompletionsResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.FinishReason() != types.FinishReasonLength || !result.Truncated() {
+			t.Errorf("expected truncated result with finish reason 'length', got '%s'", result.FinishReason())
+		}
+		if result.ResponseID() != "chatcmpl-1" || result.Model() != "gpt-4.1-2025-04-14" || result.SystemFingerprint() != "fp_1" {
+			t.Errorf("unexpected metadata: id=%s model=%s fingerprint=%s", result.ResponseID(), result.Model(), result.SystemFingerprint())
+		}
+		if !result.Created().Equal(time.Unix(1700000000, 0)) {
+			t.Errorf("unexpected created time: %v", result.Created())
+		}
+		if result.Usage().CachedTokens() != 4 || result.Usage().ReasoningTokens() != 2 {
+			t.Errorf("expected 4 cached and 2 reasoning tokens, got %d and %d", result.Usage().CachedTokens(), result.Usage().ReasoningTokens())
+		}
+		choices := result.Choices()
+		if len(choices) != 2 {
+			t.Fatalf("expected 2 choices, got %d", len(choices))
+		}
+		if choices[1].Index() != 1 || choices[1].Refusal() != "I can't help with that." || choices[1].FinishReason() != types.FinishReasonStop {
+			t.Errorf("unexpected second choice: %+v", choices[1])
+		}
+		if string(result.RawResponse()) != string(raw) {
+			t.Errorf("expected raw response to be preserved, got %s", result.RawResponse())
+		}
+	})
+
 	t.Run("empty choices", func(t *testing.T) {
 		response := ChatCompletionsResponse{
-			Choices: []struct {
-				Message struct {
-					Content string `json:"content"`
-				} `json:"message"`
-			}{},
+			Choices: []ChatCompletionsChoice{},
 		}
 
 		_, err := ParseChatCompletionsResponse(response, http.StatusOK)
@@ -131,6 +264,13 @@ func TestParseChatCompletionsResponse(t *testing.T) {
 		if err.Error() != expected {
 			t.Errorf("expected '%s', got '%s'", expected, err.Error())
 		}
+		var apiErr *types.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected APIError, got %T", err)
+		}
+		if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Code != "invalid_api_key" || apiErr.Retryable {
+			t.Errorf("unexpected APIError: %+v", apiErr)
+		}
 	})
 
 	t.Run("HTTP error", func(t *testing.T) {
@@ -146,3 +286,315 @@ func TestParseChatCompletionsResponse(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildChatCompletionsRequestBodyStream(t *testing.T) {
+	req := ChatCompletionsRequest{
+		Model:    "gpt-4",
+		Messages: []ChatMessage{{Role: "user", Content: "Hello"}},
+		Stream:   true,
+	}
+
+	body := BuildChatCompletionsRequestBody(req)
+
+	if body["stream"] != true {
+		t.Errorf("expected stream true, got %v", body["stream"])
+	}
+	streamOptions, ok := body["stream_options"].(map[string]any)
+	if !ok || streamOptions["include_usage"] != true {
+		t.Errorf("expected stream_options.include_usage true, got %v", body["stream_options"])
+	}
+}
+
+func TestBuildChatCompletionsRequestBodyResponseFormat(t *testing.T) {
+	t.Run("json_schema", func(t *testing.T) {
+		schema := map[string]any{"type": "object"}
+		body := BuildChatCompletionsRequestBody(ChatCompletionsRequest{
+			Model:         "gpt-4.1",
+			Messages:      []ChatMessage{{Role: "user", Content: "Hello"}},
+			RequestParams: map[string]any{"response_format": types.NewJSONSchemaFormat("invoice", schema, true)},
+		})
+
+		format, ok := body["response_format"].(map[string]any)
+		if !ok || format["type"] != "json_schema" {
+			t.Fatalf("expected json_schema response_format, got %v", body["response_format"])
+		}
+		jsonSchema := format["json_schema"].(map[string]any)
+		if jsonSchema["name"] != "invoice" || jsonSchema["strict"] != true || jsonSchema["schema"].(map[string]any)["type"] != "object" {
+			t.Errorf("unexpected json_schema: %v", jsonSchema)
+		}
+	})
+
+	t.Run("json_object", func(t *testing.T) {
+		body := BuildChatCompletionsRequestBody(ChatCompletionsRequest{
+			Model:         "gpt-4.1",
+			Messages:      []ChatMessage{{Role: "user", Content: "Hello"}},
+			RequestParams: map[string]any{"response_format": types.NewJSONObjectFormat()},
+		})
+
+		format, ok := body["response_format"].(map[string]any)
+		if !ok || format["type"] != "json_object" || len(format) != 1 {
+			t.Errorf("expected json_object response_format, got %v", body["response_format"])
+		}
+	})
+}
+
+func TestParseChatCompletionsStream(t *testing.T) {
+	t.Run("aggregates deltas and usage", func(t *testing.T) {
+		body := `data: {"choices":[{"delta":{"role":"assistant","content":""}}]}
+
+data: {"choices":[{"delta":{"content":"Hel"}}]}
+
+data: {"choices":[{"delta":{"content":"lo"}}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}
+
+data: [DONE]
+
+`
+		chunks := collectStream(body)
+
+		if len(chunks) != 3 {
+			t.Fatalf("expected 3 chunks, got %d", len(chunks))
+		}
+		if chunks[0].TextDelta() != "Hel" || chunks[1].TextDelta() != "lo" {
+			t.Errorf("unexpected deltas: '%s', '%s'", chunks[0].TextDelta(), chunks[1].TextDelta())
+		}
+		if !chunks[2].Done() {
+			t.Fatal("expected final chunk to be done")
+		}
+		result := chunks[2].Result()
+		if result.TextContent() != "Hello" {
+			t.Errorf("expected 'Hello', got '%s'", result.TextContent())
+		}
+		if result.Usage().TotalTokens() != 5 {
+			t.Errorf("expected 5 total tokens, got %d", result.Usage().TotalTokens())
+		}
+	})
+
+	t.Run("response metadata", func(t *testing.T) {
+		body := `data: {"id":"chatcmpl-2","created":1700000000,"model":"gpt-4.1","choices":[{"delta":{"content":"Hi"}}]}
+
+data: {"id":"chatcmpl-2","created":1700000000,"model":"gpt-4.1","system_fingerprint":"fp_2","choices":[{"delta":{},"finish_reason":"stop"}]}
+
+data: [DONE]
+
+`
+		chunks := collectStream(body)
+
+		result := chunks[len(chunks)-1].Result()
+		if result.FinishReason() != types.FinishReasonStop {
+			t.Errorf("expected finish reason 'stop', got '%s'", result.FinishReason())
+		}
+		if result.ResponseID() != "chatcmpl-2" || result.Model() != "gpt-4.1" || result.SystemFingerprint() != "fp_2" {
+			t.Errorf("unexpected metadata: id=%s model=%s fingerprint=%s", result.ResponseID(), result.Model(), result.SystemFingerprint())
+		}
+	})
+
+	t.Run("assembles tool call deltas", func(t *testing.T) {
+		body := `data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}
+
+data: {"choices":[{"delta":{},"finish_reason":"tool_calls"}]}
+
+data: [DONE]
+
+`
+		chunks := collectStream(body)
+
+		if len(chunks) != 1 {
+			t.Fatalf("expected only the final chunk, got %d", len(chunks))
+		}
+		result := chunks[0].Result()
+		calls := result.ToolCalls()
+		if len(calls) != 2 || result.FinishReason() != types.FinishReasonToolCalls {
+			t.Fatalf("expected two tool calls, got %v (%s)", calls, result.FinishReason())
+		}
+		if calls[0].ID() != "call_1" || calls[0].Name() != "get_weather" || calls[0].Arguments() != `{"city":"Paris"}` {
+			t.Errorf("unexpected first call: %+v", calls[0])
+		}
+		if calls[1].ID() != "call_2" || calls[1].Name() != "get_time" || calls[1].Arguments() != "{}" {
+			t.Errorf("unexpected second call: %+v", calls[1])
+		}
+	})
+
+	t.Run("rejects malformed tool call indexes", func(t *testing.T) {
+		for _, index := range []string{"-1", "1", "1000000000"} {
+			chunks := collectStream(`data: {"choices":[{"delta":{"tool_calls":[{"index":` + index + `,"id":"call_1","function":{"name":"get_time","arguments":"{}"}}]}}]}
+
+data: [DONE]
+
+`)
+			if len(chunks) != 1 || chunks[0].Err() == nil {
+				t.Errorf("expected a stream error for tool call index %s, got %v", index, chunks)
+			}
+		}
+	})
+
+	t.Run("missing DONE event", func(t *testing.T) {
+		chunks := collectStream("data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+
+		last := chunks[len(chunks)-1]
+		if last.Err() == nil {
+			t.Fatal("expected error when stream ends without [DONE]")
+		}
+	})
+
+	t.Run("error event", func(t *testing.T) {
+		chunks := collectStream("data: {\"error\":{\"message\":\"overloaded\",\"type\":\"server_error\"}}\n\n")
+
+		if len(chunks) != 1 || chunks[0].Err() == nil {
+			t.Fatalf("expected a single error chunk, got %v", chunks)
+		}
+		expected := "API error: overloaded (type: server_error)"
+		if chunks[0].Err().Error() != expected {
+			t.Errorf("expected '%s', got '%s'", expected, chunks[0].Err().Error())
+		}
+	})
+}
+
+func TestExecuteChatCompletionsStreamRequest(t *testing.T) {
+	t.Run("streams from server", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Accept") != "text/event-stream" {
+				t.Errorf("expected Accept text/event-stream, got %s", r.Header.Get("Accept"))
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("x-ratelimit-limit-requests", "500")
+			w.Header().Set("x-ratelimit-remaining-requests", "499")
+			w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n"))
+			w.(http.Flusher).Flush()
+			w.Write([]byte("data: [DONE]\n\n"))
+		}))
+		defer server.Close()
+
+		cfg := ChatCompletionsConfig{BaseURL: server.URL, Endpoint: "/chat/completions", APIKey: "key", HTTPClient: server.Client()}
+		chunks, err := ExecuteChatCompletionsStreamRequest(context.Background(), cfg, map[string]any{"stream": true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var final types.StreamChunk
+		for chunk := range chunks {
+			final = chunk
+		}
+		result := final.Result()
+		if result.TextContent() != "Hi" {
+			t.Errorf("expected 'Hi', got '%s'", result.TextContent())
+		}
+		if result.Attempts() != 1 || result.RateLimit().Requests().Remaining() != 499 {
+			t.Errorf("expected attempts and rate limit on the final result, got %d and %+v", result.Attempts(), result.RateLimit())
+		}
+	})
+
+	t.Run("API error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"Invalid API key","type":"authentication_error","code":"invalid_api_key"}}`))
+		}))
+		defer server.Close()
+
+		cfg := ChatCompletionsConfig{BaseURL: server.URL, Endpoint: "/chat/completions", APIKey: "key", HTTPClient: server.Client()}
+		_, err := ExecuteChatCompletionsStreamRequest(context.Background(), cfg, map[string]any{"stream": true})
+		if err == nil {
+			t.Fatal("expected error for unauthorized status")
+		}
+		expected := "API error (status 401): Invalid API key (type: authentication_error, code: invalid_api_key)"
+		if err.Error() != expected {
+			t.Errorf("expected '%s', got '%s'", expected, err.Error())
+		}
+	})
+}
+
+func collectStream(body string) []types.StreamChunk {
+	chunks := make(chan types.StreamChunk)
+	go func() {
+		defer close(chunks)
+		ParseChatCompletionsStream(context.Background(), strings.NewReader(body), chunks)
+	}()
+
+	var collected []types.StreamChunk
+	for chunk := range chunks {
+		collected = append(collected, chunk)
+	}
+	return collected
+}
+
+func TestExecuteChatCompletionsRequestHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := ChatCompletionsConfig{BaseURL: server.URL, Endpoint: "/chat/completions", APIKey: "key", HTTPClient: server.Client()}
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, _, err := ExecuteChatCompletionsRequest(ctx, cfg, map[string]any{})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded error, got %v", err)
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		_, _, err := ExecuteChatCompletionsRequest(ctx, cfg, map[string]any{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled error, got %v", err)
+		}
+	})
+}
+
+func TestChatCompletionsStreamStopsOnCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for {
+			_, err := w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"tick\"}}]}\n\n"))
+			if err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := ChatCompletionsConfig{BaseURL: server.URL, Endpoint: "/chat/completions", APIKey: "key", HTTPClient: server.Client()}
+	chunks, err := ExecuteChatCompletionsStreamRequest(ctx, cfg, map[string]any{"stream": true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	<-chunks
+	cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		for range chunks {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected stream channel to close after cancellation")
+	}
+}
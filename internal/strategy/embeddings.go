@@ -0,0 +1,152 @@
+package strategy
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+
+	"agentic-ai-framework/internal/transport"
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	EmbeddingsMaxBatchInputs = 2048
+	EmbeddingsMaxBatchTokens = 300_000
+)
+
+type EmbeddingsRequest struct {
+	Model         string
+	Input         []string
+	RequestParams map[string]any
+}
+
+type EmbeddingsResponse struct {
+	Data      []EmbeddingsData     `json:"data"`
+	Model     string               `json:"model"`
+	Usage     ChatCompletionsUsage `json:"usage"`
+	Error     ChatCompletionsError `json:"error"`
+	RequestID string               `json:"-"`
+	Attempts  int                  `json:"-"`
+}
+
+type EmbeddingsData struct {
+	Index     int             `json:"index"`
+	Embedding json.RawMessage `json:"embedding"`
+}
+
+func BuildEmbeddingsRequestBody(req EmbeddingsRequest) map[string]any {
+	requestBody := map[string]any{
+		"model": req.Model,
+		"input": req.Input,
+	}
+	for key, value := range req.RequestParams {
+		requestBody[key] = value
+	}
+	return requestBody
+}
+
+func BatchEmbeddingInputs(inputs []string, tokens []int, maxInputs int, maxTokens int) [][]string {
+	var batches [][]string
+	var batch []string
+	batchTokens := 0
+	for i, input := range inputs {
+		if len(batch) > 0 && (len(batch) >= maxInputs || batchTokens+tokens[i] > maxTokens) {
+			batches = append(batches, batch)
+			batch, batchTokens = nil, 0
+		}
+		batch = append(batch, input)
+		batchTokens += tokens[i]
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func ExecuteEmbeddingsRequest(ctx context.Context, config ChatCompletionsConfig, requestBody map[string]any) (EmbeddingsResponse, int, error) {
+	url := config.BaseURL + config.Endpoint
+	headers := config.Headers()
+
+	requestCtx, cancel := transport.CreateRequestContext(ctx, transport.DefaultTimeout)
+	defer cancel()
+
+	req, err := transport.CreateJSONRequest(requestCtx, "POST", url, requestBody, headers)
+	if err != nil {
+		return EmbeddingsResponse{}, 0, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
+	if err != nil {
+		return EmbeddingsResponse{}, 0, err
+	}
+
+	statusCode := resp.StatusCode
+	bodyBytes, err := transport.ReadResponseBody(resp)
+	if err != nil {
+		return EmbeddingsResponse{}, statusCode, err
+	}
+
+	var responseBody EmbeddingsResponse
+	err = transport.DecodeJSONResponse(bodyBytes, &responseBody)
+	if err != nil && statusCode == http.StatusOK {
+		return EmbeddingsResponse{}, statusCode, fmt.Errorf("failed to decode response: %v", err)
+	}
+	responseBody.RequestID = resp.Header.Get("x-request-id")
+	responseBody.Attempts = attempts
+
+	return responseBody, statusCode, nil
+}
+
+func ParseEmbeddingsResponse(response EmbeddingsResponse, statusCode int) (types.EmbeddingResult, error) {
+	if statusCode != http.StatusOK || response.Error.Message != "" {
+		apiErr := types.NewAPIError(statusCode, response.Error.Type, response.Error.Code, response.Error.Message, response.RequestID)
+		apiErr.Attempts = response.Attempts
+		return types.EmbeddingResult{}, apiErr
+	}
+
+	if len(response.Data) == 0 {
+		return types.EmbeddingResult{}, fmt.Errorf("no embeddings in API response")
+	}
+
+	embeddings := make([]types.Embedding, len(response.Data))
+	for i, data := range response.Data {
+		vector, err := decodeEmbedding(data.Embedding)
+		if err != nil {
+			return types.EmbeddingResult{}, fmt.Errorf("failed to decode embedding %d: %v", data.Index, err)
+		}
+		embeddings[i] = types.NewEmbedding(data.Index, vector)
+	}
+
+	usage := types.NewTokenUsage(response.Usage.PromptTokens, 0, response.Usage.TotalTokens)
+	return types.NewEmbeddingResult(embeddings, usage).
+		WithModel(response.Model).
+		WithAttempts(response.Attempts), nil
+}
+
+func decodeEmbedding(raw json.RawMessage) ([]float64, error) {
+	var vector []float64
+	if err := json.Unmarshal(raw, &vector); err == nil {
+		return vector, nil
+	}
+
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, fmt.Errorf("expected a list of floats or a base64 string")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("base64 embedding has %d bytes, not a multiple of 4", len(data))
+	}
+	vector = make([]float64, len(data)/4)
+	for i := range vector {
+		vector[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+	}
+	return vector, nil
+}
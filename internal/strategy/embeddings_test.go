@@ -0,0 +1,102 @@
+package strategy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestBuildEmbeddingsRequestBody(t *testing.T) {
+	body := BuildEmbeddingsRequestBody(EmbeddingsRequest{
+		Model:         "text-embedding-3-small",
+		Input:         []string{"first", "second"},
+		RequestParams: map[string]any{"dimensions": 256},
+	})
+
+	if body["model"] != "text-embedding-3-small" || body["dimensions"] != 256 {
+		t.Errorf("unexpected request body: %v", body)
+	}
+	if input := body["input"].([]string); len(input) != 2 || input[1] != "second" {
+		t.Errorf("unexpected input: %v", body["input"])
+	}
+}
+
+func TestBatchEmbeddingInputs(t *testing.T) {
+	t.Run("splits by input count", func(t *testing.T) {
+		batches := BatchEmbeddingInputs([]string{"a", "b", "c", "d", "e"}, []int{1, 1, 1, 1, 1}, 2, 100)
+		if len(batches) != 3 || len(batches[0]) != 2 || len(batches[2]) != 1 {
+			t.Errorf("unexpected batches: %v", batches)
+		}
+	})
+
+	t.Run("splits by tokens", func(t *testing.T) {
+		batches := BatchEmbeddingInputs([]string{"a", "b", "c", "d"}, []int{4, 4, 3, 20}, 10, 10)
+		if len(batches) != 3 || len(batches[0]) != 2 || len(batches[1]) != 1 || len(batches[2]) != 1 {
+			t.Errorf("unexpected batches: %v", batches)
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		if batches := BatchEmbeddingInputs(nil, nil, 10, 10); len(batches) != 0 {
+			t.Errorf("expected no batches, got %v", batches)
+		}
+	})
+}
+
+func TestParseEmbeddingsResponse(t *testing.T) {
+	t.Run("float vectors", func(t *testing.T) {
+		var response EmbeddingsResponse
+		err := json.Unmarshal([]byte(`{"data":[{"index":0,"embedding":[0.5,-0.25]},{"index":1,"embedding":[1,0]}],"model":"text-embedding-3-small","usage":{"prompt_tokens":6,"total_tokens":6}}`), &response)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		result, err := ParseEmbeddingsResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		vectors := result.Vectors()
+		if len(vectors) != 2 || vectors[0][0] != 0.5 || vectors[0][1] != -0.25 {
+			t.Errorf("unexpected vectors: %v", vectors)
+		}
+		if result.Usage().PromptTokens() != 6 || result.Model() != "text-embedding-3-small" {
+			t.Errorf("unexpected metadata: usage=%+v model=%s", result.Usage(), result.Model())
+		}
+	})
+
+	t.Run("base64 vectors", func(t *testing.T) {
+		var response EmbeddingsResponse
+		err := json.Unmarshal([]byte(`{"data":[{"index":0,"embedding":"AAAAPwAAgL4="}],"usage":{"prompt_tokens":1,"total_tokens":1}}`), &response)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		result, err := ParseEmbeddingsResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if vector := result.Vectors()[0]; len(vector) != 2 || vector[0] != 0.5 || vector[1] != -0.25 {
+			t.Errorf("unexpected decoded vector: %v", vector)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		response := EmbeddingsResponse{Error: ChatCompletionsError{Message: "too many tokens", Type: "invalid_request_error"}}
+
+		_, err := ParseEmbeddingsResponse(response, http.StatusBadRequest)
+		var apiErr *types.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected APIError with status 400, got %v", err)
+		}
+	})
+
+	t.Run("empty data", func(t *testing.T) {
+		_, err := ParseEmbeddingsResponse(EmbeddingsResponse{}, http.StatusOK)
+		if err == nil || err.Error() != "no embeddings in API response" {
+			t.Errorf("expected 'no embeddings in API response', got %v", err)
+		}
+	})
+}
@@ -0,0 +1,392 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"agentic-ai-framework/internal/transport"
+	"agentic-ai-framework/internal/types"
+)
+
+const DefaultMessagesMaxTokens = 4096
+
+type MessagesRequest struct {
+	Model         string
+	System        string
+	Messages      []ChatMessage
+	RequestParams map[string]any
+	Stream        bool
+	Tools         []ChatTool
+	ToolChoice    any
+}
+
+type MessagesResponse struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Model      string                 `json:"model"`
+	Content    []MessagesContentBlock `json:"content"`
+	StopReason string                 `json:"stop_reason"`
+	Usage      MessagesUsage          `json:"usage"`
+	Error      MessagesError          `json:"error"`
+	RequestID  string                 `json:"-"`
+	Attempts   int                    `json:"-"`
+	Raw        json.RawMessage        `json:"-"`
+}
+
+type MessagesContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+type MessagesUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+type MessagesError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type MessagesStreamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		ID    string        `json:"id"`
+		Model string        `json:"model"`
+		Usage MessagesUsage `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage MessagesUsage `json:"usage"`
+	Error MessagesError `json:"error"`
+}
+
+type MessagesConfig struct {
+	BaseURL     string
+	Endpoint    string
+	APIKey      string
+	Version     string
+	HTTPClient  *http.Client
+	RetryPolicy transport.RetryPolicy
+}
+
+func BuildMessagesRequestBody(req MessagesRequest) map[string]any {
+	requestBody := map[string]any{
+		"model":      req.Model,
+		"messages":   buildMessagesList(req.Messages),
+		"max_tokens": DefaultMessagesMaxTokens,
+	}
+
+	if req.System != "" {
+		requestBody["system"] = req.System
+	}
+
+	if len(req.Tools) > 0 {
+		tools := make([]map[string]any, len(req.Tools))
+		for i, tool := range req.Tools {
+			tools[i] = map[string]any{
+				"name":         tool.Name,
+				"input_schema": tool.Parameters,
+			}
+			if tool.Description != "" {
+				tools[i]["description"] = tool.Description
+			}
+		}
+		requestBody["tools"] = tools
+	}
+
+	if req.ToolChoice != nil {
+		requestBody["tool_choice"] = req.ToolChoice
+	}
+
+	for key, value := range req.RequestParams {
+		requestBody[key] = value
+	}
+
+	if req.Stream {
+		requestBody["stream"] = true
+	}
+
+	return requestBody
+}
+
+func buildMessagesList(chatMessages []ChatMessage) []map[string]any {
+	var messages []map[string]any
+	for _, msg := range chatMessages {
+		role := msg.Role
+		blocks := []map[string]any{}
+
+		switch msg.Role {
+		case "tool":
+			role = "user"
+			blocks = append(blocks, map[string]any{
+				"type":        "tool_result",
+				"tool_use_id": msg.ToolCallID,
+				"content":     msg.Content,
+			})
+		default:
+			if len(msg.Parts) > 0 {
+				blocks = append(blocks, buildMessagesContentParts(msg.Parts)...)
+			} else if msg.Content != "" {
+				blocks = append(blocks, map[string]any{"type": "text", "text": msg.Content})
+			}
+			for _, call := range msg.ToolCalls {
+				input := json.RawMessage(call.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, map[string]any{
+					"type":  "tool_use",
+					"id":    call.ID,
+					"name":  call.Name,
+					"input": input,
+				})
+			}
+		}
+
+		if role == "assistant" && len(blocks) == 0 {
+			continue
+		}
+		if last := len(messages) - 1; last >= 0 && messages[last]["role"] == role {
+			messages[last]["content"] = append(messages[last]["content"].([]map[string]any), blocks...)
+			continue
+		}
+		messages = append(messages, map[string]any{
+			"role":    role,
+			"content": blocks,
+		})
+	}
+	return messages
+}
+
+func buildMessagesContentParts(parts []types.ContentPart) []map[string]any {
+	blocks := make([]map[string]any, len(parts))
+	for i, part := range parts {
+		switch part.Type() {
+		case types.ContentPartImage, types.ContentPartFile:
+			blockType := "image"
+			if part.Type() == types.ContentPartFile {
+				blockType = "document"
+			}
+			source := map[string]any{"type": "base64", "media_type": part.MimeType(), "data": part.Base64()}
+			if part.URL() != "" {
+				source = map[string]any{"type": "url", "url": part.URL()}
+			}
+			blocks[i] = map[string]any{"type": blockType, "source": source}
+		default:
+			blocks[i] = map[string]any{"type": "text", "text": part.Text()}
+		}
+	}
+	return blocks
+}
+
+func ExecuteMessagesRequest(ctx context.Context, config MessagesConfig, requestBody map[string]any) (MessagesResponse, int, error) {
+	url := config.BaseURL + config.Endpoint
+
+	requestCtx, cancel := transport.CreateRequestContext(ctx, transport.DefaultTimeout)
+	defer cancel()
+
+	req, err := transport.CreateJSONRequest(requestCtx, "POST", url, requestBody, messagesHeaders(config))
+	if err != nil {
+		return MessagesResponse{}, 0, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
+	if err != nil {
+		return MessagesResponse{}, 0, err
+	}
+
+	statusCode := resp.StatusCode
+	bodyBytes, err := transport.ReadResponseBody(resp)
+	if err != nil {
+		return MessagesResponse{}, statusCode, err
+	}
+
+	var responseBody MessagesResponse
+	err = transport.DecodeJSONResponse(bodyBytes, &responseBody)
+	if err != nil && statusCode == http.StatusOK {
+		return MessagesResponse{}, statusCode, fmt.Errorf("failed to decode response: %v", err)
+	}
+	responseBody.RequestID = resp.Header.Get("request-id")
+	responseBody.Attempts = attempts
+	responseBody.Raw = bodyBytes
+
+	return responseBody, statusCode, nil
+}
+
+func messagesHeaders(config MessagesConfig) map[string]string {
+	return map[string]string{
+		"x-api-key":         config.APIKey,
+		"anthropic-version": config.Version,
+	}
+}
+
+func ParseMessagesResponse(response MessagesResponse, statusCode int) (types.GenerateTextResult, error) {
+	if statusCode != http.StatusOK || response.Error.Message != "" {
+		apiErr := types.NewAPIError(statusCode, response.Error.Type, "", response.Error.Message, response.RequestID)
+		apiErr.Attempts = response.Attempts
+		return types.GenerateTextResult{}, apiErr
+	}
+
+	var text strings.Builder
+	var toolCalls []types.ToolCall
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			toolCalls = append(toolCalls, types.NewToolCall(block.ID, block.Name, string(block.Input)))
+		}
+	}
+
+	finishReason := messagesFinishReason(response.StopReason)
+	result := types.NewGenerateTextResult(text.String(), messagesUsage(response.Usage, response.Usage.OutputTokens)).
+		WithAttempts(response.Attempts).
+		WithFinishReason(finishReason).
+		WithResponseID(response.ID).
+		WithModel(response.Model).
+		WithChoices([]types.Choice{types.NewChoice(0, text.String(), finishReason, toolCalls, "")}).
+		WithRawResponse(response.Raw)
+	if len(toolCalls) > 0 {
+		result = result.WithToolCalls(toolCalls)
+	}
+
+	return result, nil
+}
+
+func messagesUsage(usage MessagesUsage, outputTokens int) types.TokenUsage {
+	inputTokens := usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
+	return types.NewTokenUsage(inputTokens, outputTokens, inputTokens+outputTokens).
+		WithCachedTokens(usage.CacheReadInputTokens)
+}
+
+func messagesFinishReason(stopReason string) string {
+	switch stopReason {
+	case "end_turn", "stop_sequence":
+		return types.FinishReasonStop
+	case "max_tokens":
+		return types.FinishReasonLength
+	case "tool_use":
+		return types.FinishReasonToolCalls
+	case "refusal":
+		return types.FinishReasonContentFilter
+	default:
+		return stopReason
+	}
+}
+
+func ExecuteMessagesStreamRequest(ctx context.Context, config MessagesConfig, requestBody map[string]any) (<-chan types.StreamChunk, error) {
+	url := config.BaseURL + config.Endpoint
+	headers := messagesHeaders(config)
+	headers["Accept"] = "text/event-stream"
+
+	requestCtx, headersReceived, cancel := transport.CreateStreamContext(ctx, transport.DefaultTimeout)
+
+	req, err := transport.CreateJSONRequest(requestCtx, "POST", url, requestBody, headers)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
+	headersReceived()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer cancel()
+		bodyBytes, err := transport.ReadResponseBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		var responseBody MessagesResponse
+		transport.DecodeJSONResponse(bodyBytes, &responseBody)
+		responseBody.RequestID = resp.Header.Get("request-id")
+		responseBody.Attempts = attempts
+		_, err = ParseMessagesResponse(responseBody, resp.StatusCode)
+		return nil, err
+	}
+
+	chunks := make(chan types.StreamChunk)
+	go func() {
+		defer cancel()
+		defer resp.Body.Close()
+		defer close(chunks)
+		parseMessagesStream(requestCtx, resp.Body, chunks, attempts)
+	}()
+
+	return chunks, nil
+}
+
+func ParseMessagesStream(ctx context.Context, body io.Reader, chunks chan<- types.StreamChunk) {
+	parseMessagesStream(ctx, body, chunks, 0)
+}
+
+func parseMessagesStream(ctx context.Context, body io.Reader, chunks chan<- types.StreamChunk, attempts int) {
+	var text strings.Builder
+	var usage MessagesUsage
+	var outputTokens int
+	var responseID, model, stopReason string
+	done := false
+
+	err := transport.ReadEventStream(body, func(data string) error {
+		var event MessagesStreamEvent
+		if err := transport.DecodeJSONResponse([]byte(data), &event); err != nil {
+			return fmt.Errorf("failed to decode stream event: %v", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			usage = event.Message.Usage
+			outputTokens = event.Message.Usage.OutputTokens
+			responseID, model = event.Message.ID, event.Message.Model
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+				text.WriteString(event.Delta.Text)
+				if err := sendStreamChunk(ctx, chunks, types.NewStreamDelta(event.Delta.Text)); err != nil {
+					return err
+				}
+			}
+		case "message_delta":
+			outputTokens = event.Usage.OutputTokens
+			if event.Delta.StopReason != "" {
+				stopReason = event.Delta.StopReason
+			}
+		case "message_stop":
+			done = true
+			return transport.ErrStreamDone
+		case "error":
+			return types.NewAPIError(0, event.Error.Type, "", event.Error.Message, "")
+		}
+		return nil
+	})
+	if err != nil {
+		sendStreamChunk(ctx, chunks, types.NewStreamError(err))
+		return
+	}
+	if !done {
+		sendStreamChunk(ctx, chunks, types.NewStreamError(fmt.Errorf("stream ended before message_stop event")))
+		return
+	}
+
+	result := types.NewGenerateTextResult(text.String(), messagesUsage(usage, outputTokens)).
+		WithAttempts(attempts).
+		WithFinishReason(messagesFinishReason(stopReason)).
+		WithResponseID(responseID).
+		WithModel(model)
+	sendStreamChunk(ctx, chunks, types.NewStreamResult(result))
+}
@@ -0,0 +1,325 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestBuildMessagesRequestBody(t *testing.T) {
+	req := MessagesRequest{
+		Model:  "claude-sonnet-4-5",
+		System: "You are a helpful assistant",
+		Messages: []ChatMessage{
+			{Role: "user", Content: "Weather in Paris?"},
+			{Role: "assistant", Content: "Let me check.", ToolCalls: []ChatToolCall{{ID: "toolu_1", Name: "get_weather", Arguments: `{"city":"Paris"}`}}},
+			{Role: "tool", Content: "sunny", ToolCallID: "toolu_1"},
+		},
+		Tools: []ChatTool{
+			{Name: "get_weather", Description: "Get the weather", Parameters: map[string]any{"type": "object"}},
+		},
+		RequestParams: map[string]any{
+			"temperature": 0.2,
+		},
+	}
+
+	body := BuildMessagesRequestBody(req)
+
+	if body["system"] != "You are a helpful assistant" {
+		t.Errorf("expected top-level system, got %v", body["system"])
+	}
+	if body["max_tokens"] != DefaultMessagesMaxTokens {
+		t.Errorf("expected default max_tokens %d, got %v", DefaultMessagesMaxTokens, body["max_tokens"])
+	}
+	if body["temperature"] != 0.2 {
+		t.Errorf("expected temperature 0.2, got %v", body["temperature"])
+	}
+
+	messages := body["messages"].([]map[string]any)
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+	assistantBlocks := messages[1]["content"].([]map[string]any)
+	if len(assistantBlocks) != 2 || assistantBlocks[1]["type"] != "tool_use" || assistantBlocks[1]["id"] != "toolu_1" {
+		t.Errorf("unexpected assistant content blocks: %v", assistantBlocks)
+	}
+	if messages[2]["role"] != "user" {
+		t.Errorf("expected tool result to be sent as user message, got %v", messages[2]["role"])
+	}
+	resultBlocks := messages[2]["content"].([]map[string]any)
+	if resultBlocks[0]["type"] != "tool_result" || resultBlocks[0]["tool_use_id"] != "toolu_1" {
+		t.Errorf("unexpected tool result block: %v", resultBlocks[0])
+	}
+
+	tools := body["tools"].([]map[string]any)
+	if tools[0]["name"] != "get_weather" || tools[0]["input_schema"] == nil {
+		t.Errorf("unexpected tool definition: %v", tools[0])
+	}
+}
+
+func TestBuildMessagesRequestBodyMergesConsecutiveRoles(t *testing.T) {
+	req := MessagesRequest{
+		Model: "claude-sonnet-4-5",
+		Messages: []ChatMessage{
+			{Role: "assistant", ToolCalls: []ChatToolCall{{ID: "a", Name: "one", Arguments: "{}"}, {ID: "b", Name: "two", Arguments: "{}"}}},
+			{Role: "tool", Content: "1", ToolCallID: "a"},
+			{Role: "tool", Content: "2", ToolCallID: "b"},
+		},
+		RequestParams: map[string]any{"max_tokens": 100},
+	}
+
+	body := BuildMessagesRequestBody(req)
+
+	messages := body["messages"].([]map[string]any)
+	if len(messages) != 2 {
+		t.Fatalf("expected tool results to be merged into 2 messages, got %d", len(messages))
+	}
+	if blocks := messages[1]["content"].([]map[string]any); len(blocks) != 2 {
+		t.Errorf("expected 2 tool result blocks, got %d", len(blocks))
+	}
+	if body["max_tokens"] != 100 {
+		t.Errorf("expected max_tokens override 100, got %v", body["max_tokens"])
+	}
+}
+
+func TestBuildMessagesRequestBodyEmptyAssistantMessage(t *testing.T) {
+	body := BuildMessagesRequestBody(MessagesRequest{
+		Model:    "claude-sonnet-4-5",
+		Messages: []ChatMessage{{Role: "user", Content: "Hi"}, {Role: "assistant"}, {Role: "user", Content: "Still there?"}, {Role: "assistant"}},
+	})
+
+	encoded, err := json.Marshal(body["messages"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `[{"content":[{"text":"Hi","type":"text"},{"text":"Still there?","type":"text"}],"role":"user"}]`
+	if string(encoded) != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+}
+
+func TestBuildMessagesRequestBodyContentParts(t *testing.T) {
+	req := MessagesRequest{
+		Model: "claude-sonnet-4-5",
+		Messages: []ChatMessage{{
+			Role:    "user",
+			Content: "Summarize.",
+			Parts: []types.ContentPart{
+				types.NewImagePart([]byte("img"), "image/png"),
+				types.NewImageURLPart("https://example.com/page-2.png"),
+				types.NewFilePart("report.pdf", []byte("%PDF"), "application/pdf"),
+				types.NewTextPart("Summarize."),
+			},
+		}},
+	}
+
+	body := BuildMessagesRequestBody(req)
+
+	blocks := body["messages"].([]map[string]any)[0]["content"].([]map[string]any)
+	if len(blocks) != 4 {
+		t.Fatalf("expected 4 content blocks, got %v", blocks)
+	}
+	source := blocks[0]["source"].(map[string]any)
+	if blocks[0]["type"] != "image" || source["type"] != "base64" || source["media_type"] != "image/png" || source["data"] != "aW1n" {
+		t.Errorf("unexpected inline image block: %v", blocks[0])
+	}
+	if source := blocks[1]["source"].(map[string]any); source["type"] != "url" || source["url"] != "https://example.com/page-2.png" {
+		t.Errorf("unexpected image URL block: %v", blocks[1])
+	}
+	if blocks[2]["type"] != "document" || blocks[2]["source"].(map[string]any)["media_type"] != "application/pdf" {
+		t.Errorf("unexpected document block: %v", blocks[2])
+	}
+	if blocks[3]["type"] != "text" || blocks[3]["text"] != "Summarize." {
+		t.Errorf("unexpected text block: %v", blocks[3])
+	}
+}
+
+func TestParseMessagesResponse(t *testing.T) {
+	t.Run("successful parsing", func(t *testing.T) {
+		var response MessagesResponse
+		err := json.Unmarshal([]byte(`{"id":"msg_1","type":"message","content":[{"type":"text","text":"Checking."},{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{"city":"Paris"}}],"stop_reason":"tool_use","usage":{"input_tokens":12,"output_tokens":8}}`), &response)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		result, err := ParseMessagesResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.TextContent() != "Checking." {
+			t.Errorf("expected 'Checking.', got '%s'", result.TextContent())
+		}
+		if result.Usage().PromptTokens() != 12 || result.Usage().CompletionTokens() != 8 || result.Usage().TotalTokens() != 20 {
+			t.Errorf("unexpected usage: %+v", result.Usage())
+		}
+		toolCalls := result.ToolCalls()
+		if len(toolCalls) != 1 || toolCalls[0].Arguments() != `{"city":"Paris"}` {
+			t.Errorf("unexpected tool calls: %v", toolCalls)
+		}
+		if result.FinishReason() != types.FinishReasonToolCalls {
+			t.Errorf("expected finish reason 'tool_calls', got '%s'", result.FinishReason())
+		}
+		if result.ResponseID() != "msg_1" {
+			t.Errorf("expected response id 'msg_1', got '%s'", result.ResponseID())
+		}
+	})
+
+	t.Run("response metadata", func(t *testing.T) {
+		raw := []byte(`{"id":"msg_2","type":"message","model":"claude-sonnet-4-5-20250929","content":[{"type":"text","text":"Once upon"}],"stop_reason":"max_tokens","usage":{"input_tokens":12,"output_tokens":8,"cache_read_input_tokens":10}}`)
+		var response MessagesResponse
+		if err := json.Unmarshal(raw, &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		response.Raw = raw
+
+		result, err := ParseMessagesResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !result.Truncated() {
+			t.Errorf("expected max_tokens to be reported as truncated, got '%s'", result.FinishReason())
+		}
+		if result.Model() != "claude-sonnet-4-5-20250929" {
+			t.Errorf("unexpected model: %s", result.Model())
+		}
+		if result.Usage().CachedTokens() != 10 || result.Usage().PromptTokens() != 22 {
+			t.Errorf("expected 22 prompt tokens including 10 cached, got %d and %d", result.Usage().PromptTokens(), result.Usage().CachedTokens())
+		}
+		if len(result.Choices()) != 1 || result.Choices()[0].TextContent() != "Once upon" {
+			t.Errorf("unexpected choices: %+v", result.Choices())
+		}
+		if string(result.RawResponse()) != string(raw) {
+			t.Errorf("expected raw response to be preserved, got %s", result.RawResponse())
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		response := MessagesResponse{
+			Type:  "error",
+			Error: MessagesError{Type: "authentication_error", Message: "invalid x-api-key"},
+		}
+
+		_, err := ParseMessagesResponse(response, http.StatusUnauthorized)
+		if err == nil {
+			t.Fatal("expected error for API error")
+		}
+		expected := "API error (status 401): invalid x-api-key (type: authentication_error)"
+		if err.Error() != expected {
+			t.Errorf("expected '%s', got '%s'", expected, err.Error())
+		}
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		response := MessagesResponse{
+			ID:         "msg_2",
+			Content:    []MessagesContentBlock{},
+			StopReason: "end_turn",
+			Usage:      MessagesUsage{InputTokens: 12, OutputTokens: 1},
+		}
+		result, err := ParseMessagesResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.TextContent() != "" || len(result.ToolCalls()) != 0 {
+			t.Errorf("expected an empty result, got '%s' and %v", result.TextContent(), result.ToolCalls())
+		}
+		if result.FinishReason() != types.FinishReasonStop || result.Usage().TotalTokens() != 13 || result.ResponseID() != "msg_2" {
+			t.Errorf("expected stop reason, usage and ID to be kept, got %s, %d, %s", result.FinishReason(), result.Usage().TotalTokens(), result.ResponseID())
+		}
+	})
+}
+
+func TestParseMessagesStream(t *testing.T) {
+	body := `event: message_start
+data: {"type":"message_start","message":{"id":"msg_3","model":"claude-haiku-4-5","usage":{"input_tokens":7,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":4}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+	chunks := make(chan types.StreamChunk)
+	go func() {
+		defer close(chunks)
+		ParseMessagesStream(context.Background(), strings.NewReader(body), chunks)
+	}()
+
+	var deltas []string
+	var final types.StreamChunk
+	for chunk := range chunks {
+		if chunk.Err() != nil {
+			t.Fatalf("unexpected stream error: %v", chunk.Err())
+		}
+		if chunk.Done() {
+			final = chunk
+			continue
+		}
+		deltas = append(deltas, chunk.TextDelta())
+	}
+
+	if strings.Join(deltas, "|") != "Hel|lo" {
+		t.Errorf("unexpected deltas: %v", deltas)
+	}
+	result := final.Result()
+	if result.TextContent() != "Hello" {
+		t.Errorf("expected 'Hello', got '%s'", result.TextContent())
+	}
+	if result.Usage().PromptTokens() != 7 || result.Usage().CompletionTokens() != 4 || result.Usage().TotalTokens() != 11 {
+		t.Errorf("unexpected usage: %+v", result.Usage())
+	}
+	if result.FinishReason() != types.FinishReasonStop || result.ResponseID() != "msg_3" || result.Model() != "claude-haiku-4-5" {
+		t.Errorf("unexpected metadata: finish=%s id=%s model=%s", result.FinishReason(), result.ResponseID(), result.Model())
+	}
+}
+
+func TestExecuteMessagesRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("expected path /v1/messages, got %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "key" {
+			t.Errorf("expected x-api-key header, got '%s'", r.Header.Get("x-api-key"))
+		}
+		if r.Header.Get("anthropic-version") != "2023-06-01" {
+			t.Errorf("expected anthropic-version header, got '%s'", r.Header.Get("anthropic-version"))
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no Authorization header, got '%s'", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"content":[{"type":"text","text":"Hi"}],"usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer server.Close()
+
+	cfg := MessagesConfig{BaseURL: server.URL + "/v1", Endpoint: "/messages", APIKey: "key", Version: "2023-06-01", HTTPClient: server.Client()}
+	response, statusCode, err := ExecuteMessagesRequest(context.Background(), cfg, map[string]any{"model": "claude-sonnet-4-5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", statusCode)
+	}
+	if len(response.Content) != 1 || response.Content[0].Text != "Hi" {
+		t.Errorf("unexpected response content: %+v", response.Content)
+	}
+}
@@ -0,0 +1,391 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"agentic-ai-framework/internal/transport"
+	"agentic-ai-framework/internal/types"
+)
+
+var ollamaTopLevelParams = map[string]bool{
+	"format":     true,
+	"keep_alive": true,
+}
+
+type OllamaChatRequest struct {
+	Model         string
+	Messages      []ChatMessage
+	RequestParams map[string]any
+	Stream        bool
+	Tools         []ChatTool
+}
+
+type OllamaChatResponse struct {
+	Model           string            `json:"model"`
+	CreatedAt       time.Time         `json:"created_at"`
+	Message         OllamaChatMessage `json:"message"`
+	Done            bool              `json:"done"`
+	DoneReason      string            `json:"done_reason"`
+	PromptEvalCount int               `json:"prompt_eval_count"`
+	EvalCount       int               `json:"eval_count"`
+	Error           string            `json:"error"`
+	Attempts        int               `json:"-"`
+	Raw             json.RawMessage   `json:"-"`
+}
+
+type OllamaChatMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []OllamaToolCall `json:"tool_calls"`
+}
+
+type OllamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type OllamaTagsResponse struct {
+	Models []OllamaModelInfo `json:"models"`
+	Error  string            `json:"error"`
+}
+
+type OllamaModelInfo struct {
+	Name    string `json:"name"`
+	Model   string `json:"model"`
+	Size    int64  `json:"size"`
+	Details struct {
+		Family            string   `json:"family"`
+		Families          []string `json:"families"`
+		ParameterSize     string   `json:"parameter_size"`
+		QuantizationLevel string   `json:"quantization_level"`
+	} `json:"details"`
+}
+
+const OllamaDefaultContextLength = 4096
+
+type OllamaShowResponse struct {
+	Parameters   string         `json:"parameters"`
+	ModelInfo    map[string]any `json:"model_info"`
+	Capabilities []string       `json:"capabilities"`
+}
+
+func (r OllamaShowResponse) ContextLength(defaultLength int) int {
+	for _, line := range strings.Split(r.Parameters, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "num_ctx" {
+			if numCtx, err := strconv.Atoi(fields[1]); err == nil && numCtx > 0 {
+				return numCtx
+			}
+		}
+	}
+	if trained := r.TrainedContextLength(); trained > 0 && trained < defaultLength {
+		return trained
+	}
+	return defaultLength
+}
+
+func (r OllamaShowResponse) TrainedContextLength() int {
+	architecture, _ := r.ModelInfo["general.architecture"].(string)
+	if length, ok := r.ModelInfo[architecture+".context_length"].(float64); ok {
+		return int(length)
+	}
+	return 0
+}
+
+type OllamaConfig struct {
+	BaseURL     string
+	HTTPClient  *http.Client
+	RetryPolicy transport.RetryPolicy
+}
+
+func BuildOllamaChatRequestBody(req OllamaChatRequest) map[string]any {
+	messages := make([]map[string]any, len(req.Messages))
+	for i, msg := range req.Messages {
+		message := map[string]any{
+			"role":    msg.Role,
+			"content": msg.Content,
+		}
+		var images []string
+		for _, part := range msg.Parts {
+			if part.Type() == types.ContentPartImage && part.URL() == "" {
+				images = append(images, part.Base64())
+			}
+		}
+		if len(images) > 0 {
+			message["images"] = images
+		}
+		if len(msg.ToolCalls) > 0 {
+			toolCalls := make([]map[string]any, len(msg.ToolCalls))
+			for j, call := range msg.ToolCalls {
+				arguments := json.RawMessage(call.Arguments)
+				if !json.Valid(arguments) {
+					arguments = json.RawMessage("{}")
+				}
+				toolCalls[j] = map[string]any{
+					"function": map[string]any{
+						"name":      call.Name,
+						"arguments": arguments,
+					},
+				}
+			}
+			message["tool_calls"] = toolCalls
+		}
+		messages[i] = message
+	}
+
+	requestBody := map[string]any{
+		"model":    req.Model,
+		"messages": messages,
+		"stream":   req.Stream,
+	}
+
+	if len(req.Tools) > 0 {
+		tools := make([]map[string]any, len(req.Tools))
+		for i, tool := range req.Tools {
+			tools[i] = map[string]any{
+				"type": "function",
+				"function": map[string]any{
+					"name":        tool.Name,
+					"description": tool.Description,
+					"parameters":  tool.Parameters,
+				},
+			}
+		}
+		requestBody["tools"] = tools
+	}
+
+	options := map[string]any{}
+	for key, value := range req.RequestParams {
+		if format, ok := value.(types.ResponseFormat); ok {
+			value = buildOllamaFormat(format)
+		}
+		if ollamaTopLevelParams[key] {
+			requestBody[key] = value
+			continue
+		}
+		options[key] = value
+	}
+	if len(options) > 0 {
+		requestBody["options"] = options
+	}
+
+	return requestBody
+}
+
+func buildOllamaFormat(format types.ResponseFormat) any {
+	if format.Type() == types.ResponseFormatJSONSchema {
+		return format.Schema()
+	}
+	return "json"
+}
+
+func ExecuteOllamaChatRequest(ctx context.Context, config OllamaConfig, requestBody map[string]any) (OllamaChatResponse, int, error) {
+	requestCtx, cancel := transport.CreateRequestContext(ctx, transport.DefaultTimeout)
+	defer cancel()
+
+	req, err := transport.CreateJSONRequest(requestCtx, "POST", config.BaseURL+"/api/chat", requestBody, nil)
+	if err != nil {
+		return OllamaChatResponse{}, 0, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
+	if err != nil {
+		return OllamaChatResponse{}, 0, err
+	}
+
+	statusCode := resp.StatusCode
+	bodyBytes, err := transport.ReadResponseBody(resp)
+	if err != nil {
+		return OllamaChatResponse{}, statusCode, err
+	}
+
+	var responseBody OllamaChatResponse
+	err = transport.DecodeJSONResponse(bodyBytes, &responseBody)
+	if err != nil && statusCode == http.StatusOK {
+		return OllamaChatResponse{}, statusCode, fmt.Errorf("failed to decode response: %v", err)
+	}
+	responseBody.Attempts = attempts
+	responseBody.Raw = bodyBytes
+
+	return responseBody, statusCode, nil
+}
+
+func ParseOllamaChatResponse(response OllamaChatResponse, statusCode int) (types.GenerateTextResult, error) {
+	if statusCode != http.StatusOK || response.Error != "" {
+		apiErr := types.NewAPIError(statusCode, "", "", response.Error, "")
+		apiErr.Attempts = response.Attempts
+		return types.GenerateTextResult{}, apiErr
+	}
+
+	usage := types.NewTokenUsage(
+		response.PromptEvalCount,
+		response.EvalCount,
+		response.PromptEvalCount+response.EvalCount,
+	)
+
+	var toolCalls []types.ToolCall
+	for i, call := range response.Message.ToolCalls {
+		toolCalls = append(toolCalls, types.NewToolCall(fmt.Sprintf("call_%d", i), call.Function.Name, string(call.Function.Arguments)))
+	}
+
+	result := ollamaResultMetadata(types.NewGenerateTextResult(response.Message.Content, usage), response).
+		WithAttempts(response.Attempts).
+		WithChoices([]types.Choice{types.NewChoice(0, response.Message.Content, response.DoneReason, toolCalls, "")}).
+		WithRawResponse(response.Raw)
+	if len(toolCalls) > 0 {
+		result = result.WithToolCalls(toolCalls)
+	}
+
+	return result, nil
+}
+
+func ollamaResultMetadata(result types.GenerateTextResult, response OllamaChatResponse) types.GenerateTextResult {
+	return result.
+		WithFinishReason(response.DoneReason).
+		WithModel(response.Model).
+		WithCreated(response.CreatedAt)
+}
+
+func ExecuteOllamaChatStreamRequest(ctx context.Context, config OllamaConfig, requestBody map[string]any) (<-chan types.StreamChunk, error) {
+	requestCtx, headersReceived, cancel := transport.CreateStreamContext(ctx, transport.DefaultTimeout)
+
+	req, err := transport.CreateJSONRequest(requestCtx, "POST", config.BaseURL+"/api/chat", requestBody, map[string]string{
+		"Accept": "application/x-ndjson",
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
+	headersReceived()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer cancel()
+		bodyBytes, err := transport.ReadResponseBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		var responseBody OllamaChatResponse
+		transport.DecodeJSONResponse(bodyBytes, &responseBody)
+		responseBody.Attempts = attempts
+		_, err = ParseOllamaChatResponse(responseBody, resp.StatusCode)
+		return nil, err
+	}
+
+	chunks := make(chan types.StreamChunk)
+	go func() {
+		defer cancel()
+		defer resp.Body.Close()
+		defer close(chunks)
+		parseOllamaChatStream(requestCtx, resp.Body, chunks, attempts)
+	}()
+
+	return chunks, nil
+}
+
+func ParseOllamaChatStream(ctx context.Context, body io.Reader, chunks chan<- types.StreamChunk) {
+	parseOllamaChatStream(ctx, body, chunks, 0)
+}
+
+func parseOllamaChatStream(ctx context.Context, body io.Reader, chunks chan<- types.StreamChunk, attempts int) {
+	var text strings.Builder
+	var final *OllamaChatResponse
+
+	err := transport.ReadLineStream(body, func(line []byte) error {
+		var chunk OllamaChatResponse
+		if err := transport.DecodeJSONResponse(line, &chunk); err != nil {
+			return fmt.Errorf("failed to decode stream chunk: %v", err)
+		}
+		if chunk.Error != "" {
+			return types.NewAPIError(0, "", "", chunk.Error, "")
+		}
+		if chunk.Message.Content != "" {
+			text.WriteString(chunk.Message.Content)
+			if err := sendStreamChunk(ctx, chunks, types.NewStreamDelta(chunk.Message.Content)); err != nil {
+				return err
+			}
+		}
+		if chunk.Done {
+			final = &chunk
+			return transport.ErrStreamDone
+		}
+		return nil
+	})
+	if err != nil {
+		sendStreamChunk(ctx, chunks, types.NewStreamError(err))
+		return
+	}
+	if final == nil {
+		sendStreamChunk(ctx, chunks, types.NewStreamError(fmt.Errorf("stream ended before done message")))
+		return
+	}
+
+	usage := types.NewTokenUsage(final.PromptEvalCount, final.EvalCount, final.PromptEvalCount+final.EvalCount)
+	result := ollamaResultMetadata(types.NewGenerateTextResult(text.String(), usage).WithAttempts(attempts), *final)
+	sendStreamChunk(ctx, chunks, types.NewStreamResult(result))
+}
+
+func ExecuteOllamaTagsRequest(ctx context.Context, config OllamaConfig) (OllamaTagsResponse, error) {
+	var responseBody OllamaTagsResponse
+	if err := executeOllamaJSONRequest(ctx, config, "GET", "/api/tags", nil, &responseBody); err != nil {
+		return OllamaTagsResponse{}, err
+	}
+	return responseBody, nil
+}
+
+func ExecuteOllamaShowRequest(ctx context.Context, config OllamaConfig, modelName string) (OllamaShowResponse, error) {
+	var responseBody OllamaShowResponse
+	if err := executeOllamaJSONRequest(ctx, config, "POST", "/api/show", map[string]any{"model": modelName}, &responseBody); err != nil {
+		return OllamaShowResponse{}, err
+	}
+	return responseBody, nil
+}
+
+func executeOllamaJSONRequest(ctx context.Context, config OllamaConfig, method, path string, body any, target any) error {
+	requestCtx, cancel := transport.CreateRequestContext(ctx, transport.DefaultTimeout)
+	defer cancel()
+
+	req, err := transport.CreateJSONRequest(requestCtx, method, config.BaseURL+path, body, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, attempts, err := transport.ExecuteRequestWithRetry(config.HTTPClient, req, config.RetryPolicy)
+	if err != nil {
+		return err
+	}
+
+	statusCode := resp.StatusCode
+	bodyBytes, err := transport.ReadResponseBody(resp)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		var errorBody struct {
+			Error string `json:"error"`
+		}
+		transport.DecodeJSONResponse(bodyBytes, &errorBody)
+		apiErr := types.NewAPIError(statusCode, "", "", errorBody.Error, "")
+		apiErr.Attempts = attempts
+		return apiErr
+	}
+	if err := transport.DecodeJSONResponse(bodyBytes, target); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
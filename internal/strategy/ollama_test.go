@@ -0,0 +1,243 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+func TestBuildOllamaChatRequestBody(t *testing.T) {
+	req := OllamaChatRequest{
+		Model: "llama3.2",
+		Messages: []ChatMessage{
+			{Role: "system", Content: "Be brief"},
+			{Role: "assistant", ToolCalls: []ChatToolCall{{ID: "call_0", Name: "get_weather", Arguments: `{"city":"Paris"}`}}},
+		},
+		RequestParams: map[string]any{
+			"temperature": 0.3,
+			"num_ctx":     8192,
+			"keep_alive":  "5m",
+		},
+	}
+
+	body := BuildOllamaChatRequestBody(req)
+
+	if body["stream"] != false {
+		t.Errorf("expected stream false, got %v", body["stream"])
+	}
+	options, ok := body["options"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected options map, got %v", body["options"])
+	}
+	if options["temperature"] != 0.3 || options["num_ctx"] != 8192 {
+		t.Errorf("unexpected options: %v", options)
+	}
+	if _, exists := options["keep_alive"]; exists {
+		t.Error("expected keep_alive to be sent top-level, not in options")
+	}
+	if body["keep_alive"] != "5m" {
+		t.Errorf("expected keep_alive '5m', got %v", body["keep_alive"])
+	}
+
+	messages := body["messages"].([]map[string]any)
+	toolCalls := messages[1]["tool_calls"].([]map[string]any)
+	function := toolCalls[0]["function"].(map[string]any)
+	if string(function["arguments"].(json.RawMessage)) != `{"city":"Paris"}` {
+		t.Errorf("expected arguments to be sent as an object, got %v", function["arguments"])
+	}
+}
+
+func TestBuildOllamaChatRequestBodyImages(t *testing.T) {
+	req := OllamaChatRequest{
+		Model: "llava",
+		Messages: []ChatMessage{{
+			Role:    "user",
+			Content: "Describe this",
+			Parts: []types.ContentPart{
+				types.NewTextPart("Describe this"),
+				types.NewImagePart([]byte("img"), "image/png"),
+			},
+		}},
+	}
+
+	body := BuildOllamaChatRequestBody(req)
+
+	message := body["messages"].([]map[string]any)[0]
+	if message["content"] != "Describe this" {
+		t.Errorf("expected text content, got %v", message["content"])
+	}
+	images, ok := message["images"].([]string)
+	if !ok || len(images) != 1 || images[0] != "aW1n" {
+		t.Errorf("expected one base64 image, got %v", message["images"])
+	}
+}
+
+func TestBuildOllamaChatRequestBodyFormat(t *testing.T) {
+	t.Run("json schema", func(t *testing.T) {
+		schema := map[string]any{"type": "object"}
+		body := BuildOllamaChatRequestBody(OllamaChatRequest{
+			Model:         "llama3.2",
+			RequestParams: map[string]any{"format": types.NewJSONSchemaFormat("invoice", schema, true)},
+		})
+
+		format, ok := body["format"].(map[string]any)
+		if !ok || format["type"] != "object" {
+			t.Errorf("expected schema to be sent as format, got %v", body["format"])
+		}
+	})
+
+	t.Run("json object", func(t *testing.T) {
+		body := BuildOllamaChatRequestBody(OllamaChatRequest{
+			Model:         "llama3.2",
+			RequestParams: map[string]any{"format": types.NewJSONObjectFormat()},
+		})
+
+		if body["format"] != "json" {
+			t.Errorf("expected format 'json', got %v", body["format"])
+		}
+	})
+}
+
+func TestParseOllamaChatResponse(t *testing.T) {
+	t.Run("successful parsing", func(t *testing.T) {
+		var response OllamaChatResponse
+		err := json.Unmarshal([]byte(`{"model":"llama3.2","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_weather","arguments":{"city":"Paris"}}}]},"done":true,"prompt_eval_count":26,"eval_count":10}`), &response)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		result, err := ParseOllamaChatResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Usage().PromptTokens() != 26 || result.Usage().CompletionTokens() != 10 || result.Usage().TotalTokens() != 36 {
+			t.Errorf("unexpected usage: %+v", result.Usage())
+		}
+		toolCalls := result.ToolCalls()
+		if len(toolCalls) != 1 || toolCalls[0].ID() != "call_0" || toolCalls[0].Arguments() != `{"city":"Paris"}` {
+			t.Errorf("unexpected tool calls: %v", toolCalls)
+		}
+	})
+
+	t.Run("response metadata", func(t *testing.T) {
+		var response OllamaChatResponse
+		err := json.Unmarshal([]byte(`{"model":"llama3.2","created_at":"2025-01-02T03:04:05Z","message":{"role":"assistant","content":"Once"},"done":true,"done_reason":"length","prompt_eval_count":4,"eval_count":1}`), &response)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		result, err := ParseOllamaChatResponse(response, http.StatusOK)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Truncated() || result.Model() != "llama3.2" {
+			t.Errorf("unexpected metadata: finish=%s model=%s", result.FinishReason(), result.Model())
+		}
+		if !result.Created().Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("unexpected created time: %v", result.Created())
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		_, err := ParseOllamaChatResponse(OllamaChatResponse{Error: "model 'missing' not found"}, http.StatusNotFound)
+		expected := "API error (status 404): model 'missing' not found"
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected '%s', got %v", expected, err)
+		}
+	})
+}
+
+func TestParseOllamaChatStream(t *testing.T) {
+	body := `{"message":{"role":"assistant","content":"Hel"},"done":false}
+{"message":{"role":"assistant","content":"lo"},"done":false}
+{"model":"llama3.2","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":4,"eval_count":2}
+`
+	chunks := make(chan types.StreamChunk)
+	go func() {
+		defer close(chunks)
+		ParseOllamaChatStream(context.Background(), strings.NewReader(body), chunks)
+	}()
+
+	var collected []types.StreamChunk
+	for chunk := range chunks {
+		collected = append(collected, chunk)
+	}
+
+	if len(collected) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(collected))
+	}
+	result := collected[2].Result()
+	if result.TextContent() != "Hello" {
+		t.Errorf("expected 'Hello', got '%s'", result.TextContent())
+	}
+	if result.Usage().TotalTokens() != 6 {
+		t.Errorf("expected 6 total tokens, got %d", result.Usage().TotalTokens())
+	}
+	if result.FinishReason() != types.FinishReasonStop || result.Model() != "llama3.2" {
+		t.Errorf("unexpected metadata: finish=%s model=%s", result.FinishReason(), result.Model())
+	}
+}
+
+func TestExecuteOllamaTagsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("expected path /api/tags, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"models":[{"name":"llama3.2:latest","size":2019393189,"details":{"family":"llama","parameter_size":"3.2B"}}]}`))
+	}))
+	defer server.Close()
+
+	tags, err := ExecuteOllamaTagsRequest(context.Background(), OllamaConfig{BaseURL: server.URL, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags.Models) != 1 || tags.Models[0].Name != "llama3.2:latest" || tags.Models[0].Details.Family != "llama" {
+		t.Errorf("unexpected models: %+v", tags.Models)
+	}
+}
+
+func TestExecuteOllamaShowRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/show" || body["model"] != "llama3.2:latest" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"model not found"}`))
+			return
+		}
+		w.Write([]byte(`{"parameters":"num_ctx 8192","model_info":{"general.architecture":"llama","llama.context_length":131072}}`))
+	}))
+	defer server.Close()
+	config := OllamaConfig{BaseURL: server.URL, HTTPClient: server.Client()}
+
+	show, err := ExecuteOllamaShowRequest(context.Background(), config, "llama3.2:latest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if show.ContextLength(OllamaDefaultContextLength) != 8192 {
+		t.Errorf("expected num_ctx to take precedence, got %d", show.ContextLength(OllamaDefaultContextLength))
+	}
+	show.Parameters = ""
+	if show.ContextLength(OllamaDefaultContextLength) != OllamaDefaultContextLength {
+		t.Errorf("expected the server default without num_ctx, got %d", show.ContextLength(OllamaDefaultContextLength))
+	}
+	if show.TrainedContextLength() != 131072 {
+		t.Errorf("expected the trained context length, got %d", show.TrainedContextLength())
+	}
+	if length := (OllamaShowResponse{ModelInfo: map[string]any{"general.architecture": "phi", "phi.context_length": 2048.0}}).ContextLength(OllamaDefaultContextLength); length != 2048 {
+		t.Errorf("expected a trained length below the default to cap the window, got %d", length)
+	}
+
+	_, err = ExecuteOllamaShowRequest(context.Background(), config, "mistral")
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "model not found" {
+		t.Errorf("expected a 404 APIError, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package tokenizer
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	EncodingCL100K = "cl100k_base"
+	EncodingO200K  = "o200k_base"
+)
+
+const whitespaceClass = `\t\n\v\f\r\x{85}\p{Z}`
+
+var encodingPatterns = map[string]*regexp.Regexp{
+	EncodingCL100K: compilePattern(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`),
+	EncodingO200K:  compilePattern(`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`),
+}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	pattern = strings.ReplaceAll(pattern, `[^\s`, `[^`+whitespaceClass)
+	pattern = strings.ReplaceAll(pattern, `\s`, `[`+whitespaceClass+`]`)
+	return regexp.MustCompile(pattern)
+}
+
+type Encoding struct {
+	name    string
+	pattern *regexp.Regexp
+	ranks   map[string]int
+}
+
+func NewEncoding(name string, ranks map[string]int) (*Encoding, error) {
+	pattern, known := encodingPatterns[name]
+	if !known {
+		return nil, fmt.Errorf("unknown encoding %q", name)
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("encoding %s has no ranks", name)
+	}
+	return &Encoding{name: name, pattern: pattern, ranks: ranks}, nil
+}
+
+func LoadEncoding(name string, r io.Reader) (*Encoding, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		token, rank, found := strings.Cut(text, " ")
+		if !found {
+			return nil, fmt.Errorf("invalid %s line %d: expected token and rank", name, line)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s line %d: %w", name, line, err)
+		}
+		value, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s line %d: %w", name, line, err)
+		}
+		ranks[string(decoded)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return NewEncoding(name, ranks)
+}
+
+func LoadEncodingFile(name string, path string) (*Encoding, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+	return LoadEncoding(name, file)
+}
+
+func (e *Encoding) Name() string {
+	return e.name
+}
+
+func (e *Encoding) Encode(text string) []int {
+	var tokens []int
+	for _, piece := range splitPieces(e.pattern, text) {
+		if rank, exists := e.ranks[piece]; exists {
+			tokens = append(tokens, rank)
+			continue
+		}
+		tokens = append(tokens, e.mergePiece(piece)...)
+	}
+	return tokens
+}
+
+func (e *Encoding) Count(text string) int {
+	return len(e.Encode(text))
+}
+
+func (e *Encoding) mergePiece(piece string) []int {
+	n := len(piece)
+	ends := make([]int, n)
+	prevs := make([]int, n)
+	for i := range n {
+		ends[i], prevs[i] = i+1, i-1
+	}
+
+	queue := &mergeQueue{}
+	push := func(start int) {
+		if start < 0 || ends[start] >= n {
+			return
+		}
+		if rank, exists := e.ranks[piece[start:ends[ends[start]]]]; exists {
+			heap.Push(queue, mergeCandidate{rank: rank, start: start})
+		}
+	}
+	for i := range n - 1 {
+		push(i)
+	}
+	for queue.Len() > 0 {
+		candidate := heap.Pop(queue).(mergeCandidate)
+		start := candidate.start
+		if ends[start] < 0 || ends[start] >= n {
+			continue
+		}
+		next := ends[start]
+		if rank, exists := e.ranks[piece[start:ends[next]]]; !exists || rank != candidate.rank {
+			continue
+		}
+		ends[start], ends[next] = ends[next], -1
+		if ends[start] < n {
+			prevs[ends[start]] = start
+		}
+		push(prevs[start])
+		push(start)
+	}
+
+	var tokens []int
+	for start := 0; start < n; start = ends[start] {
+		rank, exists := e.ranks[piece[start:ends[start]]]
+		if !exists {
+			rank = -1
+		}
+		tokens = append(tokens, rank)
+	}
+	return tokens
+}
+
+type mergeCandidate struct {
+	rank  int
+	start int
+}
+
+type mergeQueue []mergeCandidate
+
+func (q mergeQueue) Len() int {
+	return len(q)
+}
+
+func (q mergeQueue) Less(i, j int) bool {
+	if q[i].rank != q[j].rank {
+		return q[i].rank < q[j].rank
+	}
+	return q[i].start < q[j].start
+}
+
+func (q mergeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *mergeQueue) Push(x any) {
+	*q = append(*q, x.(mergeCandidate))
+}
+
+func (q *mergeQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+func splitPieces(pattern *regexp.Regexp, text string) []string {
+	var pieces []string
+	for pos := 0; pos < len(text); {
+		loc := pattern.FindStringIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+		piece := text[pos+loc[0] : pos+loc[1]]
+		if pos+loc[1] < len(text) && isTrailingSpace(piece) {
+			_, size := utf8.DecodeLastRuneInString(piece)
+			piece = piece[:len(piece)-size]
+		}
+		pieces = append(pieces, piece)
+		pos += loc[0] + len(piece)
+	}
+	return pieces
+}
+
+func isTrailingSpace(piece string) bool {
+	if utf8.RuneCountInString(piece) < 2 || strings.ContainsAny(piece, "\r\n") {
+		return false
+	}
+	return strings.TrimSpace(piece) == ""
+}
@@ -0,0 +1,89 @@
+package tokenizer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testRanks() string {
+	var lines []string
+	for i, token := range []string{"a", "b", "c", " ", "ab", "abc", " abc", "1", "2"} {
+		lines = append(lines, fmt.Sprintf("%s %d", base64.StdEncoding.EncodeToString([]byte(token)), i))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestLoadEncoding(t *testing.T) {
+	t.Run("parses tiktoken ranks", func(t *testing.T) {
+		encoding, err := LoadEncoding(EncodingCL100K, strings.NewReader(testRanks()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if encoding.Name() != EncodingCL100K || len(encoding.ranks) != 9 || encoding.ranks["ab"] != 4 {
+			t.Errorf("unexpected encoding: %+v", encoding)
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		if _, err := LoadEncoding(EncodingCL100K, strings.NewReader("YQ==\n")); err == nil {
+			t.Error("expected error for missing rank")
+		}
+		if _, err := LoadEncoding(EncodingCL100K, strings.NewReader("!!! 1\n")); err == nil {
+			t.Error("expected error for invalid base64")
+		}
+		if _, err := LoadEncoding("p50k_base", strings.NewReader(testRanks())); err == nil {
+			t.Error("expected error for unknown encoding")
+		}
+		if _, err := LoadEncodingFile(EncodingCL100K, "missing.tiktoken"); err == nil {
+			t.Error("expected error for missing file")
+		}
+	})
+}
+
+func TestEncodingEncode(t *testing.T) {
+	encoding, _ := LoadEncoding(EncodingO200K, strings.NewReader(testRanks()))
+
+	if tokens := encoding.Encode("abc abc"); !slices.Equal(tokens, []int{5, 6}) {
+		t.Errorf("expected whole-piece ranks, got %v", tokens)
+	}
+	if tokens := encoding.Encode("cab"); !slices.Equal(tokens, []int{2, 4}) {
+		t.Errorf("expected c + ab merge, got %v", tokens)
+	}
+	if tokens := encoding.Encode("bca12"); !slices.Equal(tokens, []int{1, 2, 0, 7, 8}) {
+		t.Errorf("expected unmerged bytes, got %v", tokens)
+	}
+	if count := encoding.Count("abcd"); count != 2 {
+		t.Errorf("expected unknown bytes to count as tokens, got %d", count)
+	}
+	if tokens := encoding.Encode(strings.Repeat("ab", 100000)); len(tokens) != 100000 {
+		t.Errorf("expected a long piece to merge pairwise, got %d tokens", len(tokens))
+	}
+}
+
+func TestSplitPieces(t *testing.T) {
+	pattern := encodingPatterns[EncodingCL100K]
+	cases := []struct {
+		text     string
+		expected []string
+	}{
+		{"Hello, world!", []string{"Hello", ",", " world", "!"}},
+		{"hello  world", []string{"hello", " ", " world"}},
+		{"a  123", []string{"a", " ", " ", "123"}},
+		{"a  !", []string{"a", " ", " !"}},
+		{"x\n\ny", []string{"x", "\n\n", "y"}},
+		{"end  ", []string{"end", "  "}},
+		{"it's 12345", []string{"it", "'s", " ", "123", "45"}},
+		{"\n  <body", []string{"\n", " ", " <", "body"}},
+		{"a\u00a0\u00a0b", []string{"a", "\u00a0", "\u00a0b"}},
+	}
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			if pieces := splitPieces(pattern, c.text); !slices.Equal(pieces, c.expected) {
+				t.Errorf("expected %q, got %q", c.expected, pieces)
+			}
+		})
+	}
+}
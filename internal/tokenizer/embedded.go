@@ -0,0 +1,57 @@
+package tokenizer
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"sync"
+)
+
+var (
+	//go:embed data/cl100k_base.tiktoken
+	cl100kRanks []byte
+	//go:embed data/o200k_base.tiktoken
+	o200kRanks []byte
+)
+
+var embedded = map[string]*embeddedEncoding{
+	EncodingCL100K: {name: EncodingCL100K, data: cl100kRanks},
+	EncodingO200K:  {name: EncodingO200K, data: o200kRanks},
+}
+
+func init() {
+	for name, encoding := range embedded {
+		Register(name, encoding)
+	}
+}
+
+type embeddedEncoding struct {
+	name     string
+	data     []byte
+	once     sync.Once
+	encoding *Encoding
+	err      error
+}
+
+func (e *embeddedEncoding) load() (*Encoding, error) {
+	e.once.Do(func() {
+		e.encoding, e.err = LoadEncoding(e.name, bytes.NewReader(e.data))
+	})
+	return e.encoding, e.err
+}
+
+func (e *embeddedEncoding) Count(text string) int {
+	encoding, err := e.load()
+	if err != nil {
+		return Heuristic{}.Count(text)
+	}
+	return encoding.Count(text)
+}
+
+func EmbeddedEncoding(name string) (*Encoding, error) {
+	encoding, exists := embedded[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown encoding %q", name)
+	}
+	return encoding.load()
+}
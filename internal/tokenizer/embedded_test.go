@@ -0,0 +1,66 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestEmbeddedEncoding(t *testing.T) {
+	cases := []struct {
+		encoding string
+		text     string
+		expected []int
+	}{
+		{EncodingCL100K, "hello world", []int{15339, 1917}},
+		{EncodingCL100K, "Hello, world!", []int{9906, 11, 1917, 0}},
+		{EncodingO200K, "hello world", []int{24912, 2375}},
+		{EncodingO200K, "Hello, world!", []int{13225, 11, 2375, 0}},
+	}
+	for _, c := range cases {
+		t.Run(c.encoding+"/"+c.text, func(t *testing.T) {
+			encoding, err := EmbeddedEncoding(c.encoding)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tokens := encoding.Encode(c.text); !slices.Equal(tokens, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, tokens)
+			}
+		})
+	}
+
+	t.Run("registered for models", func(t *testing.T) {
+		if count := ForModel("gpt-4.1").Count("hello world"); count != 2 {
+			t.Errorf("expected 2 tokens, got %d", count)
+		}
+		if _, err := EmbeddedEncoding("p50k_base"); err == nil {
+			t.Error("expected error for an encoding that is not embedded")
+		}
+	})
+}
+
+func TestEmbeddedEncodingParity(t *testing.T) {
+	data, err := os.ReadFile("testdata/parity.json")
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+	var fixtures []struct {
+		Encoding string `json:"encoding"`
+		Text     string `json:"text"`
+		Tokens   []int  `json:"tokens"`
+	}
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("failed to decode fixtures: %v", err)
+	}
+
+	for i, fixture := range fixtures {
+		encoding, err := EmbeddedEncoding(fixture.Encoding)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tokens := encoding.Encode(fixture.Text); !slices.Equal(tokens, fixture.Tokens) {
+			t.Errorf("fixture %d (%s): expected %d tokens %v, got %d tokens %v", i, fixture.Encoding, len(fixture.Tokens), fixture.Tokens, len(tokens), tokens)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package tokenizer
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	MessageOverheadTokens = 3
+	ReplyPrimingTokens    = 3
+	ImageLowDetailTokens  = 85
+	ImageTokens           = 765
+)
+
+type Tokenizer interface {
+	Count(text string) int
+}
+
+type Heuristic struct{}
+
+func (Heuristic) Count(text string) int {
+	tokens := 0
+	for _, piece := range splitPieces(encodingPatterns[EncodingCL100K], text) {
+		ascii, other := 0, 0
+		for _, r := range strings.TrimPrefix(piece, " ") {
+			if r < utf8.RuneSelf {
+				ascii++
+			} else {
+				other++
+			}
+		}
+		tokens += max(1, (ascii+4)/5+other)
+	}
+	return tokens
+}
+
+var (
+	mu        sync.RWMutex
+	encodings = map[string]Tokenizer{}
+)
+
+func Register(encoding string, t Tokenizer) {
+	mu.Lock()
+	defer mu.Unlock()
+	encodings[encoding] = t
+}
+
+func EncodingForModel(modelName string) string {
+	if i := strings.LastIndex(modelName, "/"); i >= 0 {
+		modelName = modelName[i+1:]
+	}
+	switch {
+	case strings.HasPrefix(modelName, "gpt-4o"), strings.HasPrefix(modelName, "gpt-4.1"), strings.HasPrefix(modelName, "gpt-5"),
+		strings.HasPrefix(modelName, "o1"), strings.HasPrefix(modelName, "o3"), strings.HasPrefix(modelName, "o4"):
+		return EncodingO200K
+	case strings.HasPrefix(modelName, "gpt-4"), strings.HasPrefix(modelName, "gpt-3.5"), strings.HasPrefix(modelName, "text-embedding-"):
+		return EncodingCL100K
+	default:
+		return ""
+	}
+}
+
+func ForModel(modelName string) Tokenizer {
+	mu.RLock()
+	defer mu.RUnlock()
+	if t, exists := encodings[EncodingForModel(modelName)]; exists {
+		return t
+	}
+	return Heuristic{}
+}
+
+func CountMessage(t Tokenizer, message types.Message) int {
+	tokens := MessageOverheadTokens + t.Count(message.Role()) + t.Count(message.Content())
+	for _, call := range message.ToolCalls() {
+		tokens += t.Count(call.Name()) + t.Count(call.Arguments())
+	}
+	for _, part := range message.Parts() {
+		switch {
+		case part.Type() == types.ContentPartText:
+		case part.Type() == types.ContentPartImage && part.Detail() == "low":
+			tokens += ImageLowDetailTokens
+		case part.Type() == types.ContentPartImage:
+			tokens += ImageTokens
+		case strings.HasPrefix(part.MimeType(), "text/"):
+			tokens += t.Count(string(part.Data()))
+		}
+	}
+	return tokens
+}
+
+func CountMessages(t Tokenizer, messages []types.Message) int {
+	tokens := ReplyPrimingTokens
+	for _, message := range messages {
+		tokens += CountMessage(t, message)
+	}
+	return tokens
+}
@@ -0,0 +1,87 @@
+package tokenizer
+
+import (
+	"testing"
+
+	"agentic-ai-framework/internal/types"
+)
+
+type fixedTokenizer struct{}
+
+func (fixedTokenizer) Count(text string) int {
+	return len(text)
+}
+
+func TestHeuristic(t *testing.T) {
+	if count := (Heuristic{}).Count(""); count != 0 {
+		t.Errorf("expected 0 tokens for empty text, got %d", count)
+	}
+	if count := (Heuristic{}).Count("The quick brown fox"); count != 4 {
+		t.Errorf("expected one token per short word, got %d", count)
+	}
+	if count := (Heuristic{}).Count("internationalization"); count != 4 {
+		t.Errorf("expected long words to split, got %d", count)
+	}
+	if count := (Heuristic{}).Count("你好世界"); count != 4 {
+		t.Errorf("expected one token per CJK character, got %d", count)
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	cases := map[string]string{
+		"gpt-4.1":                EncodingO200K,
+		"openai/gpt-5-mini":      EncodingO200K,
+		"o3-mini":                EncodingO200K,
+		"gpt-4-turbo":            EncodingCL100K,
+		"text-embedding-3-small": EncodingCL100K,
+		"claude-sonnet-4-5":      "",
+	}
+	for model, expected := range cases {
+		if encoding := EncodingForModel(model); encoding != expected {
+			t.Errorf("%s: expected %q, got %q", model, expected, encoding)
+		}
+	}
+}
+
+func TestForModel(t *testing.T) {
+	embedded := ForModel("gpt-4-turbo")
+	if _, ok := embedded.(*embeddedEncoding); !ok {
+		t.Errorf("expected the embedded encoding by default, got %T", embedded)
+	}
+
+	Register(EncodingCL100K, fixedTokenizer{})
+	defer Register(EncodingCL100K, embedded)
+	if _, ok := ForModel("gpt-4-turbo").(fixedTokenizer); !ok {
+		t.Error("expected registered encoding")
+	}
+	if _, ok := ForModel("claude-haiku-4-5").(Heuristic); !ok {
+		t.Error("expected heuristic for unknown models")
+	}
+}
+
+func TestCountMessages(t *testing.T) {
+	messages := []types.Message{
+		types.NewSystemMessage("abc"),
+		types.NewAssistantMessage("", types.NewToolCall("call_1", "fn", `{}`)),
+		types.NewUserMessageWithParts(
+			types.NewTextPart("hi"),
+			types.NewImageURLPart("https://example.com/a.png").WithDetail("low"),
+			types.NewImageURLPart("https://example.com/b.png"),
+			types.NewFilePart("notes.txt", []byte("12345"), ""),
+			types.NewFilePart("report.pdf", []byte("%PDF-1.7"), ""),
+		),
+	}
+
+	if tokens := CountMessage(fixedTokenizer{}, messages[0]); tokens != 12 {
+		t.Errorf("expected 12 tokens, got %d", tokens)
+	}
+	if tokens := CountMessage(fixedTokenizer{}, messages[1]); tokens != 16 {
+		t.Errorf("expected 16 tokens, got %d", tokens)
+	}
+	if tokens := CountMessage(fixedTokenizer{}, messages[2]); tokens != 3+4+2+85+765+5 {
+		t.Errorf("expected %d tokens, got %d", 3+4+2+85+765+5, tokens)
+	}
+	if tokens := CountMessages(fixedTokenizer{}, messages); tokens != 3+12+16+864 {
+		t.Errorf("expected %d tokens, got %d", 3+12+16+864, tokens)
+	}
+}
@@ -0,0 +1,272 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type CassetteMode string
+
+const (
+	ModeReplay CassetteMode = "replay"
+	ModeRecord CassetteMode = "record"
+)
+
+var ErrUnmatchedRequest = errors.New("no recorded interaction matches request")
+
+var ScrubbedHeaders = []string{"Authorization", "X-Api-Key", "Api-Key", "Cookie", "Set-Cookie", "Openai-Organization", "Openai-Project"}
+
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	mode         CassetteMode
+	next         http.RoundTripper
+	interactions []Interaction
+	played       []bool
+}
+
+func NewCassette(path string, mode CassetteMode, next http.RoundTripper) (*Cassette, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	cassette := &Cassette{path: path, mode: mode, next: next}
+
+	switch mode {
+	case ModeRecord:
+		return cassette, nil
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &cassette.interactions); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+		}
+		cassette.played = make([]bool, len(cassette.interactions))
+		return cassette, nil
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", mode)
+	}
+}
+
+func (c *Cassette) Mode() CassetteMode {
+	return c.mode
+}
+
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+func (c *Cassette) Unplayed() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unplayed []Interaction
+	for i, interaction := range c.interactions {
+		if !c.played[i] {
+			unplayed = append(unplayed, interaction)
+		}
+	}
+	return unplayed
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, outgoing, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.mode == ModeReplay {
+		if outgoing.Body != nil {
+			outgoing.Body.Close()
+		}
+		return c.replay(req, body)
+	}
+	return c.record(outgoing, body)
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.played[i] || !matchRequest(interaction.Request, req, body) {
+			continue
+		}
+		c.played[i] = true
+		recorded := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Headers.Clone(),
+			Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, req.Method, req.URL.Path)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: scrubHeaders(req.Header),
+			Body:    string(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    scrubHeaders(resp.Header),
+			Body:       string(responseBody),
+		},
+	})
+	c.played = append(c.played, true)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+func (c *Cassette) Save() error {
+	if c.mode != ModeRecord {
+		return nil
+	}
+
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	return nil
+}
+
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	if req.GetBody != nil {
+		copied, err := req.GetBody()
+		if err != nil {
+			req.Body.Close()
+			return nil, nil, fmt.Errorf("failed to read request body: %v", err)
+		}
+		body, err := io.ReadAll(copied)
+		copied.Close()
+		if err != nil {
+			req.Body.Close()
+			return nil, nil, fmt.Errorf("failed to read request body: %v", err)
+		}
+		return body, req, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+	outgoing := req.Clone(req.Context())
+	outgoing.Body = io.NopCloser(bytes.NewReader(body))
+	outgoing.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, outgoing, nil
+}
+
+func matchRequest(recorded RecordedRequest, req *http.Request, body []byte) bool {
+	if recorded.Method != req.Method {
+		return false
+	}
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil || recordedURL.Path != req.URL.Path || recordedURL.RawQuery != req.URL.RawQuery {
+		return false
+	}
+	return normalizeBody([]byte(recorded.Body)) == normalizeBody(body)
+}
+
+func normalizeBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(bytes.TrimSpace(body))
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
+
+func scrubHeaders(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range ScrubbedHeaders {
+		scrubbed.Del(name)
+	}
+	if len(scrubbed) == 0 {
+		return nil
+	}
+	return scrubbed
+}
@@ -0,0 +1,149 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func postJSON(t *testing.T, client *http.Client, url string, body string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	req.Header.Set("Content-Type", "application/json")
+	return client.Do(req)
+}
+
+func TestCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "chat.json")
+
+	t.Run("records interactions without credentials", func(t *testing.T) {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.Header.Get("Authorization") != "Bearer sk-secret" {
+				t.Errorf("expected credentials to reach the server, got %q", r.Header.Get("Authorization"))
+			}
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Set-Cookie", "session=secret")
+			w.Header().Set("X-Request-Id", "req_1")
+			w.Write([]byte(`{"echo":` + string(body) + `}`))
+		}))
+		defer server.Close()
+
+		cassette, err := NewCassette(path, ModeRecord, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client := cassette.Client()
+		for _, body := range []string{`{"model":"gpt-4.1","n":1}`, `{"model":"gpt-4.1","n":2}`} {
+			resp, err := postJSON(t, client, server.URL+"/v1/chat/completions", body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, _ := ReadResponseBody(resp)
+			if string(got) != `{"echo":`+body+`}` {
+				t.Errorf("expected response body to pass through, got %s", got)
+			}
+		}
+		if err := cassette.Save(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(string(data), "sk-secret") || strings.Contains(string(data), "session=secret") {
+			t.Errorf("expected credentials to be scrubbed, got %s", data)
+		}
+		if !strings.Contains(string(data), "req_1") || calls != 2 || len(cassette.Interactions()) != 2 {
+			t.Errorf("expected two recorded interactions, got %s", data)
+		}
+	})
+
+	t.Run("replays recorded responses", func(t *testing.T) {
+		cassette, err := NewCassette(path, ModeReplay, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client := cassette.Client()
+
+		resp, err := postJSON(t, client, "https://api.example.com/v1/chat/completions", `{"n": 2, "model": "gpt-4.1"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, _ := ReadResponseBody(resp)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Request-Id") != "req_1" || string(body) != `{"echo":{"model":"gpt-4.1","n":2}}` {
+			t.Errorf("unexpected replayed response: %d %s", resp.StatusCode, body)
+		}
+		if unplayed := cassette.Unplayed(); len(unplayed) != 1 || unplayed[0].Request.Body != `{"model":"gpt-4.1","n":1}` {
+			t.Errorf("expected first interaction to remain unplayed, got %+v", unplayed)
+		}
+
+		_, err = postJSON(t, client, "https://api.example.com/v1/chat/completions", `{"model":"gpt-4.1","n":2}`)
+		if !errors.Is(err, ErrUnmatchedRequest) {
+			t.Errorf("expected replayed interaction not to match twice, got %v", err)
+		}
+		_, err = postJSON(t, client, "https://api.example.com/v1/embeddings", `{"model":"gpt-4.1","n":1}`)
+		if !errors.Is(err, ErrUnmatchedRequest) {
+			t.Errorf("expected ErrUnmatchedRequest for a different path, got %v", err)
+		}
+	})
+
+	t.Run("leaves the caller's request untouched", func(t *testing.T) {
+		var received []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = append(received, string(body))
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		cassette, err := NewCassette(filepath.Join(t.TempDir(), "untouched.json"), ModeRecord, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, reader := range []io.Reader{
+			strings.NewReader(`{"model":"gpt-4.1"}`),
+			io.NopCloser(strings.NewReader(`{"model":"gpt-4.1"}`)),
+		} {
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/chat/completions", reader)
+			body, getBody := req.Body, req.GetBody
+			resp, err := cassette.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if req.Body != body || (req.GetBody == nil) != (getBody == nil) {
+				t.Errorf("expected the request body not to be replaced")
+			}
+		}
+		if len(received) != 2 || received[0] != `{"model":"gpt-4.1"}` || received[1] != `{"model":"gpt-4.1"}` {
+			t.Errorf("expected both bodies to reach the server, got %v", received)
+		}
+		for _, interaction := range cassette.Interactions() {
+			if interaction.Request.Body != `{"model":"gpt-4.1"}` {
+				t.Errorf("expected the body to be recorded, got %q", interaction.Request.Body)
+			}
+		}
+	})
+
+	t.Run("rejects missing cassettes and unknown modes", func(t *testing.T) {
+		if _, err := NewCassette(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil); err == nil {
+			t.Error("expected error for missing cassette")
+		}
+		if _, err := NewCassette(path, CassetteMode("rewind"), nil); err == nil {
+			t.Error("expected error for unknown mode")
+		}
+	})
+}
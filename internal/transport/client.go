@@ -75,9 +75,41 @@ func CheckStatus(statusCode int) error {
 	return nil
 }
 
-func CreateRequestContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+func NewContextClient() *http.Client {
+	return &http.Client{}
+}
+
+func CreateRequestContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+		return context.WithCancel(ctx)
+	}
 	if timeout == 0 {
 		timeout = DefaultTimeout
 	}
-	return context.WithTimeout(context.Background(), timeout)
+	return context.WithTimeout(ctx, timeout)
+}
+
+func CreateStreamContext(ctx context.Context, timeout time.Duration) (context.Context, func(), context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	streamCtx, cancel := context.WithCancelCause(ctx)
+	headersReceived := func() {}
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		if timeout == 0 {
+			timeout = DefaultTimeout
+		}
+		timer := time.AfterFunc(timeout, func() {
+			cancel(context.DeadlineExceeded)
+		})
+		headersReceived = func() {
+			timer.Stop()
+		}
+	}
+	return streamCtx, headersReceived, func() {
+		cancel(context.Canceled)
+	}
 }
@@ -1,6 +1,8 @@
 package transport
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,9 +26,16 @@ func TestNewClient(t *testing.T) {
 	})
 }
 
+func TestNewContextClient(t *testing.T) {
+	client := NewContextClient()
+	if client.Timeout != 0 {
+		t.Errorf("expected no client timeout, got %v", client.Timeout)
+	}
+}
+
 func TestCreateJSONRequest(t *testing.T) {
 	t.Run("successful creation", func(t *testing.T) {
-		ctx, cancel := CreateRequestContext(DefaultTimeout)
+		ctx, cancel := CreateRequestContext(context.Background(), DefaultTimeout)
 		defer cancel()
 
 		req, err := CreateJSONRequest(ctx, "POST", "http://example.com", map[string]any{
@@ -61,7 +70,7 @@ func TestCreateJSONRequest(t *testing.T) {
 	})
 
 	t.Run("nil body", func(t *testing.T) {
-		ctx, cancel := CreateRequestContext(DefaultTimeout)
+		ctx, cancel := CreateRequestContext(context.Background(), DefaultTimeout)
 		defer cancel()
 
 		req, err := CreateJSONRequest(ctx, "GET", "http://example.com", nil, nil)
@@ -84,7 +93,7 @@ func TestExecuteRequest(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(DefaultTimeout)
-	ctx, cancel := CreateRequestContext(DefaultTimeout)
+	ctx, cancel := CreateRequestContext(context.Background(), DefaultTimeout)
 	defer cancel()
 
 	req, err := CreateJSONRequest(ctx, "GET", server.URL, nil, nil)
@@ -110,7 +119,7 @@ func TestReadResponseBody(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(DefaultTimeout)
-	ctx, cancel := CreateRequestContext(DefaultTimeout)
+	ctx, cancel := CreateRequestContext(context.Background(), DefaultTimeout)
 	defer cancel()
 
 	req, err := CreateJSONRequest(ctx, "GET", server.URL, nil, nil)
@@ -174,7 +183,7 @@ func TestCheckStatus(t *testing.T) {
 
 func TestCreateRequestContext(t *testing.T) {
 	t.Run("default timeout", func(t *testing.T) {
-		ctx, cancel := CreateRequestContext(0)
+		ctx, cancel := CreateRequestContext(context.Background(), 0)
 		defer cancel()
 
 		if ctx == nil {
@@ -194,7 +203,7 @@ func TestCreateRequestContext(t *testing.T) {
 
 	t.Run("custom timeout", func(t *testing.T) {
 		customTimeout := 10 * time.Second
-		ctx, cancel := CreateRequestContext(customTimeout)
+		ctx, cancel := CreateRequestContext(context.Background(), customTimeout)
 		defer cancel()
 
 		if ctx == nil {
@@ -212,3 +221,106 @@ func TestCreateRequestContext(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateRequestContextHonorsParent(t *testing.T) {
+	t.Run("parent deadline overrides default timeout", func(t *testing.T) {
+		parent, parentCancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer parentCancel()
+
+		ctx, cancel := CreateRequestContext(parent, DefaultTimeout)
+		defer cancel()
+
+		parentDeadline, _ := parent.Deadline()
+		deadline, ok := ctx.Deadline()
+		if !ok || !deadline.Equal(parentDeadline) {
+			t.Errorf("expected deadline %v, got %v", parentDeadline, deadline)
+		}
+	})
+
+	t.Run("parent cancellation propagates", func(t *testing.T) {
+		parent, parentCancel := context.WithCancel(context.Background())
+
+		ctx, cancel := CreateRequestContext(parent, DefaultTimeout)
+		defer cancel()
+
+		parentCancel()
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("expected request context to be cancelled with its parent")
+		}
+	})
+
+	t.Run("parent values are preserved", func(t *testing.T) {
+		type traceKey struct{}
+		parent := context.WithValue(context.Background(), traceKey{}, "trace-123")
+
+		ctx, cancel := CreateRequestContext(parent, DefaultTimeout)
+		defer cancel()
+
+		if ctx.Value(traceKey{}) != "trace-123" {
+			t.Errorf("expected trace value to be preserved, got %v", ctx.Value(traceKey{}))
+		}
+	})
+}
+
+func TestCreateStreamContext(t *testing.T) {
+	t.Run("times out before headers", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		ctx, headersReceived, cancel := CreateStreamContext(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		_, err := server.Client().Do(req)
+		headersReceived()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("does not limit the body once headers arrive", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("first\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(150 * time.Millisecond)
+			w.Write([]byte("second\n"))
+		}))
+		defer server.Close()
+
+		ctx, headersReceived, cancel := CreateStreamContext(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := server.Client().Do(req)
+		headersReceived()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, err := ReadResponseBody(resp)
+		if err != nil || string(body) != "first\nsecond\n" {
+			t.Errorf("expected the full body, got %q (%v)", body, err)
+		}
+	})
+
+	t.Run("parent deadline applies to the whole stream", func(t *testing.T) {
+		parent, parentCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer parentCancel()
+
+		ctx, headersReceived, cancel := CreateStreamContext(parent, time.Millisecond)
+		defer cancel()
+		headersReceived()
+
+		parentDeadline, _ := parent.Deadline()
+		if deadline, ok := ctx.Deadline(); !ok || !deadline.Equal(parentDeadline) {
+			t.Errorf("expected parent deadline %v, got %v", parentDeadline, deadline)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if ctx.Err() != nil {
+			t.Errorf("expected no header timeout under a parent deadline, got %v", ctx.Err())
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const maxEventLineSize = 1024 * 1024
+
+var ErrStreamDone = errors.New("event stream done")
+
+func ReadEventStream(body io.Reader, handle func(data string) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLineSize)
+
+	var data []string
+	dispatch := func() error {
+		if len(data) == 0 {
+			return nil
+		}
+		event := strings.Join(data, "\n")
+		data = data[:0]
+		return handle(event)
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := dispatch(); err != nil {
+				return stopOnDone(err)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		if field == "data" {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %v", err)
+	}
+	return stopOnDone(dispatch())
+}
+
+func stopOnDone(err error) error {
+	if errors.Is(err, ErrStreamDone) {
+		return nil
+	}
+	return err
+}
+
+func ReadLineStream(body io.Reader, handle func(line []byte) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLineSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		if err := handle(line); err != nil {
+			return stopOnDone(err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read line stream: %v", err)
+	}
+	return nil
+}
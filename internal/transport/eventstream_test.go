@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadEventStream(t *testing.T) {
+	t.Run("dispatches data events", func(t *testing.T) {
+		body := ": keep-alive\n\ndata: first\n\nevent: message\ndata: second\ndata: line\n\ndata:third"
+
+		var events []string
+		err := ReadEventStream(strings.NewReader(body), func(data string) error {
+			events = append(events, data)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []string{"first", "second\nline", "third"}
+		if len(events) != len(expected) {
+			t.Fatalf("expected %d events, got %d: %v", len(expected), len(events), events)
+		}
+		for i := range expected {
+			if events[i] != expected[i] {
+				t.Errorf("expected event %d to be '%s', got '%s'", i, expected[i], events[i])
+			}
+		}
+	})
+
+	t.Run("stops on ErrStreamDone", func(t *testing.T) {
+		body := "data: one\n\ndata: [DONE]\n\ndata: ignored\n\n"
+
+		count := 0
+		err := ReadEventStream(strings.NewReader(body), func(data string) error {
+			count++
+			if data == "[DONE]" {
+				return ErrStreamDone
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 events before stopping, got %d", count)
+		}
+	})
+
+	t.Run("propagates handler errors", func(t *testing.T) {
+		err := ReadEventStream(strings.NewReader("data: one\n\n"), func(data string) error {
+			return errors.New("handler failed")
+		})
+		if err == nil || err.Error() != "handler failed" {
+			t.Errorf("expected 'handler failed', got %v", err)
+		}
+	})
+}
+
+func TestReadLineStream(t *testing.T) {
+	body := "{\"n\":1}\n\n{\"n\":2}\n{\"n\":3}\n"
+
+	var lines []string
+	err := ReadLineStream(strings.NewReader(body), func(line []byte) error {
+		lines = append(lines, string(line))
+		if len(lines) == 2 {
+			return ErrStreamDone
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != `{"n":1}` || lines[1] != `{"n":2}` {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+}
@@ -0,0 +1,214 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"agentic-ai-framework/internal/types"
+)
+
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultMaxElapsed     = 2 * time.Minute
+	DefaultMultiplier     = 2.0
+	DefaultJitter         = 0.2
+)
+
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxElapsed     time.Duration
+	Multiplier     float64
+	Jitter         float64
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+		MaxElapsed:     DefaultMaxElapsed,
+		Multiplier:     DefaultMultiplier,
+		Jitter:         DefaultJitter,
+	}
+}
+
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func ExecuteRequestWithRetry(client *http.Client, req *http.Request, policy RetryPolicy) (*http.Response, int, error) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	ctx := req.Context()
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+
+		resp, err := ExecuteRequest(client, attemptReq)
+		if err == nil && !types.IsRetryableStatus(resp.StatusCode) {
+			return resp, attempt, nil
+		}
+		if err != nil && !IsRetryableError(ctx, err) {
+			return nil, attempt, wrapAttempts(attempt, err)
+		}
+		if attempt >= maxAttempts {
+			return resp, attempt, wrapAttempts(attempt, err)
+		}
+
+		wait := policy.Backoff(attempt)
+		if resp != nil {
+			if serverWait, ok := RetryAfter(resp); ok {
+				wait = serverWait
+			}
+		}
+		if policy.MaxElapsed > 0 && time.Since(start)+wait > policy.MaxElapsed {
+			return resp, attempt, wrapAttempts(attempt, err)
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, attempt, wrapAttempts(attempt, err)
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, attempt, wrapAttempts(attempt, err)
+		}
+	}
+}
+
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = DefaultInitialBackoff
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultMultiplier
+	}
+
+	backoff := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff -= backoff * p.Jitter * rand.Float64()
+	}
+	return time.Duration(backoff)
+}
+
+func IsRetryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	header := resp.Header
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return max(time.Until(date), 0), true
+		}
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	var wait time.Duration
+	found := false
+	for _, key := range []string{"x-ratelimit-reset-requests", "x-ratelimit-reset-tokens"} {
+		if d, err := time.ParseDuration(header.Get(key)); err == nil {
+			wait = max(wait, d)
+			found = true
+		}
+	}
+	return wait, found
+}
+
+func ParseRateLimit(header http.Header) types.RateLimit {
+	return types.NewRateLimit(parseRateLimitWindow(header, "requests"), parseRateLimitWindow(header, "tokens"))
+}
+
+func parseRateLimitWindow(header http.Header, name string) types.RateLimitWindow {
+	limit, err := strconv.Atoi(header.Get("x-ratelimit-limit-" + name))
+	if err != nil || limit <= 0 {
+		return types.RateLimitWindow{}
+	}
+	remaining, err := strconv.Atoi(header.Get("x-ratelimit-remaining-" + name))
+	if err != nil {
+		return types.RateLimitWindow{}
+	}
+	reset, _ := time.ParseDuration(header.Get("x-ratelimit-reset-" + name))
+	return types.NewRateLimitWindow(limit, max(remaining, 0), reset)
+}
+
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rewind request body: %v", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+func wrapAttempts(attempts int, err error) error {
+	if err == nil || attempts <= 1 {
+		return err
+	}
+	return &RetryError{Attempts: attempts, Err: err}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
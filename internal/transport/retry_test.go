@@ -0,0 +1,328 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestExecuteRequestWithRetry(t *testing.T) {
+	t.Run("retries transient failures until success", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"prompt":"hi"}` {
+				t.Errorf("expected body to be replayed, got %q", body)
+			}
+			if requests < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		req, _ := CreateJSONRequest(context.Background(), "POST", server.URL, map[string]any{"prompt": "hi"}, nil)
+		resp, attempts, err := ExecuteRequestWithRetry(server.Client(), req, testRetryPolicy())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("returns last response when attempts are exhausted", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		req, _ := CreateJSONRequest(context.Background(), "GET", server.URL, nil, nil)
+		resp, attempts, err := ExecuteRequestWithRetry(server.Client(), req, testRetryPolicy())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("expected status 429, got %d", resp.StatusCode)
+		}
+		if attempts != 3 || requests != 3 {
+			t.Errorf("expected 3 attempts, got %d (%d requests)", attempts, requests)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		req, _ := CreateJSONRequest(context.Background(), "GET", server.URL, nil, nil)
+		resp, attempts, err := ExecuteRequestWithRetry(server.Client(), req, testRetryPolicy())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if attempts != 1 || requests != 1 {
+			t.Errorf("expected a single attempt, got %d (%d requests)", attempts, requests)
+		}
+	})
+
+	t.Run("zero policy sends a single request", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		req, _ := CreateJSONRequest(context.Background(), "GET", server.URL, nil, nil)
+		resp, attempts, err := ExecuteRequestWithRetry(server.Client(), req, RetryPolicy{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if attempts != 1 || requests != 1 {
+			t.Errorf("expected a single attempt, got %d (%d requests)", attempts, requests)
+		}
+	})
+
+	t.Run("retries dropped connections", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		req, _ := CreateJSONRequest(context.Background(), "POST", server.URL, map[string]any{"prompt": "hi"}, nil)
+		resp, attempts, err := ExecuteRequestWithRetry(server.Client(), req, testRetryPolicy())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if attempts != 2 {
+			t.Errorf("expected 2 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("reports attempts when every attempt fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		}))
+		defer server.Close()
+
+		req, _ := CreateJSONRequest(context.Background(), "GET", server.URL, nil, nil)
+		_, attempts, err := ExecuteRequestWithRetry(server.Client(), req, testRetryPolicy())
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("expected RetryError, got %v", err)
+		}
+		if retryErr.Attempts != 3 || attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d/%d", retryErr.Attempts, attempts)
+		}
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		requests := 0
+		var firstRequest, secondRequest time.Time
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				firstRequest = time.Now()
+				w.Header().Set("Retry-After", "0.2")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			secondRequest = time.Now()
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		req, _ := CreateJSONRequest(context.Background(), "GET", server.URL, nil, nil)
+		resp, _, err := ExecuteRequestWithRetry(server.Client(), req, testRetryPolicy())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if wait := secondRequest.Sub(firstRequest); wait < 200*time.Millisecond {
+			t.Errorf("expected to wait at least 200ms, waited %v", wait)
+		}
+	})
+
+	t.Run("stops when the time budget would be exceeded", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		policy := testRetryPolicy()
+		policy.MaxElapsed = time.Second
+
+		req, _ := CreateJSONRequest(context.Background(), "GET", server.URL, nil, nil)
+		resp, attempts, err := ExecuteRequestWithRetry(server.Client(), req, policy)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if attempts != 1 || requests != 1 {
+			t.Errorf("expected to give up after 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("context cancellation interrupts backoff", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		policy := testRetryPolicy()
+		policy.InitialBackoff = time.Minute
+		policy.MaxBackoff = time.Minute
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		req, _ := CreateJSONRequest(ctx, "GET", server.URL, nil, nil)
+		start := time.Now()
+		_, _, err := ExecuteRequestWithRetry(server.Client(), req, policy)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Error("expected cancellation to interrupt the backoff")
+		}
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Run("grows exponentially up to the cap", func(t *testing.T) {
+		policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+		expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+		for i, want := range expected {
+			if got := policy.Backoff(i + 1); got != want {
+				t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+			}
+		}
+	})
+
+	t.Run("jitter stays within bounds", func(t *testing.T) {
+		policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2, Jitter: 0.5}
+		for range 100 {
+			got := policy.Backoff(1)
+			if got < 50*time.Millisecond || got > 100*time.Millisecond {
+				t.Fatalf("expected backoff within [50ms, 100ms], got %v", got)
+			}
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Run("seconds", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+		resp.Header.Set("Retry-After", "2")
+		wait, ok := RetryAfter(resp)
+		if !ok || wait != 2*time.Second {
+			t.Errorf("expected 2s, got %v (%t)", wait, ok)
+		}
+	})
+
+	t.Run("http date", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		wait, ok := RetryAfter(resp)
+		if !ok || wait < 59*time.Minute || wait > time.Hour {
+			t.Errorf("expected about 1h, got %v (%t)", wait, ok)
+		}
+	})
+
+	t.Run("rate limit reset headers", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		resp.Header.Set("x-ratelimit-reset-requests", "1s")
+		resp.Header.Set("x-ratelimit-reset-tokens", "6m0s")
+		wait, ok := RetryAfter(resp)
+		if !ok || wait != 6*time.Minute {
+			t.Errorf("expected 6m, got %v (%t)", wait, ok)
+		}
+	})
+
+	t.Run("rate limit reset headers are ignored on server errors", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}}
+		resp.Header.Set("x-ratelimit-reset-tokens", "6m0s")
+		if _, ok := RetryAfter(resp); ok {
+			t.Error("expected no wait hint for a 500")
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, ok := RetryAfter(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}); ok {
+			t.Error("expected no wait hint")
+		}
+	})
+}
+
+func TestParseRateLimit(t *testing.T) {
+	header := http.Header{}
+	header.Set("x-ratelimit-limit-requests", "500")
+	header.Set("x-ratelimit-remaining-requests", "499")
+	header.Set("x-ratelimit-reset-requests", "120ms")
+	header.Set("x-ratelimit-limit-tokens", "30000")
+	header.Set("x-ratelimit-remaining-tokens", "29000")
+	header.Set("x-ratelimit-reset-tokens", "2s")
+
+	limit := ParseRateLimit(header)
+	if requests := limit.Requests(); requests.Limit() != 500 || requests.Remaining() != 499 || requests.Reset() != 120*time.Millisecond {
+		t.Errorf("unexpected request window: %+v", requests)
+	}
+	if tokens := limit.Tokens(); tokens.Limit() != 30000 || tokens.Remaining() != 29000 || tokens.Reset() != 2*time.Second {
+		t.Errorf("unexpected token window: %+v", tokens)
+	}
+
+	header.Del("x-ratelimit-remaining-tokens")
+	if limit := ParseRateLimit(header); !limit.Requests().Known() || limit.Tokens().Known() {
+		t.Errorf("expected incomplete token window to be unknown, got %+v", limit)
+	}
+	if ParseRateLimit(http.Header{}).Known() {
+		t.Error("expected no rate limit without headers")
+	}
+}
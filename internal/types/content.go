@@ -0,0 +1,135 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ContentPartText  = "text"
+	ContentPartImage = "image"
+	ContentPartFile  = "file"
+)
+
+type ContentPart struct {
+	partType string
+	text     string
+	url      string
+	data     []byte
+	mimeType string
+	filename string
+	detail   string
+}
+
+func (p ContentPart) Type() string {
+	return p.partType
+}
+
+func (p ContentPart) Text() string {
+	return p.text
+}
+
+func (p ContentPart) URL() string {
+	return p.url
+}
+
+func (p ContentPart) Data() []byte {
+	return p.data
+}
+
+func (p ContentPart) MimeType() string {
+	return p.mimeType
+}
+
+func (p ContentPart) Filename() string {
+	return p.filename
+}
+
+func (p ContentPart) Detail() string {
+	return p.detail
+}
+
+func (p ContentPart) Base64() string {
+	return base64.StdEncoding.EncodeToString(p.data)
+}
+
+func (p ContentPart) DataURL() string {
+	if p.url != "" {
+		return p.url
+	}
+	return "data:" + p.mimeType + ";base64," + p.Base64()
+}
+
+func (p ContentPart) WithDetail(detail string) ContentPart {
+	p.detail = detail
+	return p
+}
+
+func NewTextPart(text string) ContentPart {
+	return ContentPart{partType: ContentPartText, text: text}
+}
+
+func NewImageURLPart(url string) ContentPart {
+	return ContentPart{partType: ContentPartImage, url: url}
+}
+
+func NewImagePart(data []byte, mimeType string) ContentPart {
+	if mimeType == "" {
+		mimeType = DetectMimeType("", data)
+	}
+	return ContentPart{partType: ContentPartImage, data: data, mimeType: mimeType}
+}
+
+func NewImagePartFromReader(r io.Reader, mimeType string) (ContentPart, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	return NewImagePart(data, mimeType), nil
+}
+
+func NewImagePartFromFile(path string) (ContentPart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	mimeType := DetectMimeType(path, data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return ContentPart{}, fmt.Errorf("%s is not an image (detected %s)", path, mimeType)
+	}
+	return NewImagePart(data, mimeType), nil
+}
+
+func NewFilePart(filename string, data []byte, mimeType string) ContentPart {
+	if mimeType == "" {
+		mimeType = DetectMimeType(filename, data)
+	}
+	return ContentPart{partType: ContentPartFile, filename: filename, data: data, mimeType: mimeType}
+}
+
+func NewFilePartFromFile(path string) (ContentPart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	return NewFilePart(filepath.Base(path), data, ""), nil
+}
+
+func DetectMimeType(filename string, data []byte) string {
+	if ext := filepath.Ext(filename); ext != "" {
+		if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+			mediaType, _, err := mime.ParseMediaType(mimeType)
+			if err == nil {
+				return mediaType
+			}
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	return mediaType
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestContentParts(t *testing.T) {
+	t.Run("image from bytes detects mime type", func(t *testing.T) {
+		part := NewImagePart(pngHeader, "")
+		if part.Type() != ContentPartImage || part.MimeType() != "image/png" {
+			t.Errorf("unexpected image part: type=%s mime=%s", part.Type(), part.MimeType())
+		}
+		if !strings.HasPrefix(part.DataURL(), "data:image/png;base64,iVBORw0KGgo") {
+			t.Errorf("unexpected data URL: %s", part.DataURL())
+		}
+	})
+
+	t.Run("image URL", func(t *testing.T) {
+		part := NewImageURLPart("https://example.com/page-1.png").WithDetail("high")
+		if part.DataURL() != "https://example.com/page-1.png" || part.Detail() != "high" {
+			t.Errorf("unexpected image URL part: %+v", part)
+		}
+	})
+
+	t.Run("image from reader", func(t *testing.T) {
+		part, err := NewImagePartFromReader(strings.NewReader(string(pngHeader)), "image/webp")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if part.MimeType() != "image/webp" || len(part.Data()) != len(pngHeader) {
+			t.Errorf("expected explicit mime type to be kept, got %s", part.MimeType())
+		}
+	})
+
+	t.Run("image from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "page.jpg")
+		if err := os.WriteFile(path, pngHeader, 0644); err != nil {
+			t.Fatalf("failed to write image: %v", err)
+		}
+
+		part, err := NewImagePartFromFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if part.MimeType() != "image/jpeg" {
+			t.Errorf("expected mime type from extension, got %s", part.MimeType())
+		}
+	})
+
+	t.Run("non-image file is rejected as image", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notes.txt")
+		if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		if _, err := NewImagePartFromFile(path); err == nil {
+			t.Error("expected error when loading a text file as an image")
+		}
+	})
+
+	t.Run("file from path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.pdf")
+		if err := os.WriteFile(path, []byte("%PDF-1.7\n"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		part, err := NewFilePartFromFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if part.Type() != ContentPartFile || part.Filename() != "report.pdf" || part.MimeType() != "application/pdf" {
+			t.Errorf("unexpected file part: type=%s filename=%s mime=%s", part.Type(), part.Filename(), part.MimeType())
+		}
+	})
+}
+
+func TestDetectMimeType(t *testing.T) {
+	if mimeType := DetectMimeType("scan.png", nil); mimeType != "image/png" {
+		t.Errorf("expected image/png from extension, got %s", mimeType)
+	}
+	if mimeType := DetectMimeType("", []byte("%PDF-1.7\n")); mimeType != "application/pdf" {
+		t.Errorf("expected application/pdf from content, got %s", mimeType)
+	}
+	if mimeType := DetectMimeType("", []byte("plain words")); mimeType != "text/plain" {
+		t.Errorf("expected text/plain without charset, got %s", mimeType)
+	}
+}
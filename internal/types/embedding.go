@@ -0,0 +1,71 @@
+package types
+
+import (
+	"cmp"
+	"slices"
+)
+
+type Embedding struct {
+	index  int
+	vector []float64
+}
+
+func (e Embedding) Index() int {
+	return e.index
+}
+
+func (e Embedding) Vector() []float64 {
+	return e.vector
+}
+
+func NewEmbedding(index int, vector []float64) Embedding {
+	return Embedding{index: index, vector: vector}
+}
+
+type EmbeddingResult struct {
+	embeddings []Embedding
+	tokenUsage TokenUsage
+	model      string
+	attempts   int
+}
+
+func (r *EmbeddingResult) Embeddings() []Embedding {
+	return r.embeddings
+}
+
+func (r *EmbeddingResult) Vectors() [][]float64 {
+	embeddings := slices.SortedStableFunc(slices.Values(r.embeddings), func(a, b Embedding) int {
+		return cmp.Compare(a.index, b.index)
+	})
+	vectors := make([][]float64, len(embeddings))
+	for i, embedding := range embeddings {
+		vectors[i] = embedding.vector
+	}
+	return vectors
+}
+
+func (r *EmbeddingResult) Usage() TokenUsage {
+	return r.tokenUsage
+}
+
+func (r *EmbeddingResult) Model() string {
+	return r.model
+}
+
+func (r *EmbeddingResult) Attempts() int {
+	return r.attempts
+}
+
+func (r EmbeddingResult) WithModel(model string) EmbeddingResult {
+	r.model = model
+	return r
+}
+
+func (r EmbeddingResult) WithAttempts(attempts int) EmbeddingResult {
+	r.attempts = attempts
+	return r
+}
+
+func NewEmbeddingResult(embeddings []Embedding, usage TokenUsage) EmbeddingResult {
+	return EmbeddingResult{embeddings: embeddings, tokenUsage: usage}
+}
@@ -0,0 +1,37 @@
+package types
+
+import "testing"
+
+func TestEmbeddingResult(t *testing.T) {
+	result := NewEmbeddingResult([]Embedding{
+		NewEmbedding(0, []float64{0.1, 0.2}),
+		NewEmbedding(1, []float64{0.3, 0.4}),
+	}, NewTokenUsage(8, 0, 8)).WithModel("text-embedding-3-small").WithAttempts(2)
+
+	if len(result.Embeddings()) != 2 || result.Embeddings()[1].Index() != 1 {
+		t.Errorf("unexpected embeddings: %v", result.Embeddings())
+	}
+	vectors := result.Vectors()
+	if len(vectors) != 2 || vectors[1][0] != 0.3 {
+		t.Errorf("unexpected vectors: %v", vectors)
+	}
+	if result.Usage().TotalTokens() != 8 || result.Model() != "text-embedding-3-small" || result.Attempts() != 2 {
+		t.Errorf("unexpected metadata: usage=%d model=%s attempts=%d", result.Usage().TotalTokens(), result.Model(), result.Attempts())
+	}
+}
+
+func TestEmbeddingResultVectorsOrder(t *testing.T) {
+	result := NewEmbeddingResult([]Embedding{
+		NewEmbedding(2, []float64{0.3}),
+		NewEmbedding(0, []float64{0.1}),
+		NewEmbedding(1, []float64{0.2}),
+	}, TokenUsage{})
+
+	vectors := result.Vectors()
+	if len(vectors) != 3 || vectors[0][0] != 0.1 || vectors[1][0] != 0.2 || vectors[2][0] != 0.3 {
+		t.Errorf("expected vectors in index order, got %v", vectors)
+	}
+	if result.Embeddings()[0].Index() != 2 {
+		t.Errorf("expected embeddings to keep their response order, got %v", result.Embeddings())
+	}
+}
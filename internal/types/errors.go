@@ -0,0 +1,160 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrModelNotFound    = errors.New("model not found")
+	ErrInvalidParameter = errors.New("invalid request parameter")
+	ErrInvalidConfig    = errors.New("invalid configuration")
+	ErrProviderNotFound = errors.New("provider not found")
+	ErrUnsupportedInput = errors.New("unsupported input")
+	ErrNotSupported     = errors.New("not supported")
+	ErrContextOverflow  = errors.New("context window exceeded")
+	ErrBudgetExceeded   = errors.New("budget exceeded")
+)
+
+type ModelNotFoundError struct {
+	Model    string
+	Provider string
+}
+
+func (e *ModelNotFoundError) Error() string {
+	return fmt.Sprintf("model %s is not available in provider %s", e.Model, e.Provider)
+}
+
+func (e *ModelNotFoundError) Is(target error) bool {
+	return target == ErrModelNotFound
+}
+
+type ProviderNotFoundError struct {
+	ID string
+}
+
+func (e *ProviderNotFoundError) Error() string {
+	return fmt.Sprintf("provider %s is not registered", e.ID)
+}
+
+func (e *ProviderNotFoundError) Is(target error) bool {
+	return target == ErrProviderNotFound
+}
+
+type UnsupportedInputError struct {
+	Model     string
+	InputType string
+}
+
+func (e *UnsupportedInputError) Error() string {
+	return fmt.Sprintf("model %s does not accept %s input", e.Model, e.InputType)
+}
+
+func (e *UnsupportedInputError) Is(target error) bool {
+	return target == ErrUnsupportedInput
+}
+
+type ContextOverflowError struct {
+	Model         string
+	PromptTokens  int
+	OutputTokens  int
+	ContextWindow int
+}
+
+func (e *ContextOverflowError) Error() string {
+	return fmt.Sprintf("model %s has a context window of %d tokens, request needs an estimated %d prompt tokens plus %d output tokens", e.Model, e.ContextWindow, e.PromptTokens, e.OutputTokens)
+}
+
+func (e *ContextOverflowError) Is(target error) bool {
+	return target == ErrContextOverflow
+}
+
+type BudgetExceededError struct {
+	Dimension string
+	Value     string
+	Limit     float64
+	Spent     float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("budget for %s %s exhausted: spent $%.4f of $%.4f", e.Dimension, e.Value, e.Spent, e.Limit)
+}
+
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+type ParameterError struct {
+	Parameter string
+	Model     string
+	Available []string
+	Reason    string
+}
+
+func (e *ParameterError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("request parameter '%s' is invalid for model %s: %s", e.Parameter, e.Model, e.Reason)
+	}
+	return fmt.Sprintf("request parameter '%s' is not available for model %s. Available parameters: %v", e.Parameter, e.Model, e.Available)
+}
+
+func (e *ParameterError) Is(target error) bool {
+	return target == ErrInvalidParameter
+}
+
+type APIError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+	RequestID  string
+	Retryable  bool
+	Attempts   int
+	RateLimit  RateLimit
+}
+
+func NewAPIError(statusCode int, errorType, code, message, requestID string) *APIError {
+	return &APIError{
+		StatusCode: statusCode,
+		Type:       errorType,
+		Code:       code,
+		Message:    message,
+		RequestID:  requestID,
+		Retryable:  IsRetryableStatus(statusCode),
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API request failed with status %d", e.StatusCode)
+	}
+
+	msg := "API error"
+	if e.StatusCode != 0 && e.StatusCode != http.StatusOK {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	msg += ": " + e.Message
+
+	switch {
+	case e.Type != "" && e.Code != "":
+		msg += fmt.Sprintf(" (type: %s, code: %s)", e.Type, e.Code)
+	case e.Type != "":
+		msg += fmt.Sprintf(" (type: %s)", e.Type)
+	case e.Code != "":
+		msg += fmt.Sprintf(" (code: %s)", e.Code)
+	}
+	return msg
+}
+
+func IsRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusConflict ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable
+}
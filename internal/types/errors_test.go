@@ -0,0 +1,121 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestModelNotFoundError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ModelNotFoundError{Model: "gpt-0", Provider: "OpenAI"})
+
+	if !errors.Is(err, ErrModelNotFound) {
+		t.Error("expected error to match ErrModelNotFound")
+	}
+	var modelErr *ModelNotFoundError
+	if !errors.As(err, &modelErr) || modelErr.Model != "gpt-0" {
+		t.Errorf("expected ModelNotFoundError for gpt-0, got %v", modelErr)
+	}
+}
+
+func TestProviderNotFoundError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ProviderNotFoundError{ID: "mistral"})
+
+	if !errors.Is(err, ErrProviderNotFound) {
+		t.Error("expected error to match ErrProviderNotFound")
+	}
+	if err.Error() != "wrapped: provider mistral is not registered" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+}
+
+func TestUnsupportedInputError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &UnsupportedInputError{Model: "gpt-3.5-turbo", InputType: ContentPartImage})
+
+	if !errors.Is(err, ErrUnsupportedInput) {
+		t.Error("expected error to match ErrUnsupportedInput")
+	}
+	if err.Error() != "wrapped: model gpt-3.5-turbo does not accept image input" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+}
+
+func TestContextOverflowError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &ContextOverflowError{Model: "gpt-4.1", PromptTokens: 1000, OutputTokens: 500, ContextWindow: 1024})
+
+	if !errors.Is(err, ErrContextOverflow) {
+		t.Error("expected error to match ErrContextOverflow")
+	}
+	if err.Error() != "wrapped: model gpt-4.1 has a context window of 1024 tokens, request needs an estimated 1000 prompt tokens plus 500 output tokens" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+}
+
+func TestBudgetExceededError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &BudgetExceededError{Dimension: "tenant", Value: "acme", Limit: 10, Spent: 10.25})
+
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Error("expected error to match ErrBudgetExceeded")
+	}
+	if err.Error() != "wrapped: budget for tenant acme exhausted: spent $10.2500 of $10.0000" {
+		t.Errorf("unexpected message: %s", err.Error())
+	}
+}
+
+func TestParameterError(t *testing.T) {
+	err := &ParameterError{Parameter: "max_tokens", Model: "gpt-5", Available: []string{}}
+
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Error("expected error to match ErrInvalidParameter")
+	}
+	if errors.Is(err, ErrModelNotFound) {
+		t.Error("expected error not to match ErrModelNotFound")
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	t.Run("message formats", func(t *testing.T) {
+		tests := []struct {
+			err      *APIError
+			expected string
+		}{
+			{NewAPIError(401, "authentication_error", "invalid_api_key", "Invalid API key", ""), "API error (status 401): Invalid API key (type: authentication_error, code: invalid_api_key)"},
+			{NewAPIError(500, "", "", "", ""), "API request failed with status 500"},
+			{NewAPIError(0, "server_error", "", "overloaded", ""), "API error: overloaded (type: server_error)"},
+			{NewAPIError(404, "", "", "model not found", ""), "API error (status 404): model not found"},
+		}
+		for _, tt := range tests {
+			if tt.err.Error() != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, tt.err.Error())
+			}
+		}
+	})
+
+	t.Run("retryable classification", func(t *testing.T) {
+		retryable := []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable, 529}
+		for _, status := range retryable {
+			if !IsRetryable(NewAPIError(status, "", "", "", "")) {
+				t.Errorf("expected status %d to be retryable", status)
+			}
+		}
+		permanent := []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound}
+		for _, status := range permanent {
+			if IsRetryable(NewAPIError(status, "", "", "", "")) {
+				t.Errorf("expected status %d not to be retryable", status)
+			}
+		}
+	})
+
+	t.Run("errors.As", func(t *testing.T) {
+		err := fmt.Errorf("call failed: %w", NewAPIError(429, "rate_limit_exceeded", "", "slow down", "req_123"))
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatal("expected errors.As to find APIError")
+		}
+		if apiErr.StatusCode != 429 || apiErr.RequestID != "req_123" || !apiErr.Retryable {
+			t.Errorf("unexpected APIError: %+v", apiErr)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package types
+
+const (
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+type ResponseFormat struct {
+	formatType string
+	name       string
+	schema     map[string]any
+	strict     bool
+}
+
+func (f ResponseFormat) Type() string {
+	return f.formatType
+}
+
+func (f ResponseFormat) Name() string {
+	return f.name
+}
+
+func (f ResponseFormat) Schema() map[string]any {
+	return f.schema
+}
+
+func (f ResponseFormat) Strict() bool {
+	return f.strict
+}
+
+func NewJSONObjectFormat() ResponseFormat {
+	return ResponseFormat{formatType: ResponseFormatJSONObject}
+}
+
+func NewJSONSchemaFormat(name string, schema map[string]any, strict bool) ResponseFormat {
+	return ResponseFormat{
+		formatType: ResponseFormatJSONSchema,
+		name:       name,
+		schema:     schema,
+		strict:     strict,
+	}
+}
@@ -0,0 +1,26 @@
+package types
+
+import "testing"
+
+func TestResponseFormat(t *testing.T) {
+	t.Run("json object", func(t *testing.T) {
+		format := NewJSONObjectFormat()
+		if format.Type() != ResponseFormatJSONObject {
+			t.Errorf("expected type %s, got %s", ResponseFormatJSONObject, format.Type())
+		}
+		if format.Schema() != nil || format.Strict() {
+			t.Errorf("expected no schema and non-strict format, got %+v", format)
+		}
+	})
+
+	t.Run("json schema", func(t *testing.T) {
+		schema := map[string]any{"type": "object"}
+		format := NewJSONSchemaFormat("invoice", schema, true)
+		if format.Type() != ResponseFormatJSONSchema || format.Name() != "invoice" || !format.Strict() {
+			t.Errorf("unexpected format: %+v", format)
+		}
+		if format.Schema()["type"] != "object" {
+			t.Errorf("expected schema to be kept, got %v", format.Schema())
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package types
+
+import "strings"
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+type Message struct {
+	role       string
+	content    string
+	toolCalls  []ToolCall
+	toolCallID string
+	parts      []ContentPart
+}
+
+func (m Message) Role() string {
+	return m.role
+}
+
+func (m Message) Content() string {
+	return m.content
+}
+
+func (m Message) ToolCalls() []ToolCall {
+	return m.toolCalls
+}
+
+func (m Message) ToolCallID() string {
+	return m.toolCallID
+}
+
+func (m Message) Parts() []ContentPart {
+	return m.parts
+}
+
+func (m Message) HasMedia() bool {
+	for _, part := range m.parts {
+		if part.Type() != ContentPartText {
+			return true
+		}
+	}
+	return false
+}
+
+func NewMessage(role string, content string) Message {
+	return Message{role: role, content: content}
+}
+
+func NewSystemMessage(content string) Message {
+	return Message{role: RoleSystem, content: content}
+}
+
+func NewUserMessage(content string) Message {
+	return Message{role: RoleUser, content: content}
+}
+
+func NewUserMessageWithParts(parts ...ContentPart) Message {
+	var text []string
+	for _, part := range parts {
+		if part.Type() == ContentPartText {
+			text = append(text, part.Text())
+		}
+	}
+	return Message{role: RoleUser, content: strings.Join(text, "\n"), parts: parts}
+}
+
+func NewAssistantMessage(content string, toolCalls ...ToolCall) Message {
+	return Message{role: RoleAssistant, content: content, toolCalls: toolCalls}
+}
+
+func NewToolMessage(toolCallID string, content string) Message {
+	return Message{role: RoleTool, content: content, toolCallID: toolCallID}
+}
+
+type ToolCall struct {
+	id        string
+	name      string
+	arguments string
+}
+
+func (c ToolCall) ID() string {
+	return c.id
+}
+
+func (c ToolCall) Name() string {
+	return c.name
+}
+
+func (c ToolCall) Arguments() string {
+	return c.arguments
+}
+
+func NewToolCall(id, name, arguments string) ToolCall {
+	return ToolCall{id: id, name: name, arguments: arguments}
+}
+
+type ToolDefinition struct {
+	name        string
+	description string
+	parameters  map[string]any
+}
+
+func (d ToolDefinition) Name() string {
+	return d.name
+}
+
+func (d ToolDefinition) Description() string {
+	return d.description
+}
+
+func (d ToolDefinition) Parameters() map[string]any {
+	return d.parameters
+}
+
+func NewToolDefinition(name, description string, parameters map[string]any) ToolDefinition {
+	return ToolDefinition{name: name, description: description, parameters: parameters}
+}
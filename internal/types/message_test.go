@@ -0,0 +1,68 @@
+package types
+
+import "testing"
+
+func TestMessageConstructors(t *testing.T) {
+	system := NewSystemMessage("Be brief")
+	if system.Role() != RoleSystem || system.Content() != "Be brief" {
+		t.Errorf("unexpected system message: %+v", system)
+	}
+
+	user := NewUserMessage("Hello")
+	if user.Role() != RoleUser || user.Content() != "Hello" {
+		t.Errorf("unexpected user message: %+v", user)
+	}
+
+	generic := NewMessage(RoleAssistant, "Hi there")
+	if generic.Role() != RoleAssistant || generic.Content() != "Hi there" {
+		t.Errorf("unexpected generic message: %+v", generic)
+	}
+
+	call := NewToolCall("call_1", "get_weather", `{"city":"Paris"}`)
+	assistant := NewAssistantMessage("", call)
+	if assistant.Role() != RoleAssistant {
+		t.Errorf("expected role 'assistant', got '%s'", assistant.Role())
+	}
+	if len(assistant.ToolCalls()) != 1 || assistant.ToolCalls()[0].Name() != "get_weather" {
+		t.Errorf("unexpected tool calls: %v", assistant.ToolCalls())
+	}
+
+	tool := NewToolMessage("call_1", "sunny")
+	if tool.Role() != RoleTool || tool.ToolCallID() != "call_1" || tool.Content() != "sunny" {
+		t.Errorf("unexpected tool message: %+v", tool)
+	}
+}
+
+func TestNewUserMessageWithParts(t *testing.T) {
+	msg := NewUserMessageWithParts(
+		NewTextPart("What is on this page?"),
+		NewImageURLPart("https://example.com/page-1.png"),
+		NewTextPart("Answer briefly."),
+	)
+
+	if msg.Role() != RoleUser || msg.Content() != "What is on this page?\nAnswer briefly." {
+		t.Errorf("unexpected message: role=%s content=%q", msg.Role(), msg.Content())
+	}
+	if len(msg.Parts()) != 3 || !msg.HasMedia() {
+		t.Errorf("expected 3 parts including media, got %v", msg.Parts())
+	}
+	if NewUserMessageWithParts(NewTextPart("Hi")).HasMedia() {
+		t.Error("expected text-only message not to report media")
+	}
+}
+
+func TestToolAccessors(t *testing.T) {
+	call := NewToolCall("call_1", "get_weather", `{"city":"Paris"}`)
+	if call.ID() != "call_1" || call.Name() != "get_weather" || call.Arguments() != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+
+	schema := map[string]any{"type": "object"}
+	definition := NewToolDefinition("get_weather", "Get the weather", schema)
+	if definition.Name() != "get_weather" || definition.Description() != "Get the weather" {
+		t.Errorf("unexpected tool definition: %+v", definition)
+	}
+	if definition.Parameters()["type"] != "object" {
+		t.Errorf("expected parameters to be preserved, got %v", definition.Parameters())
+	}
+}
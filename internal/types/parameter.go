@@ -0,0 +1,197 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+type ParameterType string
+
+const (
+	ParameterNumber     ParameterType = "number"
+	ParameterInteger    ParameterType = "integer"
+	ParameterString     ParameterType = "string"
+	ParameterBoolean    ParameterType = "boolean"
+	ParameterStringList ParameterType = "string_list"
+	ParameterAny        ParameterType = "any"
+)
+
+type Parameter struct {
+	name          string
+	parameterType ParameterType
+	minimum       *float64
+	maximum       *float64
+	enum          []any
+	defaultValue  any
+	aliases       []string
+	exclusiveWith []string
+}
+
+func NewParameter(name string, parameterType ParameterType) Parameter {
+	return Parameter{name: name, parameterType: parameterType}
+}
+
+func (p Parameter) Name() string {
+	return p.name
+}
+
+func (p Parameter) Type() ParameterType {
+	return p.parameterType
+}
+
+func (p Parameter) Minimum() (float64, bool) {
+	if p.minimum == nil {
+		return 0, false
+	}
+	return *p.minimum, true
+}
+
+func (p Parameter) Maximum() (float64, bool) {
+	if p.maximum == nil {
+		return 0, false
+	}
+	return *p.maximum, true
+}
+
+func (p Parameter) Enum() []any {
+	return p.enum
+}
+
+func (p Parameter) Default() any {
+	return p.defaultValue
+}
+
+func (p Parameter) Aliases() []string {
+	return p.aliases
+}
+
+func (p Parameter) ExclusiveWith() []string {
+	return p.exclusiveWith
+}
+
+func (p Parameter) WithRange(minimum, maximum float64) Parameter {
+	p.minimum = &minimum
+	p.maximum = &maximum
+	return p
+}
+
+func (p Parameter) WithMinimum(minimum float64) Parameter {
+	p.minimum = &minimum
+	return p
+}
+
+func (p Parameter) WithEnum(values ...any) Parameter {
+	p.enum = values
+	return p
+}
+
+func (p Parameter) WithDefault(value any) Parameter {
+	p.defaultValue = value
+	return p
+}
+
+func (p Parameter) WithAliases(aliases ...string) Parameter {
+	p.aliases = aliases
+	return p
+}
+
+func (p Parameter) WithExclusive(names ...string) Parameter {
+	p.exclusiveWith = names
+	return p
+}
+
+func (p Parameter) Check(value any) error {
+	switch p.parameterType {
+	case ParameterNumber, ParameterInteger:
+		number, ok := ToFloat(value)
+		if !ok {
+			return fmt.Errorf("expected %s, got %T", p.parameterType, value)
+		}
+		if p.parameterType == ParameterInteger && number != math.Trunc(number) {
+			return fmt.Errorf("expected integer, got %v", value)
+		}
+		if p.minimum != nil && number < *p.minimum {
+			return p.rangeError(value)
+		}
+		if p.maximum != nil && number > *p.maximum {
+			return p.rangeError(value)
+		}
+	case ParameterString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected string, got %T", value)
+		}
+	case ParameterBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected boolean, got %T", value)
+		}
+	case ParameterStringList:
+		if !isStringList(value) {
+			return fmt.Errorf("expected string or list of strings, got %T", value)
+		}
+	}
+
+	if len(p.enum) > 0 && !slices.Contains(p.enum, value) {
+		return fmt.Errorf("expected one of %v, got %v", p.enum, value)
+	}
+	return nil
+}
+
+func (p Parameter) rangeError(value any) error {
+	switch {
+	case p.minimum != nil && p.maximum != nil:
+		return fmt.Errorf("must be between %v and %v, got %v", *p.minimum, *p.maximum, value)
+	case p.minimum != nil:
+		return fmt.Errorf("must be at least %v, got %v", *p.minimum, value)
+	default:
+		return fmt.Errorf("must be at most %v, got %v", *p.maximum, value)
+	}
+}
+
+func ParameterNames(parameters []Parameter) []string {
+	names := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		names[i] = parameter.Name()
+	}
+	return names
+}
+
+func FindParameter(parameters []Parameter, name string) (Parameter, bool) {
+	for _, parameter := range parameters {
+		if parameter.Name() == name || slices.Contains(parameter.Aliases(), name) {
+			return parameter, true
+		}
+	}
+	return Parameter{}, false
+}
+
+func ToFloat(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func isStringList(value any) bool {
+	switch list := value.(type) {
+	case string, []string:
+		return true
+	case []any:
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+package types
+
+import "testing"
+
+func TestParameter(t *testing.T) {
+	t.Run("descriptor accessors", func(t *testing.T) {
+		param := NewParameter("max_completion_tokens", ParameterInteger).
+			WithMinimum(1).
+			WithDefault(1024).
+			WithAliases("max_tokens").
+			WithExclusive("max_output_tokens")
+
+		if param.Name() != "max_completion_tokens" || param.Type() != ParameterInteger {
+			t.Errorf("unexpected descriptor: %+v", param)
+		}
+		if minimum, ok := param.Minimum(); !ok || minimum != 1 {
+			t.Errorf("expected minimum 1, got %v (%t)", minimum, ok)
+		}
+		if _, ok := param.Maximum(); ok {
+			t.Error("expected no maximum")
+		}
+		if param.Default() != 1024 || param.Aliases()[0] != "max_tokens" || param.ExclusiveWith()[0] != "max_output_tokens" {
+			t.Errorf("unexpected descriptor: %+v", param)
+		}
+	})
+
+	t.Run("check number", func(t *testing.T) {
+		param := NewParameter("temperature", ParameterNumber).WithRange(0, 2)
+		for _, value := range []any{0, 1.5, float32(2), int64(1), uint(0)} {
+			if err := param.Check(value); err != nil {
+				t.Errorf("expected %v to be valid, got %v", value, err)
+			}
+		}
+		for _, value := range []any{-0.1, 2.5, "1", nil} {
+			if err := param.Check(value); err == nil {
+				t.Errorf("expected %v to be invalid", value)
+			}
+		}
+	})
+
+	t.Run("check integer", func(t *testing.T) {
+		param := NewParameter("seed", ParameterInteger)
+		if err := param.Check(42.0); err != nil {
+			t.Errorf("expected integral float to be valid, got %v", err)
+		}
+		if err := param.Check(4.2); err == nil || err.Error() != "expected integer, got 4.2" {
+			t.Errorf("expected fractional value to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("check string list", func(t *testing.T) {
+		param := NewParameter("stop", ParameterStringList)
+		for _, value := range []any{"END", []string{"a", "b"}, []any{"a"}} {
+			if err := param.Check(value); err != nil {
+				t.Errorf("expected %v to be valid, got %v", value, err)
+			}
+		}
+		if err := param.Check([]any{"a", 1}); err == nil {
+			t.Error("expected list with non-string to be invalid")
+		}
+	})
+
+	t.Run("check enum", func(t *testing.T) {
+		param := NewParameter("reasoning_effort", ParameterString).WithEnum("low", "high")
+		if err := param.Check("low"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := param.Check("medium"); err == nil || err.Error() != "expected one of [low high], got medium" {
+			t.Errorf("expected enum error, got %v", err)
+		}
+	})
+
+	t.Run("any accepts everything", func(t *testing.T) {
+		param := NewParameter("response_format", ParameterAny)
+		if err := param.Check(map[string]any{"type": "json_object"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestFindParameter(t *testing.T) {
+	params := []Parameter{
+		NewParameter("temperature", ParameterNumber),
+		NewParameter("max_completion_tokens", ParameterInteger).WithAliases("max_tokens"),
+	}
+
+	if param, ok := FindParameter(params, "max_tokens"); !ok || param.Name() != "max_completion_tokens" {
+		t.Errorf("expected alias to resolve, got %v (%t)", param.Name(), ok)
+	}
+	if _, ok := FindParameter(params, "top_p"); ok {
+		t.Error("expected unknown parameter not to be found")
+	}
+	if names := ParameterNames(params); len(names) != 2 || names[1] != "max_completion_tokens" {
+		t.Errorf("unexpected names: %v", names)
+	}
+}
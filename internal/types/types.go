@@ -1,8 +1,34 @@
 package types
 
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	FinishReasonStop          = "stop"
+	FinishReasonLength        = "length"
+	FinishReasonToolCalls     = "tool_calls"
+	FinishReasonContentFilter = "content_filter"
+)
+
 type GenerateTextResult struct {
-	textContent string
-	tokenUsage  TokenUsage
+	textContent       string
+	tokenUsage        TokenUsage
+	toolCalls         []ToolCall
+	attempts          int
+	finishReason      string
+	responseID        string
+	model             string
+	systemFingerprint string
+	created           time.Time
+	refusal           string
+	choices           []Choice
+	rawResponse       json.RawMessage
+	estimatedPrompt   int
+	cost              float64
+	cacheHit          bool
+	rateLimit         RateLimit
 }
 
 func (r *GenerateTextResult) TextContent() string {
@@ -13,10 +39,185 @@ func (r *GenerateTextResult) Usage() TokenUsage {
 	return r.tokenUsage
 }
 
+func (r *GenerateTextResult) ToolCalls() []ToolCall {
+	return r.toolCalls
+}
+
+func (r *GenerateTextResult) Attempts() int {
+	return r.attempts
+}
+
+func (r *GenerateTextResult) FinishReason() string {
+	return r.finishReason
+}
+
+func (r *GenerateTextResult) Truncated() bool {
+	return r.finishReason == FinishReasonLength
+}
+
+func (r *GenerateTextResult) ResponseID() string {
+	return r.responseID
+}
+
+func (r *GenerateTextResult) Model() string {
+	return r.model
+}
+
+func (r *GenerateTextResult) SystemFingerprint() string {
+	return r.systemFingerprint
+}
+
+func (r *GenerateTextResult) Created() time.Time {
+	return r.created
+}
+
+func (r *GenerateTextResult) Refusal() string {
+	return r.refusal
+}
+
+func (r *GenerateTextResult) Choices() []Choice {
+	return r.choices
+}
+
+func (r *GenerateTextResult) RawResponse() json.RawMessage {
+	return r.rawResponse
+}
+
+func (r *GenerateTextResult) EstimatedPromptTokens() int {
+	return r.estimatedPrompt
+}
+
+func (r *GenerateTextResult) Cost() float64 {
+	return r.cost
+}
+
+func (r *GenerateTextResult) CacheHit() bool {
+	return r.cacheHit
+}
+
+func (r *GenerateTextResult) RateLimit() RateLimit {
+	return r.rateLimit
+}
+
+func (r GenerateTextResult) WithToolCalls(toolCalls []ToolCall) GenerateTextResult {
+	r.toolCalls = toolCalls
+	return r
+}
+
+func (r GenerateTextResult) WithUsage(tokenUsage TokenUsage) GenerateTextResult {
+	r.tokenUsage = tokenUsage
+	return r
+}
+
+func (r GenerateTextResult) WithAttempts(attempts int) GenerateTextResult {
+	r.attempts = attempts
+	return r
+}
+
+func (r GenerateTextResult) WithFinishReason(finishReason string) GenerateTextResult {
+	r.finishReason = finishReason
+	return r
+}
+
+func (r GenerateTextResult) WithResponseID(responseID string) GenerateTextResult {
+	r.responseID = responseID
+	return r
+}
+
+func (r GenerateTextResult) WithModel(model string) GenerateTextResult {
+	r.model = model
+	return r
+}
+
+func (r GenerateTextResult) WithSystemFingerprint(systemFingerprint string) GenerateTextResult {
+	r.systemFingerprint = systemFingerprint
+	return r
+}
+
+func (r GenerateTextResult) WithCreated(created time.Time) GenerateTextResult {
+	r.created = created
+	return r
+}
+
+func (r GenerateTextResult) WithRefusal(refusal string) GenerateTextResult {
+	r.refusal = refusal
+	return r
+}
+
+func (r GenerateTextResult) WithChoices(choices []Choice) GenerateTextResult {
+	r.choices = choices
+	return r
+}
+
+func (r GenerateTextResult) WithRawResponse(rawResponse json.RawMessage) GenerateTextResult {
+	r.rawResponse = rawResponse
+	return r
+}
+
+func (r GenerateTextResult) WithEstimatedPromptTokens(tokens int) GenerateTextResult {
+	r.estimatedPrompt = tokens
+	return r
+}
+
+func (r GenerateTextResult) WithCost(cost float64) GenerateTextResult {
+	r.cost = cost
+	return r
+}
+
+func (r GenerateTextResult) WithCacheHit(cacheHit bool) GenerateTextResult {
+	r.cacheHit = cacheHit
+	return r
+}
+
+func (r GenerateTextResult) WithRateLimit(rateLimit RateLimit) GenerateTextResult {
+	r.rateLimit = rateLimit
+	return r
+}
+
+type Choice struct {
+	index        int
+	textContent  string
+	finishReason string
+	toolCalls    []ToolCall
+	refusal      string
+}
+
+func (c Choice) Index() int {
+	return c.index
+}
+
+func (c Choice) TextContent() string {
+	return c.textContent
+}
+
+func (c Choice) FinishReason() string {
+	return c.finishReason
+}
+
+func (c Choice) ToolCalls() []ToolCall {
+	return c.toolCalls
+}
+
+func (c Choice) Refusal() string {
+	return c.refusal
+}
+
+func NewChoice(index int, textContent string, finishReason string, toolCalls []ToolCall, refusal string) Choice {
+	return Choice{
+		index:        index,
+		textContent:  textContent,
+		finishReason: finishReason,
+		toolCalls:    toolCalls,
+		refusal:      refusal,
+	}
+}
+
 type TokenUsage struct {
 	promptTokens     int
 	completionTokens int
 	totalTokens      int
+	cachedTokens     int
+	reasoningTokens  int
 }
 
 func (t TokenUsage) PromptTokens() int {
@@ -31,6 +232,81 @@ func (t TokenUsage) TotalTokens() int {
 	return t.totalTokens
 }
 
+func (t TokenUsage) CachedTokens() int {
+	return t.cachedTokens
+}
+
+func (t TokenUsage) ReasoningTokens() int {
+	return t.reasoningTokens
+}
+
+func (t TokenUsage) WithCachedTokens(cachedTokens int) TokenUsage {
+	t.cachedTokens = cachedTokens
+	return t
+}
+
+func (t TokenUsage) WithReasoningTokens(reasoningTokens int) TokenUsage {
+	t.reasoningTokens = reasoningTokens
+	return t
+}
+
+func (t TokenUsage) Add(other TokenUsage) TokenUsage {
+	return TokenUsage{
+		promptTokens:     t.promptTokens + other.promptTokens,
+		completionTokens: t.completionTokens + other.completionTokens,
+		totalTokens:      t.totalTokens + other.totalTokens,
+		cachedTokens:     t.cachedTokens + other.cachedTokens,
+		reasoningTokens:  t.reasoningTokens + other.reasoningTokens,
+	}
+}
+
+type RateLimitWindow struct {
+	limit     int
+	remaining int
+	reset     time.Duration
+}
+
+func NewRateLimitWindow(limit, remaining int, reset time.Duration) RateLimitWindow {
+	return RateLimitWindow{limit: limit, remaining: remaining, reset: reset}
+}
+
+func (w RateLimitWindow) Limit() int {
+	return w.limit
+}
+
+func (w RateLimitWindow) Remaining() int {
+	return w.remaining
+}
+
+func (w RateLimitWindow) Reset() time.Duration {
+	return w.reset
+}
+
+func (w RateLimitWindow) Known() bool {
+	return w.limit > 0
+}
+
+type RateLimit struct {
+	requests RateLimitWindow
+	tokens   RateLimitWindow
+}
+
+func NewRateLimit(requests, tokens RateLimitWindow) RateLimit {
+	return RateLimit{requests: requests, tokens: tokens}
+}
+
+func (l RateLimit) Requests() RateLimitWindow {
+	return l.requests
+}
+
+func (l RateLimit) Tokens() RateLimitWindow {
+	return l.tokens
+}
+
+func (l RateLimit) Known() bool {
+	return l.requests.Known() || l.tokens.Known()
+}
+
 func NewGenerateTextResult(textContent string, tokenUsage TokenUsage) GenerateTextResult {
 	return GenerateTextResult{
 		textContent: textContent,
@@ -45,3 +321,40 @@ func NewTokenUsage(promptTokens, completionTokens, totalTokens int) TokenUsage {
 		totalTokens:      totalTokens,
 	}
 }
+
+type StreamChunk struct {
+	textDelta string
+	result    *GenerateTextResult
+	err       error
+}
+
+func (c StreamChunk) TextDelta() string {
+	return c.textDelta
+}
+
+func (c StreamChunk) Done() bool {
+	return c.result != nil
+}
+
+func (c StreamChunk) Result() GenerateTextResult {
+	if c.result == nil {
+		return GenerateTextResult{}
+	}
+	return *c.result
+}
+
+func (c StreamChunk) Err() error {
+	return c.err
+}
+
+func NewStreamDelta(textDelta string) StreamChunk {
+	return StreamChunk{textDelta: textDelta}
+}
+
+func NewStreamResult(result GenerateTextResult) StreamChunk {
+	return StreamChunk{result: &result}
+}
+
+func NewStreamError(err error) StreamChunk {
+	return StreamChunk{err: err}
+}
@@ -1,6 +1,10 @@
 package types
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+)
 
 func TestTokenUsageAccessors(t *testing.T) {
 	usage := NewTokenUsage(10, 20, 30)
@@ -27,3 +31,89 @@ func TestGenerateTextResultAccessors(t *testing.T) {
 		t.Errorf("expected Usage PromptTokens 5, got %d", result.Usage().PromptTokens())
 	}
 }
+
+func TestTokenUsageAdd(t *testing.T) {
+	usage := NewTokenUsage(1, 2, 3).Add(NewTokenUsage(10, 20, 30))
+
+	if usage.PromptTokens() != 11 || usage.CompletionTokens() != 22 || usage.TotalTokens() != 33 {
+		t.Errorf("expected 11/22/33, got %d/%d/%d", usage.PromptTokens(), usage.CompletionTokens(), usage.TotalTokens())
+	}
+}
+
+func TestGenerateTextResultWith(t *testing.T) {
+	original := NewGenerateTextResult("", NewTokenUsage(1, 1, 2))
+	result := original.
+		WithToolCalls([]ToolCall{NewToolCall("call_1", "lookup", "{}")}).
+		WithUsage(NewTokenUsage(5, 5, 10))
+
+	if len(result.ToolCalls()) != 1 || result.ToolCalls()[0].ID() != "call_1" {
+		t.Errorf("expected tool call call_1, got %v", result.ToolCalls())
+	}
+	if result.Usage().TotalTokens() != 10 {
+		t.Errorf("expected 10 total tokens, got %d", result.Usage().TotalTokens())
+	}
+	if len(original.ToolCalls()) != 0 || original.Usage().TotalTokens() != 2 {
+		t.Error("expected original result to be unchanged")
+	}
+}
+
+func TestGenerateTextResultTruncated(t *testing.T) {
+	result := NewGenerateTextResult("Once upon", NewTokenUsage(1, 1, 2)).WithFinishReason(FinishReasonLength)
+	if !result.Truncated() {
+		t.Error("expected result with finish reason 'length' to be truncated")
+	}
+
+	result = result.WithFinishReason(FinishReasonStop)
+	if result.Truncated() {
+		t.Error("expected result with finish reason 'stop' not to be truncated")
+	}
+}
+
+func TestTokenUsageAddDetails(t *testing.T) {
+	usage := NewTokenUsage(1, 2, 3).WithCachedTokens(1).Add(NewTokenUsage(10, 20, 30).WithCachedTokens(4).WithReasoningTokens(5))
+
+	if usage.CachedTokens() != 5 || usage.ReasoningTokens() != 5 {
+		t.Errorf("expected 5 cached and 5 reasoning tokens, got %d and %d", usage.CachedTokens(), usage.ReasoningTokens())
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	if (RateLimit{}).Known() {
+		t.Error("expected zero rate limit to be unknown")
+	}
+
+	limit := NewRateLimit(NewRateLimitWindow(500, 499, 120*time.Millisecond), RateLimitWindow{})
+	result := NewGenerateTextResult("", NewTokenUsage(1, 1, 2)).WithRateLimit(limit)
+
+	got := result.RateLimit()
+	if !got.Known() || !got.Requests().Known() || got.Tokens().Known() {
+		t.Errorf("expected only the request window to be known, got %+v", got)
+	}
+	if got.Requests().Limit() != 500 || got.Requests().Remaining() != 499 || got.Requests().Reset() != 120*time.Millisecond {
+		t.Errorf("unexpected request window: %+v", got.Requests())
+	}
+}
+
+func TestStreamChunkAccessors(t *testing.T) {
+	delta := NewStreamDelta("Hel")
+	if delta.TextDelta() != "Hel" {
+		t.Errorf("expected TextDelta 'Hel', got '%s'", delta.TextDelta())
+	}
+	if delta.Done() {
+		t.Error("expected delta chunk not to be done")
+	}
+
+	final := NewStreamResult(NewGenerateTextResult("Hello", NewTokenUsage(1, 2, 3)))
+	if !final.Done() {
+		t.Error("expected result chunk to be done")
+	}
+	result := final.Result()
+	if result.TextContent() != "Hello" {
+		t.Errorf("expected Result TextContent 'Hello', got '%s'", result.TextContent())
+	}
+
+	failed := NewStreamError(errors.New("boom"))
+	if failed.Err() == nil || failed.Err().Error() != "boom" {
+		t.Errorf("expected Err 'boom', got %v", failed.Err())
+	}
+}